@@ -7,6 +7,8 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/rhajizada/signum/pkg/renderer"
 )
@@ -25,17 +27,171 @@ func main() {
 	}
 }
 
-func run(args []string, stdout io.Writer, getenv func(string) string) error {
+// options holds the parsed command line.
+type options struct {
+	showVersion bool
+	fontPath    string
+	subject     string
+	status      string
+	color       string
+	labelColor  string
+	textColor   string
+	darkLabel   string
+	darkColor   string
+	darkText    string
+	style       string
+	logo        string
+	logoColor   string
+	logoWidth   int
+	maxWidth    int
+	overflow    string
+	title       string
+	links       [2]string
+	idPrefix    string
+	scale       float64
+	templateDir string
+	textPaths   bool
+	kind        string
+	progress    float64
+	animation   string
+	fontDir     string
+	fontName    string
+	fontSize    float64
+	fontWeight  string
+	segments    segmentList
+	format      string
+	output      string
+}
+
+// segmentList collects the repeated -segment flag.
+type segmentList []renderer.Segment
+
+func (l *segmentList) String() string {
+	if l == nil {
+		return ""
+	}
+	texts := make([]string, len(*l))
+	for i, s := range *l {
+		texts[i] = s.Text
+	}
+	return strings.Join(texts, ", ")
+}
+
+func (l *segmentList) Set(value string) error {
+	*l = append(*l, renderer.ParseSegment(value))
+	return nil
+}
+
+func newFlagSet(stdout io.Writer, opts *options) *flag.FlagSet {
 	fs := flag.NewFlagSet("signum", flag.ContinueOnError)
 	fs.SetOutput(stdout)
 
-	showVersion := fs.Bool("version", false, "Print version and exit")
-	fontPath := fs.String("font", "", "Path to a .ttf font file (or set SIGNUM_FONT_PATH)")
-	subject := fs.String("subject", "", "Badge subject text")
-	status := fs.String("status", "", "Badge status text")
-	color := fs.String("color", "", "Badge color (named or hex)")
-	style := fs.String("style", "flat", "Badge style (flat, flat-square, plastic)")
-	output := fs.String("out", "", "Output SVG file path")
+	fs.BoolVar(&opts.showVersion, "version", false, "Print version and exit")
+	fs.StringVar(&opts.fontPath, "font", "", "Path to a .ttf, .otf, .ttc or .woff font file, with an optional #index face selector for collections, or a list of fallback fonts separated by the OS path list separator (or set SIGNUM_FONT_PATH). Default: built-in Verdana widths")
+	fs.StringVar(&opts.subject, "subject", "", "Badge subject text")
+	fs.StringVar(&opts.status, "status", "", "Badge status text")
+	fs.StringVar(&opts.color, "color", "", "Badge color (name, hex, rgb() or hsl())")
+	fs.StringVar(&opts.labelColor, "label-color", "", "Subject (left segment) color (name, hex, rgb() or hsl())")
+	fs.StringVar(&opts.textColor, "text-color", "", "Text color override (default light or dark to contrast with each segment)")
+	fs.StringVar(&opts.darkLabel, "dark-label-color", "", "Subject color when the reader prefers a dark color scheme")
+	fs.StringVar(&opts.darkColor, "dark-color", "", "Badge color when the reader prefers a dark color scheme")
+	fs.StringVar(&opts.darkText, "dark-text-color", "", "Text color override when the reader prefers a dark color scheme")
+	fs.StringVar(&opts.style, "style", "flat", "Badge style (flat, flat-square, plastic, for-the-badge, social)")
+	fs.StringVar(&opts.logo, "logo", "", "Embedded logo name or data:image/svg+xml;base64 URI")
+	fs.StringVar(&opts.logoColor, "logo-color", "", "Logo color for embedded logos (name, hex, rgb() or hsl())")
+	fs.IntVar(&opts.logoWidth, "logo-width", 0, "Logo width in pixels (default 14)")
+	fs.IntVar(&opts.maxWidth, "max-width", 0, "Maximum badge width in pixels (default unlimited)")
+	fs.StringVar(&opts.overflow, "overflow", "", "Overflow policy when -max-width is exceeded (truncate-end, truncate-middle, shrink)")
+	fs.StringVar(&opts.title, "title", "", "Accessible name for screen readers (default \"subject: status\")")
+	fs.StringVar(&opts.links[0], "subject-link", "", "Subject link target (http, https or mailto URL)")
+	fs.StringVar(&opts.links[1], "status-link", "", "Status link target (http, https or mailto URL)")
+	fs.StringVar(&opts.idPrefix, "id-prefix", "", "Prefix for element ids, to keep inline SVGs on one page apart")
+	fs.Float64Var(&opts.scale, "scale", 1, "Size multiplier, e.g. 2 for retina displays (max 8)")
+	fs.StringVar(&opts.templateDir, "templates", "", "Directory of custom *.svg.tmpl styles")
+	fs.BoolVar(&opts.textPaths, "text-paths", false, "Draw text as glyph outlines so the SVG looks the same without the font installed")
+	fs.StringVar(&opts.kind, "kind", "", "Badge kind (status, progress). Default: status")
+	fs.Float64Var(&opts.progress, "progress", 0, "Progress bar fill from 0 to 100 for -kind progress")
+	fs.StringVar(&opts.animation, "animation", "", "Badge animation (pulse, blink, spinner), still for readers who prefer reduced motion")
+	fs.StringVar(&opts.fontDir, "font-dir", "", "Directory of fonts to select with -font-name (or set SIGNUM_FONT_DIR)")
+	fs.StringVar(&opts.fontName, "font-name", "", "Family name of a font loaded with -font-dir, e.g. \"JetBrains Mono\"")
+	fs.Float64Var(&opts.fontSize, "font-size", 0, "Text size in pixels from 6 to 16 (default 11)")
+	fs.StringVar(&opts.fontWeight, "font-weight", "", "Text weight (regular, bold)")
+	fs.Var(&opts.segments, "segment", "Segment as text|color|link|logo, e.g. \"linux|blue\"; repeat for up to 8 segments replacing -subject and -status")
+	fs.StringVar(&opts.format, "format", "svg", "Output format (svg, png)")
+	fs.StringVar(&opts.output, "out", "", "Output file path")
+	return fs
+}
+
+func (o options) validate() error {
+	segmented := len(o.segments) > 0
+	if segmented && (o.subject != "" || o.status != "") {
+		return errors.New("-segment replaces -subject and -status")
+	}
+	if o.subject == "" && !segmented {
+		return errors.New("subject is required")
+	}
+	if !renderer.Kind(o.kind).IsValid() {
+		return fmt.Errorf("invalid kind: %q", o.kind)
+	}
+	if o.status == "" && renderer.Kind(o.kind) != renderer.KindProgress && !segmented {
+		return errors.New("status is required")
+	}
+	if o.color == "" && (!segmented || renderer.SegmentsUseColor(o.segments)) {
+		return errors.New("color is required")
+	}
+	if err := renderer.Logo(o.logo).Validate(); err != nil {
+		return fmt.Errorf("invalid logo: %w", err)
+	}
+	if !renderer.Animation(o.animation).IsValid() {
+		return fmt.Errorf("invalid animation: %q", o.animation)
+	}
+	if !renderer.FontWeight(o.fontWeight).IsValid() {
+		return fmt.Errorf("invalid font weight: %q", o.fontWeight)
+	}
+	if !renderer.ValidFontSize(o.fontSize) {
+		return fmt.Errorf("invalid font size: %v", o.fontSize)
+	}
+	if !renderer.Format(o.format).IsValid() {
+		return fmt.Errorf("invalid format: %q", o.format)
+	}
+	return nil
+}
+
+func (o options) badge() renderer.Badge {
+	return renderer.Badge{
+		Subject:    o.subject,
+		Status:     o.status,
+		Color:      renderer.Color(o.color),
+		Style:      renderer.Style(o.style),
+		LabelColor: renderer.Color(o.labelColor),
+		TextColor:  renderer.Color(o.textColor),
+		Dark: renderer.Palette{
+			LabelColor: renderer.Color(o.darkLabel),
+			Color:      renderer.Color(o.darkColor),
+			TextColor:  renderer.Color(o.darkText),
+		},
+		Logo:       renderer.Logo(o.logo),
+		LogoColor:  renderer.Color(o.logoColor),
+		LogoWidth:  o.logoWidth,
+		MaxWidth:   o.maxWidth,
+		Overflow:   renderer.Overflow(o.overflow),
+		Title:      o.title,
+		Links:      o.links,
+		IDPrefix:   o.idPrefix,
+		Scale:      o.scale,
+		Kind:       renderer.Kind(o.kind),
+		Progress:   o.progress,
+		Animation:  renderer.Animation(o.animation),
+		Font:       o.fontName,
+		FontSize:   o.fontSize,
+		FontWeight: renderer.FontWeight(o.fontWeight),
+		Segments:   o.segments,
+	}
+}
+
+func run(args []string, stdout io.Writer, getenv func(string) string) error {
+	var opts options
+	fs := newFlagSet(stdout, &opts)
 
 	if len(args) == 0 {
 		fs.Usage()
@@ -45,48 +201,49 @@ func run(args []string, stdout io.Writer, getenv func(string) string) error {
 		return err
 	}
 
-	if *showVersion {
+	if opts.showVersion {
 		_, err := fmt.Fprintln(stdout, Version)
 		return err
 	}
 
-	if *fontPath == "" {
-		*fontPath = getenv("SIGNUM_FONT_PATH")
+	if opts.fontPath == "" {
+		opts.fontPath = getenv("SIGNUM_FONT_PATH")
 	}
-	if *fontPath == "" {
-		return errors.New("font is required (set -font or SIGNUM_FONT_PATH)")
+	if opts.fontDir == "" {
+		opts.fontDir = getenv("SIGNUM_FONT_DIR")
 	}
-	if *subject == "" {
-		return errors.New("subject is required")
-	}
-	if *status == "" {
-		return errors.New("status is required")
-	}
-	if *color == "" {
-		return errors.New("color is required")
-	}
-
-	badgeStyle := renderer.Style(*style)
-	if !badgeStyle.IsValid() {
-		return fmt.Errorf("invalid style: %q", *style)
+	if err := opts.validate(); err != nil {
+		return err
 	}
 
-	r, err := renderer.NewRenderer(*fontPath)
+	r, err := newRenderer(opts.fontPath)
 	if err != nil {
 		return fmt.Errorf("init renderer: %w", err)
 	}
+	if opts.templateDir != "" {
+		if err = r.LoadStyleDir(opts.templateDir); err != nil {
+			return fmt.Errorf("load templates: %w", err)
+		}
+	}
+	if opts.fontDir != "" {
+		if err = r.LoadFontDir(opts.fontDir); err != nil {
+			return fmt.Errorf("load fonts: %w", err)
+		}
+	}
+	r.SetTextPaths(opts.textPaths)
+	if !r.HasStyle(renderer.Style(opts.style)) {
+		return fmt.Errorf("invalid style: %q", opts.style)
+	}
+	if opts.fontName != "" && !r.HasFont(opts.fontName, renderer.FontWeight(opts.fontWeight)) {
+		return fmt.Errorf("unknown font: %q (available: %s)", opts.fontName, strings.Join(r.Fonts(), ", "))
+	}
 
-	outputBytes, err := r.Render(renderer.Badge{
-		Subject: *subject,
-		Status:  *status,
-		Color:   renderer.Color(*color),
-		Style:   badgeStyle,
-	})
+	outputBytes, err := render(r, renderer.Format(opts.format), opts.badge())
 	if err != nil {
 		return fmt.Errorf("render badge: %w", err)
 	}
 
-	if *output == "" {
+	if opts.output == "" {
 		_, err = stdout.Write(outputBytes)
 		if err != nil {
 			return fmt.Errorf("write stdout: %w", err)
@@ -94,8 +251,24 @@ func run(args []string, stdout io.Writer, getenv func(string) string) error {
 		return nil
 	}
 
-	if err = os.WriteFile(*output, outputBytes, 0o600); err != nil {
+	if err = os.WriteFile(opts.output, outputBytes, 0o600); err != nil {
 		return fmt.Errorf("write output: %w", err)
 	}
 	return nil
 }
+
+func render(r *renderer.Renderer, format renderer.Format, b renderer.Badge) ([]byte, error) {
+	if format == renderer.FormatPNG {
+		return r.RenderPNG(b, 1)
+	}
+	return r.Render(b)
+}
+
+// newRenderer loads the font chain in fontPath, falling back to the built-in Verdana widths.
+func newRenderer(fontPath string) (*renderer.Renderer, error) {
+	fontPaths := filepath.SplitList(fontPath)
+	if len(fontPaths) == 0 {
+		return renderer.NewVerdanaRenderer()
+	}
+	return renderer.NewRendererWithFontPaths(fontPaths...)
+}
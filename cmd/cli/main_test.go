@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"image/png"
 	"os"
 	"path/filepath"
 	"strings"
@@ -44,15 +45,18 @@ func TestRunVersion(t *testing.T) {
 	}
 }
 
-func TestRunMissingFont(t *testing.T) {
+func TestRunWithoutFontUsesVerdana(t *testing.T) {
 	var out bytes.Buffer
 	err := run(
 		[]string{"-subject", "build", "-status", "passing", "-color", "green"},
 		&out,
 		func(string) string { return "" },
 	)
-	if err == nil || !strings.Contains(err.Error(), "font is required") {
-		t.Fatalf("expected font error, got %v", err)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), `width="95"`) {
+		t.Fatalf("expected verdana widths in svg output, got %q", out.String())
 	}
 }
 
@@ -116,7 +120,12 @@ func TestRunUsesEnvFontPath(t *testing.T) {
 		"-subject", "build",
 		"-status", "passing",
 		"-color", "green",
-	}, &out, func(string) string { return fontPath }); err != nil {
+	}, &out, func(key string) string {
+		if key == "SIGNUM_FONT_PATH" {
+			return fontPath
+		}
+		return ""
+	}); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if !strings.Contains(out.String(), "<svg") {
@@ -163,6 +172,122 @@ func TestRunMissingColor(t *testing.T) {
 	}
 }
 
+func TestRunRendersLogo(t *testing.T) {
+	fontPath := writeTempFont(t)
+	var out bytes.Buffer
+	if err := run([]string{
+		"-font", fontPath,
+		"-subject", "build",
+		"-status", "passing",
+		"-color", "green",
+		"-logo", "check",
+		"-logo-color", "yellow",
+	}, &out, func(string) string { return "" }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "<image") {
+		t.Fatalf("expected logo in svg output, got %q", out.String())
+	}
+}
+
+func TestRunFontFallbackChain(t *testing.T) {
+	fontPath := writeTempFont(t)
+	var out bytes.Buffer
+	if err := run([]string{
+		"-font", fontPath + string(os.PathListSeparator) + fontPath,
+		"-subject", "build",
+		"-status", "passing",
+		"-color", "green",
+	}, &out, func(string) string { return "" }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), `font-family="Go,`) {
+		t.Fatalf("expected font family list in svg output, got %q", out.String())
+	}
+}
+
+func TestRunRendersForTheBadge(t *testing.T) {
+	fontPath := writeTempFont(t)
+	var out bytes.Buffer
+	if err := run([]string{
+		"-font", fontPath,
+		"-subject", "build",
+		"-status", "passing",
+		"-color", "green",
+		"-style", "for-the-badge",
+	}, &out, func(string) string { return "" }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), ">PASSING<") {
+		t.Fatalf("expected for-the-badge output, got %q", out.String())
+	}
+}
+
+func TestRunMaxWidth(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{
+		"-subject", "branch",
+		"-status", "feature/a-very-long-branch-name",
+		"-color", "blue",
+		"-max-width", "100",
+		"-overflow", "truncate-end",
+	}, &out, func(string) string { return "" }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), `width="97"`) {
+		t.Fatalf("expected capped width in svg output, got %q", out.String())
+	}
+	if !strings.Contains(out.String(), ">feat…<") {
+		t.Fatalf("expected truncated status in svg output, got %q", out.String())
+	}
+}
+
+func TestRunCustomTemplates(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := `<svg width="{{.Bounds.Dx}}"><text>{{.Subject}}/{{.Status}}</text></svg>`
+	if err := os.WriteFile(filepath.Join(dir, "corporate.svg.tmpl"), []byte(tmpl), 0o600); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	var out bytes.Buffer
+	if err := run([]string{
+		"-templates", dir,
+		"-subject", "build",
+		"-status", "passing",
+		"-color", "green",
+		"-style", "corporate",
+	}, &out, func(string) string { return "" }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "<text>build/passing</text>") {
+		t.Fatalf("expected custom template output, got %q", out.String())
+	}
+
+	err := run([]string{
+		"-templates", t.TempDir(),
+		"-subject", "build",
+		"-status", "passing",
+		"-color", "green",
+	}, &out, func(string) string { return "" })
+	if err == nil || !strings.Contains(err.Error(), "load templates") {
+		t.Fatalf("expected template dir error, got %v", err)
+	}
+}
+
+func TestRunInvalidLogo(t *testing.T) {
+	fontPath := writeTempFont(t)
+	var out bytes.Buffer
+	err := run([]string{
+		"-font", fontPath,
+		"-subject", "build",
+		"-status", "passing",
+		"-color", "green",
+		"-logo", "nope",
+	}, &out, func(string) string { return "" })
+	if err == nil || !strings.Contains(err.Error(), "invalid logo") {
+		t.Fatalf("expected logo error, got %v", err)
+	}
+}
+
 func TestRunUnknownFlag(t *testing.T) {
 	var out bytes.Buffer
 	if err := run([]string{"-nope"}, &out, func(string) string { return "" }); err == nil {
@@ -190,3 +315,239 @@ func TestRunStdoutWriteFailure(t *testing.T) {
 		t.Fatalf("expected stdout error, got %v", err)
 	}
 }
+
+func TestRunTextColor(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{
+		"-subject", "build",
+		"-status", "passing",
+		"-color", "yellow",
+		"-text-color", "#000",
+	}, &out, func(string) string { return "" }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), `fill="#000">passing<`) {
+		t.Fatalf("expected text color in svg output, got %q", out.String())
+	}
+}
+
+func TestRunDarkPalette(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{
+		"-subject", "build",
+		"-status", "passing",
+		"-color", "green",
+		"-dark-color", "#050",
+		"-dark-label-color", "#111",
+	}, &out, func(string) string { return "" }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"@media (prefers-color-scheme:dark)", " .status{fill:#050}", " .subject{fill:#111}"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in svg output, got %q", want, out.String())
+		}
+	}
+	if err := run([]string{
+		"-subject", "build",
+		"-status", "passing",
+		"-color", "green",
+		"-dark-color", "nope",
+	}, &out, func(string) string { return "" }); err == nil {
+		t.Fatalf("expected invalid dark color error")
+	}
+}
+
+func TestRunAnimation(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{
+		"-subject", "deploy",
+		"-status", "running",
+		"-color", "blue",
+		"-animation", "spinner",
+	}, &out, func(string) string { return "" }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"@media (prefers-reduced-motion:no-preference)", `class="spinner"`} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in svg output, got %q", want, out.String())
+		}
+	}
+	if err := run([]string{
+		"-subject", "deploy",
+		"-status", "running",
+		"-color", "blue",
+		"-animation", "spin",
+	}, &out, func(string) string { return "" }); err == nil {
+		t.Fatalf("expected invalid animation error")
+	}
+}
+
+func TestRunFontDir(t *testing.T) {
+	dir := filepath.Dir(writeTempFont(t))
+	getenv := func(key string) string {
+		if key == "SIGNUM_FONT_DIR" {
+			return dir
+		}
+		return ""
+	}
+	var out bytes.Buffer
+	if err := run([]string{
+		"-subject", "release",
+		"-status", "v1.2.3",
+		"-color", "blue",
+		"-font-name", "go",
+		"-font-size", "12",
+		"-font-weight", "bold",
+	}, &out, getenv); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{`font-family="Go,`, `font-size="12" font-weight="bold"`} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in svg output, got %q", want, out.String())
+		}
+	}
+
+	for _, args := range [][]string{
+		{"-font-name", "Fira Code"},
+		{"-font-size", "40"},
+		{"-font-weight", "heavy"},
+	} {
+		args = append(args, "-subject", "release", "-status", "v1.2.3", "-color", "blue")
+		if err := run(args, &out, getenv); err == nil {
+			t.Fatalf("%v: expected error", args)
+		}
+	}
+}
+
+func TestRunTextPaths(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{
+		"-subject", "build",
+		"-status", "passing",
+		"-color", "green",
+		"-text-paths",
+	}, &out, func(string) string { return "" }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(out.String(), "<text") || !strings.Contains(out.String(), `<path transform="translate(`) {
+		t.Fatalf("expected text drawn as paths, got %q", out.String())
+	}
+}
+
+func TestRunScale(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{
+		"-subject", "build",
+		"-status", "passing",
+		"-color", "green",
+		"-scale", "2",
+	}, &out, func(string) string { return "" }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), `height="40" viewBox="0 0 `) {
+		t.Fatalf("expected scaled svg output, got %q", out.String())
+	}
+}
+
+func TestRunPNG(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{
+		"-subject", "build",
+		"-status", "passing",
+		"-color", "green",
+		"-scale", "2",
+		"-format", "png",
+	}, &out, func(string) string { return "" }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	img, err := png.Decode(&out)
+	if err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+	if got := img.Bounds().Dy(); got != 40 {
+		t.Fatalf("expected scaled png height 40, got %d", got)
+	}
+	// Laid out with the Verdana widths of the SVG, drawn with Go Regular.
+	if got := img.Bounds().Dx(); got != 190 {
+		t.Fatalf("expected scaled png width 190, got %d", got)
+	}
+}
+
+func TestRunInvalidFormat(t *testing.T) {
+	var out bytes.Buffer
+	err := run([]string{
+		"-subject", "build",
+		"-status", "passing",
+		"-color", "green",
+		"-format", "gif",
+	}, &out, func(string) string { return "" })
+	if err == nil || !strings.Contains(err.Error(), "invalid format") {
+		t.Fatalf("expected invalid format error, got %v", err)
+	}
+}
+
+func TestRunProgress(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{
+		"-subject", "coverage",
+		"-color", "green",
+		"-kind", "progress",
+		"-progress", "73",
+	}, &out, func(string) string { return "" }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), ">73%</text>") || !strings.Contains(out.String(), `fill="#9f9f9f"`) {
+		t.Fatalf("expected a progress bar: %s", out.String())
+	}
+	err := run([]string{
+		"-subject", "coverage",
+		"-color", "green",
+		"-kind", "gauge",
+	}, &out, func(string) string { return "" })
+	if err == nil || !strings.Contains(err.Error(), "invalid kind") {
+		t.Fatalf("expected invalid kind error, got %v", err)
+	}
+}
+
+func TestRunSegments(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{
+		"-segment", "build",
+		"-segment", "linux|blue|https://example.com",
+		"-segment", "passing",
+		"-color", "green",
+	}, &out, func(string) string { return "" }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{`aria-label="build: linux: passing"`, `fill="#007ec6"`, `xlink:href="https://example.com"`} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in svg output, got %q", want, out.String())
+		}
+	}
+	err := run([]string{
+		"-subject", "build",
+		"-segment", "linux",
+		"-color", "green",
+	}, &out, func(string) string { return "" })
+	if err == nil || !strings.Contains(err.Error(), "-segment replaces") {
+		t.Fatalf("expected segment conflict error, got %v", err)
+	}
+	if err = run([]string{
+		"-segment", "linux|nope",
+		"-color", "green",
+	}, &out, func(string) string { return "" }); err == nil {
+		t.Fatalf("expected invalid segment color error")
+	}
+	if err = run([]string{
+		"-segment", "build",
+		"-segment", "linux|blue",
+	}, &out, func(string) string { return "" }); err != nil {
+		t.Fatalf("expected colored segments to need no -color, got %v", err)
+	}
+	if err = run([]string{
+		"-segment", "build|blue",
+		"-segment", "linux",
+	}, &out, func(string) string { return "" }); err == nil || !strings.Contains(err.Error(), "color is required") {
+		t.Fatalf("expected color to be required, got %v", err)
+	}
+}
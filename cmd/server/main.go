@@ -73,8 +73,11 @@ func runServer(logger *slog.Logger) error {
 		return fmt.Errorf("load config: %w", err)
 	}
 
-	if err = validateFontPath(cfg.FontPath); err != nil {
-		return err
+	fontPaths := cfg.FontPaths()
+	for _, fontPath := range fontPaths {
+		if err = validateFontPath(fontPath); err != nil {
+			return err
+		}
 	}
 
 	db, err := openDB(context.Background(), cfg.Postgres)
@@ -91,10 +94,21 @@ func runServer(logger *slog.Logger) error {
 		return err
 	}
 
-	rdr, err := renderer.NewRenderer(cfg.FontPath)
+	rdr, err := newRenderer(fontPaths)
 	if err != nil {
 		return fmt.Errorf("init renderer: %w", err)
 	}
+	if cfg.TemplateDir != "" {
+		if err = rdr.LoadStyleDir(cfg.TemplateDir); err != nil {
+			return fmt.Errorf("load templates: %w", err)
+		}
+	}
+	if cfg.FontDir != "" {
+		if err = rdr.LoadFontDir(cfg.FontDir); err != nil {
+			return fmt.Errorf("load fonts: %w", err)
+		}
+	}
+	rdr.SetTextPaths(cfg.TextPaths)
 
 	tokenManager, err := service.NewTokenManager(cfg.SecretKey)
 	if err != nil {
@@ -129,10 +143,19 @@ func runServer(logger *slog.Logger) error {
 	return serve(logger, srv)
 }
 
+// newRenderer loads the configured font chain, falling back to the built-in Verdana widths.
+func newRenderer(fontPaths []string) (*renderer.Renderer, error) {
+	if len(fontPaths) == 0 {
+		return renderer.NewVerdanaRenderer()
+	}
+	return renderer.NewRendererWithFontPaths(fontPaths...)
+}
+
 func validateFontPath(path string) error {
 	if path == "" {
 		return errors.New("font path is required")
 	}
+	path, _ = renderer.SplitFontIndex(path)
 	info, err := os.Stat(path)
 	if err != nil {
 		return fmt.Errorf("font path is invalid: %w", err)
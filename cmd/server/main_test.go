@@ -50,6 +50,9 @@ func TestValidateFontPath(t *testing.T) {
 	if err := validateFontPath(path); err != nil {
 		t.Fatalf("expected valid font path, got %v", err)
 	}
+	if err := validateFontPath(path + "#1"); err != nil {
+		t.Fatalf("expected valid font path with a face index, got %v", err)
+	}
 }
 
 func TestRunServerInvalidFontPath(t *testing.T) {
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE badges
+    ADD COLUMN logo TEXT NOT NULL DEFAULT '',
+    ADD COLUMN logo_color TEXT NOT NULL DEFAULT '',
+    ADD COLUMN logo_width INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE badges
+    DROP COLUMN logo,
+    DROP COLUMN logo_color,
+    DROP COLUMN logo_width;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE badges
+    ADD COLUMN max_width INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN overflow TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE badges
+    DROP COLUMN max_width,
+    DROP COLUMN overflow;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE badges
+    ADD COLUMN text_color TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE badges
+    DROP COLUMN text_color;
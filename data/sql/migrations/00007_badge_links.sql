@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE badges
+    ADD COLUMN subject_link TEXT NOT NULL DEFAULT '',
+    ADD COLUMN status_link TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE badges
+    DROP COLUMN subject_link,
+    DROP COLUMN status_link;
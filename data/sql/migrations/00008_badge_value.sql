@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE badges
+    ADD COLUMN value DOUBLE PRECISION,
+    ADD COLUMN unit TEXT NOT NULL DEFAULT '',
+    ADD COLUMN value_format TEXT NOT NULL DEFAULT '',
+    ADD COLUMN color_scale TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE badges
+    DROP COLUMN value,
+    DROP COLUMN unit,
+    DROP COLUMN value_format,
+    DROP COLUMN color_scale;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE badges
+    ADD COLUMN kind TEXT NOT NULL DEFAULT '',
+    ADD COLUMN progress DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE badges
+    DROP COLUMN kind,
+    DROP COLUMN progress;
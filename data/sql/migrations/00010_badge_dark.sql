@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE badges
+    ADD COLUMN dark_label_color TEXT NOT NULL DEFAULT '',
+    ADD COLUMN dark_color TEXT NOT NULL DEFAULT '',
+    ADD COLUMN dark_text_color TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE badges
+    DROP COLUMN dark_label_color,
+    DROP COLUMN dark_color,
+    DROP COLUMN dark_text_color;
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE badges
+    ADD COLUMN font TEXT NOT NULL DEFAULT '',
+    ADD COLUMN font_size DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN font_weight TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE badges
+    DROP COLUMN font,
+    DROP COLUMN font_size,
+    DROP COLUMN font_weight;
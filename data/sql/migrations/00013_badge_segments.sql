@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE badges
+    ADD COLUMN segments JSONB NOT NULL DEFAULT '[]';
+
+-- +goose Down
+ALTER TABLE badges
+    DROP COLUMN segments;
@@ -4,14 +4,38 @@ INSERT INTO badges (
     subject,
     status,
     color,
-    style
+    style,
+    logo,
+    logo_color,
+    logo_width,
+    label_color,
+    max_width,
+    overflow,
+    text_color,
+    title,
+    subject_link,
+    status_link,
+    value,
+    unit,
+    value_format,
+    color_scale,
+    kind,
+    progress,
+    dark_label_color,
+    dark_color,
+    dark_text_color,
+    animation,
+    font,
+    font_size,
+    font_weight,
+    segments
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29
 )
-RETURNING id, token_hash, subject, status, color, style, created_at, updated_at;
+RETURNING id, token_hash, subject, status, color, style, created_at, updated_at, logo, logo_color, logo_width, label_color, max_width, overflow, text_color, title, subject_link, status_link, value, unit, value_format, color_scale, kind, progress, dark_label_color, dark_color, dark_text_color, animation, font, font_size, font_weight, segments;
 
 -- name: GetBadgeByID :one
-SELECT id, token_hash, subject, status, color, style, created_at, updated_at
+SELECT id, token_hash, subject, status, color, style, created_at, updated_at, logo, logo_color, logo_width, label_color, max_width, overflow, text_color, title, subject_link, status_link, value, unit, value_format, color_scale, kind, progress, dark_label_color, dark_color, dark_text_color, animation, font, font_size, font_weight, segments
 FROM badges
 WHERE id = $1;
 
@@ -21,9 +45,33 @@ SET subject = $2,
     status = $3,
     color = $4,
     style = $5,
+    logo = $6,
+    logo_color = $7,
+    logo_width = $8,
+    label_color = $9,
+    max_width = $10,
+    overflow = $11,
+    text_color = $12,
+    title = $13,
+    subject_link = $14,
+    status_link = $15,
+    value = $16,
+    unit = $17,
+    value_format = $18,
+    color_scale = $19,
+    kind = $20,
+    progress = $21,
+    dark_label_color = $22,
+    dark_color = $23,
+    dark_text_color = $24,
+    animation = $25,
+    font = $26,
+    font_size = $27,
+    font_weight = $28,
+    segments = $29,
     updated_at = now()
 WHERE id = $1
-RETURNING id, token_hash, subject, status, color, style, created_at, updated_at;
+RETURNING id, token_hash, subject, status, color, style, created_at, updated_at, logo, logo_color, logo_width, label_color, max_width, overflow, text_color, title, subject_link, status_link, value, unit, value_format, color_scale, kind, progress, dark_label_color, dark_color, dark_text_color, animation, font, font_size, font_weight, segments;
 
 -- name: DeleteBadge :exec
 DELETE FROM badges
@@ -76,9 +76,10 @@ const docTemplate = `{
         },
         "/api/badges/live": {
             "get": {
-                "description": "Renders an SVG badge for the provided parameters.",
+                "description": "Renders an SVG badge for the provided parameters, or a PNG for format=png or Accept: image/png.",
                 "produces": [
-                    "text/plain"
+                    "text/plain",
+                    "image/png"
                 ],
                 "tags": [
                     "Badges"
@@ -87,35 +88,172 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Left-hand subject text",
+                        "description": "Left-hand subject text. Required unless segment is set",
                         "name": "subject",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Right-hand status text",
+                        "description": "Right-hand status text. Required unless kind is progress or segment is set",
                         "name": "status",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Segment as text|color|link|logo, replacing subject and status; repeat for up to 8 segments",
+                        "name": "segment",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Badge color (named or hex)",
+                        "description": "Badge color (name, hex, rgb() or hsl()). Required unless every segment after the first has a color",
                         "name": "color",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Badge style (flat, flat-square, plastic). Default: flat",
+                        "description": "Badge style (flat, flat-square, plastic, for-the-badge, social). Default: flat",
                         "name": "style",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subject (left segment) color (name, hex, rgb() or hsl())",
+                        "name": "label_color",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text color override. Default: light or dark to contrast with each segment",
+                        "name": "text_color",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subject color when the reader prefers a dark color scheme",
+                        "name": "dark_label_color",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Badge color when the reader prefers a dark color scheme",
+                        "name": "dark_color",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text color override when the reader prefers a dark color scheme",
+                        "name": "dark_text_color",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Badge animation (pulse, blink, spinner), still for readers who prefer reduced motion",
+                        "name": "animation",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a font loaded from the server font directory",
+                        "name": "font",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Text size in pixels from 6 to 16. Default: 11",
+                        "name": "font_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text weight (regular, bold). Default: regular",
+                        "name": "font_weight",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Embedded logo name or data:image/svg+xml;base64 URI",
+                        "name": "logo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Logo color for embedded logos (name, hex, rgb() or hsl())",
+                        "name": "logo_color",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Logo width in pixels. Default: 14",
+                        "name": "logo_width",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum badge width in pixels. Default: unlimited",
+                        "name": "max_width",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Overflow policy when max_width is exceeded (truncate-end, truncate-middle, shrink). Default: truncate-end",
+                        "name": "overflow",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Accessible name for screen readers. Default: subject: status, or the segment texts",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Badge kind (status, progress). Default: status",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Progress bar fill from 0 to 100 for kind=progress (flat, flat-square and plastic styles)",
+                        "name": "progress",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Subject link, then status link (http, https or mailto)",
+                        "name": "link",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Prefix for element ids, to keep inline SVGs on one page apart (letters, digits, - and _)",
+                        "name": "id_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Size multiplier up to 8. Default: 1",
+                        "name": "scale",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Output format (svg, png). Default: negotiated from Accept, else svg",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "SVG image",
+                        "description": "SVG or PNG image",
                         "schema": {
                             "type": "string"
                         }
@@ -149,9 +287,10 @@ const docTemplate = `{
         },
         "/api/badges/{id}": {
             "get": {
-                "description": "Returns an SVG badge for the stored definition.",
+                "description": "Returns an SVG badge for the stored definition, or a PNG for a .png id suffix, format=png or Accept: image/png.",
                 "produces": [
-                    "text/plain"
+                    "text/plain",
+                    "image/png"
                 ],
                 "tags": [
                     "Badges"
@@ -160,15 +299,33 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Badge ID",
+                        "description": "Badge ID, optionally with a .png suffix",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Size multiplier up to 8. Default: 1",
+                        "name": "scale",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Prefix for element ids, to keep inline SVGs on one page apart (letters, digits, - and _)",
+                        "name": "id_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Output format (svg, png). Default: negotiated from Accept, else svg",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "SVG image",
+                        "description": "SVG or PNG image",
                         "schema": {
                             "type": "string"
                         }
@@ -411,15 +568,76 @@ const docTemplate = `{
         "Badge": {
             "type": "object",
             "properties": {
+                "animation": {
+                    "type": "string"
+                },
                 "color": {
                     "type": "string"
                 },
+                "color_scale": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
+                "dark_color": {
+                    "type": "string"
+                },
+                "dark_label_color": {
+                    "description": "Dark colors apply when the reader prefers a dark color scheme.",
+                    "type": "string"
+                },
+                "dark_text_color": {
+                    "type": "string"
+                },
+                "font": {
+                    "type": "string"
+                },
+                "font_size": {
+                    "type": "number"
+                },
+                "font_weight": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
+                "kind": {
+                    "type": "string"
+                },
+                "label_color": {
+                    "type": "string"
+                },
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "logo": {
+                    "type": "string"
+                },
+                "logo_color": {
+                    "type": "string"
+                },
+                "logo_width": {
+                    "type": "integer"
+                },
+                "max_width": {
+                    "type": "integer"
+                },
+                "overflow": {
+                    "type": "string"
+                },
+                "progress": {
+                    "type": "number"
+                },
+                "segments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Segment"
+                    }
+                },
                 "status": {
                     "type": "string"
                 },
@@ -429,17 +647,97 @@ const docTemplate = `{
                 "subject": {
                     "type": "string"
                 },
+                "text_color": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
                 "updated_at": {
                     "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                },
+                "value_format": {
+                    "type": "string"
                 }
             }
         },
         "CreateBadgeRequest": {
             "type": "object",
             "properties": {
+                "animation": {
+                    "description": "Animation is pulse, blink or spinner. Empty draws a still badge.",
+                    "type": "string"
+                },
                 "color": {
                     "type": "string"
                 },
+                "color_scale": {
+                    "type": "string"
+                },
+                "dark_color": {
+                    "type": "string"
+                },
+                "dark_label_color": {
+                    "description": "Dark colors repaint the badge for readers who prefer a dark color\nscheme. Empty keeps the light color.",
+                    "type": "string"
+                },
+                "dark_text_color": {
+                    "type": "string"
+                },
+                "font": {
+                    "description": "Font names a font loaded from the server font directory, drawn at\nFontSize pixels from 6 to 16 and FontWeight regular or bold. Empty\nkeeps the default 11px regular text.",
+                    "type": "string"
+                },
+                "font_size": {
+                    "type": "number"
+                },
+                "font_weight": {
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "Kind is status or progress. Progress badges fill in proportion to\nProgress, from 0 to 100, which follows Value when one is set.",
+                    "type": "string"
+                },
+                "label_color": {
+                    "type": "string"
+                },
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "logo": {
+                    "type": "string"
+                },
+                "logo_color": {
+                    "type": "string"
+                },
+                "logo_width": {
+                    "type": "integer"
+                },
+                "max_width": {
+                    "type": "integer"
+                },
+                "overflow": {
+                    "type": "string"
+                },
+                "progress": {
+                    "type": "number"
+                },
+                "segments": {
+                    "description": "Segments replace subject and status with up to 8 parts, each with its\nown color, link and logo. Subject, status, value, logo and links must\nthen be empty.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Segment"
+                    }
+                },
                 "status": {
                     "type": "string"
                 },
@@ -448,21 +746,98 @@ const docTemplate = `{
                 },
                 "subject": {
                     "type": "string"
+                },
+                "text_color": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Value sets the status and color from the value; see ColorScale.",
+                    "type": "number"
+                },
+                "value_format": {
+                    "type": "string"
                 }
             }
         },
         "CreateBadgeResponse": {
             "type": "object",
             "properties": {
+                "animation": {
+                    "type": "string"
+                },
                 "color": {
                     "type": "string"
                 },
+                "color_scale": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
+                "dark_color": {
+                    "type": "string"
+                },
+                "dark_label_color": {
+                    "description": "Dark colors apply when the reader prefers a dark color scheme.",
+                    "type": "string"
+                },
+                "dark_text_color": {
+                    "type": "string"
+                },
+                "font": {
+                    "type": "string"
+                },
+                "font_size": {
+                    "type": "number"
+                },
+                "font_weight": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
+                "kind": {
+                    "type": "string"
+                },
+                "label_color": {
+                    "type": "string"
+                },
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "logo": {
+                    "type": "string"
+                },
+                "logo_color": {
+                    "type": "string"
+                },
+                "logo_width": {
+                    "type": "integer"
+                },
+                "max_width": {
+                    "type": "integer"
+                },
+                "overflow": {
+                    "type": "string"
+                },
+                "progress": {
+                    "type": "number"
+                },
+                "segments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Segment"
+                    }
+                },
                 "status": {
                     "type": "string"
                 },
@@ -472,20 +847,99 @@ const docTemplate = `{
                 "subject": {
                     "type": "string"
                 },
+                "text_color": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
                 "token": {
                     "type": "string"
                 },
+                "unit": {
+                    "type": "string"
+                },
                 "updated_at": {
                     "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                },
+                "value_format": {
+                    "type": "string"
                 }
             }
         },
         "PatchBadgeRequest": {
             "type": "object",
             "properties": {
+                "animation": {
+                    "description": "Animation is cleared with an empty string.",
+                    "type": "string"
+                },
                 "color": {
                     "type": "string"
                 },
+                "color_scale": {
+                    "type": "string"
+                },
+                "dark_color": {
+                    "type": "string"
+                },
+                "dark_label_color": {
+                    "description": "Dark colors are patched one by one; an empty string removes one.",
+                    "type": "string"
+                },
+                "dark_text_color": {
+                    "type": "string"
+                },
+                "font": {
+                    "description": "Font settings are reset with an empty string and zero.",
+                    "type": "string"
+                },
+                "font_size": {
+                    "type": "number"
+                },
+                "font_weight": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "label_color": {
+                    "type": "string"
+                },
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "logo": {
+                    "type": "string"
+                },
+                "logo_color": {
+                    "type": "string"
+                },
+                "logo_width": {
+                    "type": "integer"
+                },
+                "max_width": {
+                    "type": "integer"
+                },
+                "overflow": {
+                    "type": "string"
+                },
+                "progress": {
+                    "type": "number"
+                },
+                "segments": {
+                    "description": "Segments replace the segments and clear subject, status, value, logo\nand links. Patching subject or status clears the segments.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Segment"
+                    }
+                },
                 "status": {
                     "type": "string"
                 },
@@ -494,6 +948,40 @@ const docTemplate = `{
                 },
                 "subject": {
                     "type": "string"
+                },
+                "text_color": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Value updates the status and color. Patching status or color without\na value clears it.",
+                    "type": "number"
+                },
+                "value_format": {
+                    "type": "string"
+                }
+            }
+        },
+        "Segment": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "description": "Color fills the segment. Empty uses the label color for the first\nsegment and the badge color for the others.",
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "logo": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
                 }
             }
         }
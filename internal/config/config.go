@@ -2,6 +2,7 @@ package config
 
 import (
 	"net/url"
+	"path/filepath"
 	"strconv"
 
 	"github.com/caarlos0/env/v11"
@@ -9,11 +10,14 @@ import (
 
 // ServerConfig holds every runtime option for the HTTP server.
 type ServerConfig struct {
-	Address   string `env:"SIGNUM_ADDR"       envDefault:":8080"`
-	Postgres  PostgresConfig
-	FontPath  string `env:"SIGNUM_FONT_PATH"                     envRequired:"true"`
-	SecretKey string `env:"SIGNUM_SECRET_KEY"                    envRequired:"true"`
-	RateLimit RateLimitConfig
+	Address     string `env:"SIGNUM_ADDR"       envDefault:":8080"`
+	Postgres    PostgresConfig
+	FontPath    string `env:"SIGNUM_FONT_PATH"`
+	FontDir     string `env:"SIGNUM_FONT_DIR"`
+	TemplateDir string `env:"SIGNUM_TEMPLATE_DIR"`
+	TextPaths   bool   `env:"SIGNUM_TEXT_PATHS"`
+	SecretKey   string `env:"SIGNUM_SECRET_KEY"                    envRequired:"true"`
+	RateLimit   RateLimitConfig
 }
 
 // RateLimitConfig holds settings for API rate limiting.
@@ -50,6 +54,12 @@ func (c PostgresConfig) DSN() string {
 	return dsn.String()
 }
 
+// FontPaths splits FontPath into its fallback chain, which is separated by
+// os.PathListSeparator.
+func (c ServerConfig) FontPaths() []string {
+	return filepath.SplitList(c.FontPath)
+}
+
 // LoadServer populates ServerConfig from environment variables.
 func LoadServer() (*ServerConfig, error) {
 	var cfg ServerConfig
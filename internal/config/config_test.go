@@ -1,6 +1,7 @@
 package config_test
 
 import (
+	"os"
 	"testing"
 
 	"github.com/rhajizada/signum/internal/config"
@@ -26,6 +27,7 @@ func TestPostgresConfigDSN(t *testing.T) {
 func TestLoadServerFromEnv(t *testing.T) {
 	t.Setenv("SIGNUM_ADDR", ":9090")
 	t.Setenv("SIGNUM_FONT_PATH", "/tmp/font.ttf")
+	t.Setenv("SIGNUM_FONT_DIR", "/tmp/fonts")
 	t.Setenv("SIGNUM_SECRET_KEY", "secret")
 	t.Setenv("SIGNUM_POSTGRES_HOST", "db")
 	t.Setenv("SIGNUM_POSTGRES_PORT", "1234")
@@ -36,6 +38,7 @@ func TestLoadServerFromEnv(t *testing.T) {
 	t.Setenv("SIGNUM_RATE_LIMIT_ENABLED", "true")
 	t.Setenv("SIGNUM_RATE_LIMIT_REQUESTS_PER_MINUTE", "120")
 	t.Setenv("SIGNUM_RATE_LIMIT_BURST", "40")
+	t.Setenv("SIGNUM_TEXT_PATHS", "true")
 
 	cfg, err := config.LoadServer()
 	if err != nil {
@@ -48,9 +51,15 @@ func TestLoadServerFromEnv(t *testing.T) {
 	if cfg.FontPath != "/tmp/font.ttf" {
 		t.Fatalf("expected font path, got %q", cfg.FontPath)
 	}
+	if cfg.FontDir != "/tmp/fonts" {
+		t.Fatalf("expected font dir, got %q", cfg.FontDir)
+	}
 	if cfg.SecretKey != "secret" {
 		t.Fatalf("expected secret key to be set")
 	}
+	if !cfg.TextPaths {
+		t.Fatalf("expected text paths enabled")
+	}
 	if cfg.Postgres.Port != 1234 {
 		t.Fatalf("expected postgres port 1234, got %d", cfg.Postgres.Port)
 	}
@@ -68,6 +77,19 @@ func TestLoadServerFromEnv(t *testing.T) {
 	}
 }
 
+func TestServerConfigFontPaths(t *testing.T) {
+	cfg := config.ServerConfig{}
+	if paths := cfg.FontPaths(); len(paths) != 0 {
+		t.Fatalf("expected no font paths, got %v", paths)
+	}
+
+	cfg.FontPath = "/fonts/a.ttf" + string(os.PathListSeparator) + "/fonts/b.ttf"
+	paths := cfg.FontPaths()
+	if len(paths) != 2 || paths[0] != "/fonts/a.ttf" || paths[1] != "/fonts/b.ttf" {
+		t.Fatalf("expected two font paths, got %v", paths)
+	}
+}
+
 func TestLoadServerMissingEnv(t *testing.T) {
 	t.Setenv("SIGNUM_ADDR", ":9090")
 	t.Setenv("SIGNUM_FONT_PATH", "/tmp/font.ttf")
@@ -5,7 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,6 +16,7 @@ import (
 
 	"github.com/rhajizada/signum/internal/models"
 	"github.com/rhajizada/signum/internal/service"
+	"github.com/rhajizada/signum/pkg/renderer"
 )
 
 const maxJSONBodyBytes int64 = 64 * 1024
@@ -20,27 +24,52 @@ const maxJSONBodyBytes int64 = 64 * 1024
 // LiveBadge godoc
 //
 //	@Summary		Render a live badge
-//	@Description	Renders an SVG badge for the provided parameters.
+//	@Description	Renders an SVG badge for the provided parameters, or a PNG for format=png or Accept: image/png.
 //	@Tags			Badges
-//	@Produce		text/plain
-//	@Param			subject	query		string	true	"Left-hand subject text"
-//	@Param			status	query		string	true	"Right-hand status text"
-//	@Param			color	query		string	true	"Badge color (named or hex)"
-//	@Param			style	query		string	false	"Badge style (flat, flat-square, plastic). Default: flat"
-//	@Success		200		{string}	string	"SVG image"
-//	@Failure		400		{string}	string
-//	@Failure		413		{string}	string
-//	@Failure		429		{string}	string
-//	@Failure		500		{string}	string
+//	@Produce		text/plain,image/png
+//	@Param			subject		query		string	false	"Left-hand subject text. Required unless segment is set"
+//	@Param			status		query		string	false	"Right-hand status text. Required unless kind is progress or segment is set"
+//	@Param			segment		query		[]string	false	"Segment as text|color|link|logo, replacing subject and status; repeat for up to 8 segments"	collectionFormat(multi)
+//	@Param			color		query		string	false	"Badge color (name, hex, rgb() or hsl()). Required unless every segment after the first has a color"
+//	@Param			style		query		string	false	"Badge style (flat, flat-square, plastic, for-the-badge, social). Default: flat"
+//	@Param			label_color	query		string	false	"Subject (left segment) color (name, hex, rgb() or hsl())"
+//	@Param			text_color	query		string	false	"Text color override. Default: light or dark to contrast with each segment"
+//	@Param			dark_label_color	query		string	false	"Subject color when the reader prefers a dark color scheme"
+//	@Param			dark_color	query		string	false	"Badge color when the reader prefers a dark color scheme"
+//	@Param			dark_text_color	query		string	false	"Text color override when the reader prefers a dark color scheme"
+//	@Param			animation	query		string	false	"Badge animation (pulse, blink, spinner), still for readers who prefer reduced motion"
+//	@Param			font		query		string	false	"Name of a font loaded from the server font directory"
+//	@Param			font_size	query		number	false	"Text size in pixels from 6 to 16. Default: 11"
+//	@Param			font_weight	query		string	false	"Text weight (regular, bold). Default: regular"
+//	@Param			logo		query		string	false	"Embedded logo name or data:image/svg+xml;base64 URI"
+//	@Param			logo_color	query		string	false	"Logo color for embedded logos (name, hex, rgb() or hsl())"
+//	@Param			logo_width	query		int		false	"Logo width in pixels. Default: 14"
+//	@Param			max_width	query		int		false	"Maximum badge width in pixels. Default: unlimited"
+//	@Param			overflow	query		string	false	"Overflow policy when max_width is exceeded (truncate-end, truncate-middle, shrink). Default: truncate-end"
+//	@Param			title		query		string	false	"Accessible name for screen readers. Default: subject: status, or the segment texts"
+//	@Param			kind		query		string	false	"Badge kind (status, progress). Default: status"
+//	@Param			progress	query		number	false	"Progress bar fill from 0 to 100 for kind=progress (flat, flat-square and plastic styles)"
+//	@Param			link		query		[]string	false	"Subject link, then status link (http, https or mailto)"	collectionFormat(multi)
+//	@Param			id_prefix	query		string	false	"Prefix for element ids, to keep inline SVGs on one page apart (letters, digits, - and _)"
+//	@Param			scale		query		number	false	"Size multiplier up to 8. Default: 1"
+//	@Param			format		query		string	false	"Output format (svg, png). Default: negotiated from Accept, else svg"
+//	@Success		200			{string}	string	"SVG or PNG image"
+//	@Failure		400			{string}	string
+//	@Failure		413			{string}	string
+//	@Failure		429			{string}	string
+//	@Failure		500			{string}	string
 //	@Router			/api/badges/live [get].
 func (h *Handler) LiveBadge(w http.ResponseWriter, req *http.Request) {
-	query := req.URL.Query()
-	subject := query.Get("subject")
-	status := query.Get("status")
-	color := query.Get("color")
-	style := query.Get("style")
+	input, err := liveBadgeInput(req.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if input.Format == "" {
+		input.Format = negotiateFormat(req.Header.Get("Accept"))
+	}
 
-	badge, err := h.svc.GetLiveBadge(subject, status, color, style)
+	badge, err := h.svc.GetLiveBadge(input)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		if errors.Is(err, service.ErrInvalidBadgeInput) {
@@ -50,11 +79,88 @@ func (h *Handler) LiveBadge(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+	w.Header().Set("Content-Type", input.Format.ContentType())
+	w.Header().Set("Vary", "Accept")
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write(badge)
 }
 
+func liveBadgeInput(query url.Values) (service.BadgeInput, error) {
+	logoWidth, err := parseInt32Query(query, "logo_width")
+	if err != nil {
+		return service.BadgeInput{}, err
+	}
+	maxWidth, err := parseInt32Query(query, "max_width")
+	if err != nil {
+		return service.BadgeInput{}, err
+	}
+	progress, err := parseFloatQuery(query, "progress")
+	if err != nil {
+		return service.BadgeInput{}, err
+	}
+	fontSize, err := parseFloatQuery(query, "font_size")
+	if err != nil {
+		return service.BadgeInput{}, err
+	}
+	opts, err := renderOptions(query)
+	if err != nil {
+		return service.BadgeInput{}, err
+	}
+	var links [2]string
+	if len(query["link"]) > len(links) {
+		return service.BadgeInput{}, errors.New("at most two link parameters are allowed")
+	}
+	copy(links[:], query["link"])
+	var segments []service.Segment
+	for _, raw := range query["segment"] {
+		segment := renderer.ParseSegment(raw)
+		segments = append(segments, service.Segment{
+			Text:  segment.Text,
+			Color: string(segment.Color),
+			Link:  segment.Link,
+			Logo:  string(segment.Logo),
+		})
+	}
+	return service.BadgeInput{
+		Subject:        query.Get("subject"),
+		Status:         query.Get("status"),
+		Color:          query.Get("color"),
+		Style:          query.Get("style"),
+		LabelColor:     query.Get("label_color"),
+		Logo:           query.Get("logo"),
+		LogoColor:      query.Get("logo_color"),
+		LogoWidth:      logoWidth,
+		MaxWidth:       maxWidth,
+		Overflow:       query.Get("overflow"),
+		TextColor:      query.Get("text_color"),
+		Title:          query.Get("title"),
+		Links:          links,
+		Kind:           query.Get("kind"),
+		Progress:       progress,
+		DarkLabelColor: query.Get("dark_label_color"),
+		DarkColor:      query.Get("dark_color"),
+		DarkTextColor:  query.Get("dark_text_color"),
+		Animation:      query.Get("animation"),
+		Font:           query.Get("font"),
+		FontSize:       fontSize,
+		FontWeight:     query.Get("font_weight"),
+		Segments:       segments,
+		RenderOptions:  opts,
+	}, nil
+}
+
+func renderOptions(query url.Values) (service.RenderOptions, error) {
+	scale, err := parseFloatQuery(query, "scale")
+	if err != nil {
+		return service.RenderOptions{}, err
+	}
+	return service.RenderOptions{
+		IDPrefix: query.Get("id_prefix"),
+		Scale:    scale,
+		Format:   renderer.Format(strings.ToLower(strings.TrimSpace(query.Get("format")))),
+	}, nil
+}
+
 // CreateBadge handles POST /api/badges.
 //
 //	@Summary		Create a badge
@@ -83,10 +189,33 @@ func (h *Handler) CreateBadge(w http.ResponseWriter, req *http.Request) {
 	}
 
 	badge, token, err := h.svc.CreateBadge(req.Context(), service.BadgeInput{
-		Subject: payload.Subject,
-		Status:  payload.Status,
-		Color:   payload.Color,
-		Style:   payload.Style,
+		Subject:        payload.Subject,
+		Status:         payload.Status,
+		Color:          payload.Color,
+		Style:          payload.Style,
+		LabelColor:     payload.LabelColor,
+		Logo:           payload.Logo,
+		LogoColor:      payload.LogoColor,
+		LogoWidth:      payload.LogoWidth,
+		MaxWidth:       payload.MaxWidth,
+		Overflow:       payload.Overflow,
+		TextColor:      payload.TextColor,
+		Title:          payload.Title,
+		Links:          payload.Links,
+		Value:          payload.Value,
+		Unit:           payload.Unit,
+		ValueFormat:    payload.ValueFormat,
+		ColorScale:     payload.ColorScale,
+		Kind:           payload.Kind,
+		Progress:       payload.Progress,
+		DarkLabelColor: payload.DarkLabelColor,
+		DarkColor:      payload.DarkColor,
+		DarkTextColor:  payload.DarkTextColor,
+		Animation:      payload.Animation,
+		Font:           payload.Font,
+		FontSize:       payload.FontSize,
+		FontWeight:     payload.FontWeight,
+		Segments:       serviceSegments(payload.Segments),
 	})
 	if err != nil {
 		h.writeServiceError(w, err)
@@ -103,30 +232,46 @@ func (h *Handler) CreateBadge(w http.ResponseWriter, req *http.Request) {
 // GetBadge handles GET /api/badges/{id}.
 //
 //	@Summary		Render a stored badge
-//	@Description	Returns an SVG badge for the stored definition.
+//	@Description	Returns an SVG badge for the stored definition, or a PNG for a .png id suffix, format=png or Accept: image/png.
 //	@Tags			Badges
-//	@Produce		text/plain
-//	@Param			id	path		string	true	"Badge ID"
-//	@Success		200	{string}	string	"SVG image"
-//	@Failure		400	{string}	string
-//	@Failure		404	{string}	string
-//	@Failure		429	{string}	string
-//	@Failure		500	{string}	string
+//	@Produce		text/plain,image/png
+//	@Param			id			path		string	true	"Badge ID, optionally with a .png suffix"
+//	@Param			scale		query		number	false	"Size multiplier up to 8. Default: 1"
+//	@Param			id_prefix	query		string	false	"Prefix for element ids, to keep inline SVGs on one page apart (letters, digits, - and _)"
+//	@Param			format		query		string	false	"Output format (svg, png). Default: negotiated from Accept, else svg"
+//	@Success		200			{string}	string	"SVG or PNG image"
+//	@Failure		400			{string}	string
+//	@Failure		404			{string}	string
+//	@Failure		429			{string}	string
+//	@Failure		500			{string}	string
 //	@Router			/api/badges/{id} [get].
 func (h *Handler) GetBadge(w http.ResponseWriter, req *http.Request) {
-	id, err := parseBadgeID(req)
+	rawID, png := strings.CutSuffix(strings.TrimSpace(req.PathValue("id")), ".png")
+	id, err := parseUUID(rawID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	opts, err := renderOptions(req.URL.Query())
 	if err != nil {
 		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	switch {
+	case png:
+		opts.Format = renderer.FormatPNG
+	case opts.Format == "":
+		opts.Format = negotiateFormat(req.Header.Get("Accept"))
+	}
 
-	badge, svg, err := h.svc.RenderBadge(req.Context(), id)
+	badge, image, err := h.svc.RenderBadge(req.Context(), id, opts)
 	if err != nil {
 		h.writeServiceError(w, err)
 		return
 	}
 
-	etag := fmt.Sprintf(`W/"%s-%d"`, badge.ID, badge.UpdatedAt.UnixNano())
+	etag := fmt.Sprintf(`W/"%s-%d%s"`, badge.ID, badge.UpdatedAt.UnixNano(), etagSuffix(opts))
 	lastModified := badge.UpdatedAt.UTC().Format(http.TimeFormat)
 	if match := req.Header.Get("If-None-Match"); match != "" {
 		if etagMatches(match, etag) {
@@ -143,9 +288,9 @@ func (h *Handler) GetBadge(w http.ResponseWriter, req *http.Request) {
 	}
 
 	writeBadgeCacheHeaders(w, etag, lastModified)
-	w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+	w.Header().Set("Content-Type", opts.Format.ContentType())
 	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write(svg)
+	_, _ = w.Write(image)
 }
 
 // GetBadgeMeta handles GET /api/badges/{id}/meta.
@@ -222,17 +367,44 @@ func (h *Handler) PatchBadge(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	if payload.Subject == nil && payload.Status == nil && payload.Color == nil && payload.Style == nil {
+	patch := service.BadgePatch{
+		Subject:        payload.Subject,
+		Status:         payload.Status,
+		Color:          payload.Color,
+		Style:          payload.Style,
+		LabelColor:     payload.LabelColor,
+		Logo:           payload.Logo,
+		LogoColor:      payload.LogoColor,
+		LogoWidth:      payload.LogoWidth,
+		MaxWidth:       payload.MaxWidth,
+		Overflow:       payload.Overflow,
+		TextColor:      payload.TextColor,
+		Title:          payload.Title,
+		Links:          payload.Links,
+		Value:          payload.Value,
+		Unit:           payload.Unit,
+		ValueFormat:    payload.ValueFormat,
+		ColorScale:     payload.ColorScale,
+		Kind:           payload.Kind,
+		Progress:       payload.Progress,
+		DarkLabelColor: payload.DarkLabelColor,
+		DarkColor:      payload.DarkColor,
+		DarkTextColor:  payload.DarkTextColor,
+		Animation:      payload.Animation,
+		Font:           payload.Font,
+		FontSize:       payload.FontSize,
+		FontWeight:     payload.FontWeight,
+	}
+	if payload.Segments != nil {
+		segments := serviceSegments(*payload.Segments)
+		patch.Segments = &segments
+	}
+	if patch == (service.BadgePatch{}) {
 		writeError(w, http.StatusBadRequest, "at least one field is required")
 		return
 	}
 
-	badge, err := h.svc.PatchBadge(req.Context(), id, token, service.BadgePatch{
-		Subject: payload.Subject,
-		Status:  payload.Status,
-		Color:   payload.Color,
-		Style:   payload.Style,
-	})
+	badge, err := h.svc.PatchBadge(req.Context(), id, token, patch)
 	if err != nil {
 		h.writeServiceError(w, err)
 		return
@@ -291,7 +463,10 @@ func decodeJSON(req *http.Request, dst any) error {
 }
 
 func parseBadgeID(req *http.Request) (uuid.UUID, error) {
-	id := strings.TrimSpace(req.PathValue("id"))
+	return parseUUID(strings.TrimSpace(req.PathValue("id")))
+}
+
+func parseUUID(id string) (uuid.UUID, error) {
 	if id == "" {
 		return uuid.UUID{}, errors.New("badge id is required")
 	}
@@ -302,6 +477,71 @@ func parseBadgeID(req *http.Request) (uuid.UUID, error) {
 	return parsed, nil
 }
 
+func parseInt32Query(query url.Values, key string) (int32, error) {
+	raw := strings.TrimSpace(query.Get(key))
+	if raw == "" {
+		return 0, nil
+	}
+	value, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %q", key, raw)
+	}
+	return int32(value), nil
+}
+
+func parseFloatQuery(query url.Values, key string) (float64, error) {
+	raw := strings.TrimSpace(query.Get(key))
+	if raw == "" {
+		return 0, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %q", key, raw)
+	}
+	return value, nil
+}
+
+// etagSuffix distinguishes the renderings of one badge under different options.
+func etagSuffix(opts service.RenderOptions) string {
+	suffix := ""
+	if opts.IDPrefix != "" || opts.Scale != 0 {
+		suffix = fmt.Sprintf("-%s-%g", opts.IDPrefix, opts.Scale)
+	}
+	if opts.Format == renderer.FormatPNG {
+		suffix += "-png"
+	}
+	return suffix
+}
+
+// negotiateFormat picks PNG when the Accept header prefers image/png over
+// SVG, and SVG otherwise, as browsers list image/svg+xml or a wildcard.
+func negotiateFormat(accept string) renderer.Format {
+	pngQuality, svgQuality := 0.0, 0.0
+	for part := range strings.SplitSeq(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		switch mediaType {
+		case "image/png":
+			pngQuality = max(pngQuality, quality)
+		case "image/svg+xml", "image/*", "*/*":
+			svgQuality = max(svgQuality, quality)
+		default:
+		}
+	}
+	if pngQuality > svgQuality {
+		return renderer.FormatPNG
+	}
+	return renderer.FormatSVG
+}
+
 func readBearerToken(req *http.Request) string {
 	auth := strings.TrimSpace(req.Header.Get("Authorization"))
 	if auth == "" {
@@ -318,6 +558,7 @@ func writeBadgeCacheHeaders(w http.ResponseWriter, etag, lastModified string) {
 	w.Header().Set("Cache-Control", "public, max-age=0, s-maxage=300, must-revalidate")
 	w.Header().Set("ETag", etag)
 	w.Header().Set("Last-Modified", lastModified)
+	w.Header().Set("Vary", "Accept")
 }
 
 func etagMatches(header, etag string) bool {
@@ -331,12 +572,54 @@ func etagMatches(header, etag string) bool {
 
 func toBadgeResponse(badge service.Badge) models.Badge {
 	return models.Badge{
-		ID:        badge.ID.String(),
-		Subject:   badge.Subject,
-		Status:    badge.Status,
-		Color:     badge.Color,
-		Style:     badge.Style,
-		CreatedAt: badge.CreatedAt,
-		UpdatedAt: badge.UpdatedAt,
+		ID:             badge.ID.String(),
+		Subject:        badge.Subject,
+		Status:         badge.Status,
+		Color:          badge.Color,
+		Style:          badge.Style,
+		LabelColor:     badge.LabelColor,
+		Logo:           badge.Logo,
+		LogoColor:      badge.LogoColor,
+		LogoWidth:      badge.LogoWidth,
+		MaxWidth:       badge.MaxWidth,
+		Overflow:       badge.Overflow,
+		TextColor:      badge.TextColor,
+		Title:          badge.Title,
+		Links:          badge.Links,
+		Value:          badge.Value,
+		Unit:           badge.Unit,
+		ValueFormat:    badge.ValueFormat,
+		ColorScale:     badge.ColorScale,
+		Kind:           badge.Kind,
+		Progress:       badge.Progress,
+		DarkLabelColor: badge.DarkLabelColor,
+		DarkColor:      badge.DarkColor,
+		DarkTextColor:  badge.DarkTextColor,
+		Animation:      badge.Animation,
+		Font:           badge.Font,
+		FontSize:       badge.FontSize,
+		FontWeight:     badge.FontWeight,
+		Segments:       segmentResponses(badge.Segments),
+		CreatedAt:      badge.CreatedAt,
+		UpdatedAt:      badge.UpdatedAt,
+	}
+}
+
+func serviceSegments(segments []models.Segment) []service.Segment {
+	if segments == nil {
+		return nil
+	}
+	converted := make([]service.Segment, len(segments))
+	for i, s := range segments {
+		converted[i] = service.Segment{Text: s.Text, Color: s.Color, Link: s.Link, Logo: s.Logo}
+	}
+	return converted
+}
+
+func segmentResponses(segments []service.Segment) []models.Segment {
+	converted := make([]models.Segment, len(segments))
+	for i, s := range segments {
+		converted[i] = models.Segment{Text: s.Text, Color: s.Color, Link: s.Link, Logo: s.Logo}
 	}
+	return converted
 }
@@ -218,6 +218,60 @@ func TestGetBadgeHandlerNotFound(t *testing.T) {
 	}
 }
 
+func TestGetBadgeHandlerScale(t *testing.T) {
+	id := uuid.New()
+	repo := &fakeRepo{
+		getFn: func(_ context.Context, _ uuid.UUID) (repository.Badge, error) {
+			return repository.Badge{
+				ID:      id,
+				Subject: "build",
+				Status:  "passing",
+				Color:   "green",
+				Style:   "flat",
+			}, nil
+		},
+	}
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	h := newHandler(t, repo, tokens)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/badges/"+id.String(), nil)
+	req.SetPathValue("id", id.String())
+	rec := httptest.NewRecorder()
+	h.GetBadge(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected ok, got %d", rec.Code)
+	}
+	etag := rec.Header().Get("ETag")
+
+	req = httptest.NewRequest(http.MethodGet, "/api/badges/"+id.String()+"?scale=2", nil)
+	req.SetPathValue("id", id.String())
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	h.GetBadge(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected ok for a different scale, got %d", rec.Code)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte(`height="40" viewBox="0 0 `)) {
+		t.Fatalf("expected scaled svg response body: %s", rec.Body.String())
+	}
+	if rec.Header().Get("ETag") == etag {
+		t.Fatalf("expected scaled badge to have its own etag")
+	}
+
+	for _, query := range []string{"scale=big", "scale=100"} {
+		req = httptest.NewRequest(http.MethodGet, "/api/badges/"+id.String()+"?"+query, nil)
+		req.SetPathValue("id", id.String())
+		rec = httptest.NewRecorder()
+		h.GetBadge(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected bad request, got %d", query, rec.Code)
+		}
+	}
+}
+
 func TestGetBadgeMetaHandler(t *testing.T) {
 	id := uuid.New()
 	repo := &fakeRepo{
@@ -310,6 +364,62 @@ func TestPatchBadgeHandler(t *testing.T) {
 	}
 }
 
+func TestPatchBadgeHandlerValue(t *testing.T) {
+	id := uuid.New()
+	token := "token"
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	hash, err := tokens.HashToken(token)
+	if err != nil {
+		t.Fatalf("hash token: %v", err)
+	}
+	repo := &fakeRepo{
+		getFn: func(_ context.Context, _ uuid.UUID) (repository.Badge, error) {
+			return repository.Badge{
+				ID:        id,
+				TokenHash: hash,
+				Subject:   "coverage",
+				Status:    "unknown",
+				Color:     "lightgrey",
+				Style:     "flat",
+				Unit:      "%",
+			}, nil
+		},
+		updateFn: func(_ context.Context, arg repository.UpdateBadgeParams) (repository.Badge, error) {
+			return repository.Badge{
+				ID:        id,
+				TokenHash: hash,
+				Subject:   arg.Subject,
+				Status:    arg.Status,
+				Color:     arg.Color,
+				Style:     arg.Style,
+				Value:     arg.Value,
+				Unit:      arg.Unit,
+			}, nil
+		},
+	}
+	h := newHandler(t, repo, tokens)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/badges/"+id.String(), strings.NewReader(`{"value":87.3}`))
+	req.SetPathValue("id", id.String())
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	h.PatchBadge(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status ok, got %d", rec.Code)
+	}
+	var resp models.Badge
+	if err = json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Status != "87.3%" || resp.Color != "brightgreen" || resp.Value == nil || *resp.Value != 87.3 {
+		t.Fatalf("unexpected badge: %#v", resp)
+	}
+}
+
 func TestPatchBadgeHandlerMissingToken(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPatch, "/api/badges/123", strings.NewReader(`{"subject":"updated"}`))
 	req.SetPathValue("id", uuid.New().String())
@@ -422,3 +532,535 @@ func TestLiveBadgeHandler(t *testing.T) {
 		t.Fatalf("expected svg response body")
 	}
 }
+
+func TestLiveBadgeHandlerProgress(t *testing.T) {
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	h := newHandler(t, &fakeRepo{}, tokens)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/badges/live?subject=tasks&color=green&kind=progress&progress=75", nil)
+	rec := httptest.NewRecorder()
+	h.LiveBadge(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected ok, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte("75%")) {
+		t.Fatalf("expected progress text in body")
+	}
+
+	for _, query := range []string{"progress=abc", "progress=150", "style=social"} {
+		req = httptest.NewRequest(http.MethodGet, "/api/badges/live?subject=tasks&color=green&kind=progress&"+query, nil)
+		rec = httptest.NewRecorder()
+		h.LiveBadge(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected bad request, got %d", query, rec.Code)
+		}
+	}
+}
+
+func TestLiveBadgeHandlerLogo(t *testing.T) {
+	repo := &fakeRepo{}
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	h := newHandler(t, repo, tokens)
+
+	req := httptest.NewRequest(
+		http.MethodGet,
+		"/api/badges/live?subject=build&status=passing&color=green&logo=check&logo_color=yellow&logo_width=12",
+		nil,
+	)
+	rec := httptest.NewRecorder()
+	h.LiveBadge(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected ok, got %d", rec.Code)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte("<image")) {
+		t.Fatalf("expected logo in svg response body")
+	}
+
+	req = httptest.NewRequest(
+		http.MethodGet,
+		"/api/badges/live?subject=build&status=passing&color=green&logo=check&logo_width=wide",
+		nil,
+	)
+	rec = httptest.NewRecorder()
+	h.LiveBadge(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected bad request, got %d", rec.Code)
+	}
+}
+
+func TestLiveBadgeHandlerMaxWidth(t *testing.T) {
+	repo := &fakeRepo{}
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	h := newHandler(t, repo, tokens)
+
+	req := httptest.NewRequest(
+		http.MethodGet,
+		"/api/badges/live?subject=branch&status=feature-a-very-long-branch-name&color=blue&max_width=100&overflow=shrink",
+		nil,
+	)
+	rec := httptest.NewRecorder()
+	h.LiveBadge(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected ok, got %d", rec.Code)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte("textLength=")) {
+		t.Fatalf("expected shrunk text in svg response body: %s", rec.Body.String())
+	}
+
+	for _, query := range []string{"max_width=wide", "max_width=100&overflow=wrap", "max_width=5"} {
+		req = httptest.NewRequest(
+			http.MethodGet,
+			"/api/badges/live?subject=build&status=passing&color=green&"+query,
+			nil,
+		)
+		rec = httptest.NewRecorder()
+		h.LiveBadge(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected bad request, got %d", query, rec.Code)
+		}
+	}
+}
+
+func TestBadgeHandlersMaxWidthTooSmall(t *testing.T) {
+	id := uuid.New()
+	token := "token"
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	hash, err := tokens.HashToken(token)
+	if err != nil {
+		t.Fatalf("hash token: %v", err)
+	}
+	repo := &fakeRepo{
+		createFn: func(_ context.Context, arg repository.CreateBadgeParams) (repository.Badge, error) {
+			t.Fatalf("unrenderable badge was created: %#v", arg)
+			return repository.Badge{}, nil
+		},
+		getFn: func(_ context.Context, _ uuid.UUID) (repository.Badge, error) {
+			return repository.Badge{
+				ID:        id,
+				TokenHash: hash,
+				Subject:   "build",
+				Status:    "passing",
+				Color:     "green",
+				Style:     "flat",
+			}, nil
+		},
+		updateFn: func(_ context.Context, arg repository.UpdateBadgeParams) (repository.Badge, error) {
+			t.Fatalf("unrenderable badge was updated: %#v", arg)
+			return repository.Badge{}, nil
+		},
+	}
+	h := newHandler(t, repo, tokens)
+
+	body := `{"subject":"build","status":"passing","color":"green","max_width":5}`
+	req := httptest.NewRequest(http.MethodPost, "/api/badges", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.CreateBadge(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("create: expected bad request, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPatch, "/api/badges/"+id.String(), strings.NewReader(`{"max_width":5}`))
+	req.SetPathValue("id", id.String())
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec = httptest.NewRecorder()
+	h.PatchBadge(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("patch: expected bad request, got %d", rec.Code)
+	}
+}
+
+func TestLiveBadgeHandlerTextColor(t *testing.T) {
+	repo := &fakeRepo{}
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	h := newHandler(t, repo, tokens)
+
+	req := httptest.NewRequest(
+		http.MethodGet,
+		"/api/badges/live?subject=build&status=passing&color=green&text_color=%23000",
+		nil,
+	)
+	rec := httptest.NewRecorder()
+	h.LiveBadge(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected ok, got %d", rec.Code)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte(`fill="#000">passing<`)) {
+		t.Fatalf("expected text color in svg response body: %s", rec.Body.String())
+	}
+}
+
+func TestLiveBadgeHandlerDarkPalette(t *testing.T) {
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	h := newHandler(t, &fakeRepo{}, tokens)
+
+	req := httptest.NewRequest(
+		http.MethodGet,
+		"/api/badges/live?subject=build&status=passing&color=green&dark_color=%23050&dark_label_color=%23111",
+		nil,
+	)
+	rec := httptest.NewRecorder()
+	h.LiveBadge(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected ok, got %d", rec.Code)
+	}
+	for _, want := range []string{"@media (prefers-color-scheme:dark)", " .status{fill:#050}", " .subject{fill:#111}"} {
+		if !bytes.Contains(rec.Body.Bytes(), []byte(want)) {
+			t.Fatalf("expected %q in svg response body: %s", want, rec.Body.String())
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/badges/live?subject=build&status=passing&color=green&dark_color=nope", nil)
+	rec = httptest.NewRecorder()
+	h.LiveBadge(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected bad request, got %d", rec.Code)
+	}
+}
+
+func TestLiveBadgeHandlerAnimation(t *testing.T) {
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	h := newHandler(t, &fakeRepo{}, tokens)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/badges/live?subject=deploy&status=running&color=blue&animation=pulse", nil)
+	rec := httptest.NewRecorder()
+	h.LiveBadge(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected ok, got %d", rec.Code)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte("@media (prefers-reduced-motion:no-preference)")) {
+		t.Fatalf("expected a reduced motion guard in svg response body: %s", rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/badges/live?subject=deploy&status=running&color=blue&animation=spin", nil)
+	rec = httptest.NewRecorder()
+	h.LiveBadge(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected bad request, got %d", rec.Code)
+	}
+}
+
+func TestLiveBadgeHandlerFont(t *testing.T) {
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	h := newHandler(t, &fakeRepo{}, tokens)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/badges/live?subject=release&status=v1.2.3&color=blue&font_size=12&font_weight=bold", nil)
+	rec := httptest.NewRecorder()
+	h.LiveBadge(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected ok, got %d", rec.Code)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte(`font-size="12" font-weight="bold"`)) {
+		t.Fatalf("expected bold 12px text in svg response body: %s", rec.Body.String())
+	}
+
+	for _, query := range []string{"font=Fira+Code", "font_size=big", "font_size=40", "font_weight=heavy"} {
+		req = httptest.NewRequest(http.MethodGet, "/api/badges/live?subject=release&status=v1.2.3&color=blue&"+query, nil)
+		rec = httptest.NewRecorder()
+		h.LiveBadge(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected bad request, got %d", query, rec.Code)
+		}
+	}
+}
+
+func TestLiveBadgeHandlerTitle(t *testing.T) {
+	repo := &fakeRepo{}
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	h := newHandler(t, repo, tokens)
+
+	req := httptest.NewRequest(
+		http.MethodGet,
+		"/api/badges/live?subject=build&status=passing&color=green&title=Build+status&id_prefix=docs",
+		nil,
+	)
+	rec := httptest.NewRecorder()
+	h.LiveBadge(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected ok, got %d", rec.Code)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte("<title>Build status</title>")) {
+		t.Fatalf("expected title in svg response body: %s", rec.Body.String())
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte(`id="smooth-docs-`)) {
+		t.Fatalf("expected prefixed ids in svg response body: %s", rec.Body.String())
+	}
+}
+
+func TestLiveBadgeHandlerLinks(t *testing.T) {
+	repo := &fakeRepo{}
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	h := newHandler(t, repo, tokens)
+
+	req := httptest.NewRequest(
+		http.MethodGet,
+		"/api/badges/live?subject=docs&status=latest&color=blue&link=https%3A%2F%2Fexample.com&link=https%3A%2F%2Fexample.org",
+		nil,
+	)
+	rec := httptest.NewRecorder()
+	h.LiveBadge(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected ok, got %d", rec.Code)
+	}
+	body := rec.Body.Bytes()
+	if !bytes.Contains(body, []byte(`xlink:href="https://example.com"`)) || !bytes.Contains(body, []byte(`xlink:href="https://example.org"`)) {
+		t.Fatalf("expected links in svg response body: %s", rec.Body.String())
+	}
+
+	for _, query := range []string{"link=javascript%3Aalert(1)", "link=a&link=b&link=c"} {
+		req = httptest.NewRequest(
+			http.MethodGet,
+			"/api/badges/live?subject=docs&status=latest&color=blue&"+query,
+			nil,
+		)
+		rec = httptest.NewRecorder()
+		h.LiveBadge(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected bad request, got %d", query, rec.Code)
+		}
+	}
+}
+
+func TestLiveBadgeHandlerSegments(t *testing.T) {
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	h := newHandler(t, &fakeRepo{}, tokens)
+
+	req := httptest.NewRequest(
+		http.MethodGet,
+		"/api/badges/live?color=green&segment=build||https%3A%2F%2Fexample.com&segment=linux%7Cblue&segment=passing",
+		nil,
+	)
+	rec := httptest.NewRecorder()
+	h.LiveBadge(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected ok, got %d: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.Bytes()
+	for _, want := range []string{`aria-label="build: linux: passing"`, `fill="#007ec6"`, `xlink:href="https://example.com"`} {
+		if !bytes.Contains(body, []byte(want)) {
+			t.Fatalf("expected %s in svg response body: %s", want, body)
+		}
+	}
+
+	for _, query := range []string{"subject=build&segment=a", "segment=a|nope", "segment=a&segment=|green"} {
+		req = httptest.NewRequest(http.MethodGet, "/api/badges/live?color=green&"+query, nil)
+		rec = httptest.NewRecorder()
+		h.LiveBadge(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected bad request, got %d", query, rec.Code)
+		}
+	}
+}
+
+func TestPatchBadgeHandlerSegments(t *testing.T) {
+	id := uuid.New()
+	token := "token"
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	hash, err := tokens.HashToken(token)
+	if err != nil {
+		t.Fatalf("hash token: %v", err)
+	}
+	repo := &fakeRepo{
+		getFn: func(_ context.Context, _ uuid.UUID) (repository.Badge, error) {
+			return repository.Badge{
+				ID:        id,
+				TokenHash: hash,
+				Subject:   "build",
+				Status:    "passing",
+				Color:     "green",
+				Style:     "flat",
+			}, nil
+		},
+		updateFn: func(_ context.Context, arg repository.UpdateBadgeParams) (repository.Badge, error) {
+			return repository.Badge{
+				ID:        id,
+				TokenHash: hash,
+				Subject:   arg.Subject,
+				Status:    arg.Status,
+				Color:     arg.Color,
+				Style:     arg.Style,
+				Segments:  arg.Segments,
+			}, nil
+		},
+	}
+	h := newHandler(t, repo, tokens)
+
+	payload := `{"segments":[{"text":"build"},{"text":"linux","color":"blue"},{"text":"passing"}]}`
+	req := httptest.NewRequest(http.MethodPatch, "/api/badges/"+id.String(), strings.NewReader(payload))
+	req.SetPathValue("id", id.String())
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	h.PatchBadge(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status ok, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp models.Badge
+	if err = json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Subject != "" || len(resp.Segments) != 3 || resp.Segments[1] != (models.Segment{Text: "linux", Color: "blue"}) {
+		t.Fatalf("unexpected badge: %#v", resp)
+	}
+}
+
+func TestLiveBadgeHandlerScale(t *testing.T) {
+	repo := &fakeRepo{}
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	h := newHandler(t, repo, tokens)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/badges/live?subject=build&status=passing&color=green&scale=1.5", nil)
+	rec := httptest.NewRecorder()
+	h.LiveBadge(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected ok, got %d", rec.Code)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte(`height="30" viewBox="0 0 `)) {
+		t.Fatalf("expected scaled svg response body: %s", rec.Body.String())
+	}
+}
+
+func TestGetBadgeHandlerPNG(t *testing.T) {
+	id := uuid.New()
+	repo := &fakeRepo{
+		getFn: func(_ context.Context, _ uuid.UUID) (repository.Badge, error) {
+			return repository.Badge{
+				ID:      id,
+				Subject: "build",
+				Status:  "passing",
+				Color:   "green",
+				Style:   "flat",
+			}, nil
+		},
+	}
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	h := newHandler(t, repo, tokens)
+
+	cases := []struct {
+		name   string
+		path   string
+		accept string
+		want   string
+	}{
+		{"suffix", id.String() + ".png", "", "image/png"},
+		{"accept", id.String(), "image/png", "image/png"},
+		{"browser", id.String(), "image/avif,image/webp,image/png,image/svg+xml,image/*,*/*;q=0.8", "image/svg+xml; charset=utf-8"},
+		{"svg preferred", id.String(), "image/png;q=0.5,image/svg+xml", "image/svg+xml; charset=utf-8"},
+	}
+	etags := map[string]string{}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/api/badges/"+tc.path, nil)
+		req.SetPathValue("id", tc.path)
+		if tc.accept != "" {
+			req.Header.Set("Accept", tc.accept)
+		}
+		rec := httptest.NewRecorder()
+		h.GetBadge(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected ok, got %d", tc.name, rec.Code)
+		}
+		if got := rec.Header().Get("Content-Type"); got != tc.want {
+			t.Fatalf("%s: expected content type %q, got %q", tc.name, tc.want, got)
+		}
+		if got := rec.Header().Get("Vary"); got != "Accept" {
+			t.Fatalf("%s: expected Vary: Accept, got %q", tc.name, got)
+		}
+		if tc.want == "image/png" && !bytes.HasPrefix(rec.Body.Bytes(), []byte("\x89PNG")) {
+			t.Fatalf("%s: expected png body", tc.name)
+		}
+		etags[tc.want] = rec.Header().Get("ETag")
+	}
+	if etags["image/png"] == etags["image/svg+xml; charset=utf-8"] {
+		t.Fatalf("expected png and svg renderings to have their own etags")
+	}
+}
+
+func TestLiveBadgeHandlerPNG(t *testing.T) {
+	repo := &fakeRepo{}
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	h := newHandler(t, repo, tokens)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/badges/live?subject=build&status=passing&color=green&format=png", nil)
+	rec := httptest.NewRecorder()
+	h.LiveBadge(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected ok, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "image/png" {
+		t.Fatalf("expected png content type, got %q", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/badges/live?subject=build&status=passing&color=green", nil)
+	req.Header.Set("Accept", "image/png")
+	rec = httptest.NewRecorder()
+	h.LiveBadge(rec, req)
+	if got := rec.Header().Get("Content-Type"); got != "image/png" {
+		t.Fatalf("expected png content type from Accept, got %q", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/badges/live?subject=build&status=passing&color=green&format=gif", nil)
+	rec = httptest.NewRecorder()
+	h.LiveBadge(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected bad request for unknown format, got %d", rec.Code)
+	}
+}
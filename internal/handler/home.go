@@ -5,6 +5,8 @@ import (
 	"errors"
 	"html/template"
 	"net/http"
+
+	"github.com/rhajizada/signum/pkg/renderer"
 )
 
 //go:embed templates/index.html
@@ -16,6 +18,7 @@ type homeData struct {
 	Status  string
 	Color   string
 	Style   string
+	Styles  []renderer.Style
 }
 
 func parseHomeTemplate() (*template.Template, error) {
@@ -47,6 +50,7 @@ func (h *Handler) Home(w http.ResponseWriter, req *http.Request) {
 		Status:  "passing",
 		Color:   "green",
 		Style:   "flat",
+		Styles:  h.svc.Styles(),
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -4,29 +4,128 @@ import "time"
 
 // CreateBadgeRequest defines the payload for creating a badge.
 type CreateBadgeRequest struct {
-	Subject string `json:"subject"`
-	Status  string `json:"status"`
-	Color   string `json:"color"`
-	Style   string `json:"style"`
+	Subject    string    `json:"subject"`
+	Status     string    `json:"status"`
+	Color      string    `json:"color"`
+	Style      string    `json:"style"`
+	LabelColor string    `json:"label_color"`
+	Logo       string    `json:"logo"`
+	LogoColor  string    `json:"logo_color"`
+	LogoWidth  int32     `json:"logo_width"`
+	MaxWidth   int32     `json:"max_width"`
+	Overflow   string    `json:"overflow"`
+	TextColor  string    `json:"text_color"`
+	Title      string    `json:"title"`
+	Links      [2]string `json:"links"`
+	// Value sets the status and color from the value; see ColorScale.
+	Value       *float64 `json:"value"`
+	Unit        string   `json:"unit"`
+	ValueFormat string   `json:"value_format"`
+	ColorScale  string   `json:"color_scale"`
+	// Kind is status or progress. Progress badges fill in proportion to
+	// Progress, from 0 to 100, which follows Value when one is set.
+	Kind     string  `json:"kind"`
+	Progress float64 `json:"progress"`
+	// Dark colors repaint the badge for readers who prefer a dark color
+	// scheme. Empty keeps the light color.
+	DarkLabelColor string `json:"dark_label_color"`
+	DarkColor      string `json:"dark_color"`
+	DarkTextColor  string `json:"dark_text_color"`
+	// Animation is pulse, blink or spinner. Empty draws a still badge.
+	Animation string `json:"animation"`
+	// Font names a font loaded from the server font directory, drawn at
+	// FontSize pixels from 6 to 16 and FontWeight regular or bold. Empty
+	// keeps the default 11px regular text.
+	Font       string  `json:"font"`
+	FontSize   float64 `json:"font_size"`
+	FontWeight string  `json:"font_weight"`
+	// Segments replace subject and status with up to 8 parts, each with its
+	// own color, link and logo. Subject, status, value, logo and links must
+	// then be empty.
+	Segments []Segment `json:"segments"`
 } // @name CreateBadgeRequest
 
+// Segment defines one part of a badge with segments.
+type Segment struct {
+	Text string `json:"text"`
+	// Color fills the segment. Empty uses the label color for the first
+	// segment and the badge color for the others.
+	Color string `json:"color,omitempty"`
+	Link  string `json:"link,omitempty"`
+	Logo  string `json:"logo,omitempty"`
+} // @name Segment
+
 // PatchBadgeRequest defines the payload for patching a badge.
 type PatchBadgeRequest struct {
-	Subject *string `json:"subject"`
-	Status  *string `json:"status"`
-	Color   *string `json:"color"`
-	Style   *string `json:"style"`
+	Subject    *string    `json:"subject"`
+	Status     *string    `json:"status"`
+	Color      *string    `json:"color"`
+	Style      *string    `json:"style"`
+	LabelColor *string    `json:"label_color"`
+	Logo       *string    `json:"logo"`
+	LogoColor  *string    `json:"logo_color"`
+	LogoWidth  *int32     `json:"logo_width"`
+	MaxWidth   *int32     `json:"max_width"`
+	Overflow   *string    `json:"overflow"`
+	TextColor  *string    `json:"text_color"`
+	Title      *string    `json:"title"`
+	Links      *[2]string `json:"links"`
+	// Value updates the status and color. Patching status or color without
+	// a value clears it.
+	Value       *float64 `json:"value"`
+	Unit        *string  `json:"unit"`
+	ValueFormat *string  `json:"value_format"`
+	ColorScale  *string  `json:"color_scale"`
+	Kind        *string  `json:"kind"`
+	Progress    *float64 `json:"progress"`
+	// Dark colors are patched one by one; an empty string removes one.
+	DarkLabelColor *string `json:"dark_label_color"`
+	DarkColor      *string `json:"dark_color"`
+	DarkTextColor  *string `json:"dark_text_color"`
+	// Animation is cleared with an empty string.
+	Animation *string `json:"animation"`
+	// Font settings are reset with an empty string and zero.
+	Font       *string  `json:"font"`
+	FontSize   *float64 `json:"font_size"`
+	FontWeight *string  `json:"font_weight"`
+	// Segments replace the segments and clear subject, status, value, logo
+	// and links. Patching subject or status clears the segments.
+	Segments *[]Segment `json:"segments"`
 } // @name PatchBadgeRequest
 
 // Badge defines the badge payload returned from the API.
 type Badge struct {
-	ID        string    `json:"id"`
-	Subject   string    `json:"subject"`
-	Status    string    `json:"status"`
-	Color     string    `json:"color"`
-	Style     string    `json:"style"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          string    `json:"id"`
+	Subject     string    `json:"subject"`
+	Status      string    `json:"status"`
+	Color       string    `json:"color"`
+	Style       string    `json:"style"`
+	LabelColor  string    `json:"label_color"`
+	Logo        string    `json:"logo"`
+	LogoColor   string    `json:"logo_color"`
+	LogoWidth   int32     `json:"logo_width"`
+	MaxWidth    int32     `json:"max_width"`
+	Overflow    string    `json:"overflow"`
+	TextColor   string    `json:"text_color"`
+	Title       string    `json:"title"`
+	Links       [2]string `json:"links"`
+	Value       *float64  `json:"value"`
+	Unit        string    `json:"unit"`
+	ValueFormat string    `json:"value_format"`
+	ColorScale  string    `json:"color_scale"`
+	Kind        string    `json:"kind"`
+	Progress    float64   `json:"progress"`
+	// Dark colors apply when the reader prefers a dark color scheme.
+	DarkLabelColor string    `json:"dark_label_color"`
+	DarkColor      string    `json:"dark_color"`
+	DarkTextColor  string    `json:"dark_text_color"`
+	Animation      string    `json:"animation"`
+	Font           string    `json:"font"`
+	FontSize       float64   `json:"font_size"`
+	FontWeight     string    `json:"font_weight"`
+	Segments       []Segment `json:"segments"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 } // @name Badge
 
 // CreateBadgeResponse defines the response payload for badge creation.
@@ -7,6 +7,8 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 
 	"github.com/google/uuid"
 )
@@ -17,19 +19,67 @@ INSERT INTO badges (
     subject,
     status,
     color,
-    style
+    style,
+    logo,
+    logo_color,
+    logo_width,
+    label_color,
+    max_width,
+    overflow,
+    text_color,
+    title,
+    subject_link,
+    status_link,
+    value,
+    unit,
+    value_format,
+    color_scale,
+    kind,
+    progress,
+    dark_label_color,
+    dark_color,
+    dark_text_color,
+    animation,
+    font,
+    font_size,
+    font_weight,
+    segments
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29
 )
-RETURNING id, token_hash, subject, status, color, style, created_at, updated_at
+RETURNING id, token_hash, subject, status, color, style, created_at, updated_at, logo, logo_color, logo_width, label_color, max_width, overflow, text_color, title, subject_link, status_link, value, unit, value_format, color_scale, kind, progress, dark_label_color, dark_color, dark_text_color, animation, font, font_size, font_weight, segments
 `
 
 type CreateBadgeParams struct {
-	TokenHash string `json:"token_hash"`
-	Subject   string `json:"subject"`
-	Status    string `json:"status"`
-	Color     string `json:"color"`
-	Style     string `json:"style"`
+	TokenHash      string          `json:"token_hash"`
+	Subject        string          `json:"subject"`
+	Status         string          `json:"status"`
+	Color          string          `json:"color"`
+	Style          string          `json:"style"`
+	Logo           string          `json:"logo"`
+	LogoColor      string          `json:"logo_color"`
+	LogoWidth      int32           `json:"logo_width"`
+	LabelColor     string          `json:"label_color"`
+	MaxWidth       int32           `json:"max_width"`
+	Overflow       string          `json:"overflow"`
+	TextColor      string          `json:"text_color"`
+	Title          string          `json:"title"`
+	SubjectLink    string          `json:"subject_link"`
+	StatusLink     string          `json:"status_link"`
+	Value          sql.NullFloat64 `json:"value"`
+	Unit           string          `json:"unit"`
+	ValueFormat    string          `json:"value_format"`
+	ColorScale     string          `json:"color_scale"`
+	Kind           string          `json:"kind"`
+	Progress       float64         `json:"progress"`
+	DarkLabelColor string          `json:"dark_label_color"`
+	DarkColor      string          `json:"dark_color"`
+	DarkTextColor  string          `json:"dark_text_color"`
+	Animation      string          `json:"animation"`
+	Font           string          `json:"font"`
+	FontSize       float64         `json:"font_size"`
+	FontWeight     string          `json:"font_weight"`
+	Segments       json.RawMessage `json:"segments"`
 }
 
 func (q *Queries) CreateBadge(ctx context.Context, arg CreateBadgeParams) (Badge, error) {
@@ -39,6 +89,30 @@ func (q *Queries) CreateBadge(ctx context.Context, arg CreateBadgeParams) (Badge
 		arg.Status,
 		arg.Color,
 		arg.Style,
+		arg.Logo,
+		arg.LogoColor,
+		arg.LogoWidth,
+		arg.LabelColor,
+		arg.MaxWidth,
+		arg.Overflow,
+		arg.TextColor,
+		arg.Title,
+		arg.SubjectLink,
+		arg.StatusLink,
+		arg.Value,
+		arg.Unit,
+		arg.ValueFormat,
+		arg.ColorScale,
+		arg.Kind,
+		arg.Progress,
+		arg.DarkLabelColor,
+		arg.DarkColor,
+		arg.DarkTextColor,
+		arg.Animation,
+		arg.Font,
+		arg.FontSize,
+		arg.FontWeight,
+		arg.Segments,
 	)
 	var i Badge
 	err := row.Scan(
@@ -50,6 +124,30 @@ func (q *Queries) CreateBadge(ctx context.Context, arg CreateBadgeParams) (Badge
 		&i.Style,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Logo,
+		&i.LogoColor,
+		&i.LogoWidth,
+		&i.LabelColor,
+		&i.MaxWidth,
+		&i.Overflow,
+		&i.TextColor,
+		&i.Title,
+		&i.SubjectLink,
+		&i.StatusLink,
+		&i.Value,
+		&i.Unit,
+		&i.ValueFormat,
+		&i.ColorScale,
+		&i.Kind,
+		&i.Progress,
+		&i.DarkLabelColor,
+		&i.DarkColor,
+		&i.DarkTextColor,
+		&i.Animation,
+		&i.Font,
+		&i.FontSize,
+		&i.FontWeight,
+		&i.Segments,
 	)
 	return i, err
 }
@@ -65,7 +163,7 @@ func (q *Queries) DeleteBadge(ctx context.Context, id uuid.UUID) error {
 }
 
 const getBadgeByID = `-- name: GetBadgeByID :one
-SELECT id, token_hash, subject, status, color, style, created_at, updated_at
+SELECT id, token_hash, subject, status, color, style, created_at, updated_at, logo, logo_color, logo_width, label_color, max_width, overflow, text_color, title, subject_link, status_link, value, unit, value_format, color_scale, kind, progress, dark_label_color, dark_color, dark_text_color, animation, font, font_size, font_weight, segments
 FROM badges
 WHERE id = $1
 `
@@ -82,6 +180,30 @@ func (q *Queries) GetBadgeByID(ctx context.Context, id uuid.UUID) (Badge, error)
 		&i.Style,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Logo,
+		&i.LogoColor,
+		&i.LogoWidth,
+		&i.LabelColor,
+		&i.MaxWidth,
+		&i.Overflow,
+		&i.TextColor,
+		&i.Title,
+		&i.SubjectLink,
+		&i.StatusLink,
+		&i.Value,
+		&i.Unit,
+		&i.ValueFormat,
+		&i.ColorScale,
+		&i.Kind,
+		&i.Progress,
+		&i.DarkLabelColor,
+		&i.DarkColor,
+		&i.DarkTextColor,
+		&i.Animation,
+		&i.Font,
+		&i.FontSize,
+		&i.FontWeight,
+		&i.Segments,
 	)
 	return i, err
 }
@@ -92,17 +214,65 @@ SET subject = $2,
     status = $3,
     color = $4,
     style = $5,
+    logo = $6,
+    logo_color = $7,
+    logo_width = $8,
+    label_color = $9,
+    max_width = $10,
+    overflow = $11,
+    text_color = $12,
+    title = $13,
+    subject_link = $14,
+    status_link = $15,
+    value = $16,
+    unit = $17,
+    value_format = $18,
+    color_scale = $19,
+    kind = $20,
+    progress = $21,
+    dark_label_color = $22,
+    dark_color = $23,
+    dark_text_color = $24,
+    animation = $25,
+    font = $26,
+    font_size = $27,
+    font_weight = $28,
+    segments = $29,
     updated_at = now()
 WHERE id = $1
-RETURNING id, token_hash, subject, status, color, style, created_at, updated_at
+RETURNING id, token_hash, subject, status, color, style, created_at, updated_at, logo, logo_color, logo_width, label_color, max_width, overflow, text_color, title, subject_link, status_link, value, unit, value_format, color_scale, kind, progress, dark_label_color, dark_color, dark_text_color, animation, font, font_size, font_weight, segments
 `
 
 type UpdateBadgeParams struct {
-	ID      uuid.UUID `json:"id"`
-	Subject string    `json:"subject"`
-	Status  string    `json:"status"`
-	Color   string    `json:"color"`
-	Style   string    `json:"style"`
+	ID             uuid.UUID       `json:"id"`
+	Subject        string          `json:"subject"`
+	Status         string          `json:"status"`
+	Color          string          `json:"color"`
+	Style          string          `json:"style"`
+	Logo           string          `json:"logo"`
+	LogoColor      string          `json:"logo_color"`
+	LogoWidth      int32           `json:"logo_width"`
+	LabelColor     string          `json:"label_color"`
+	MaxWidth       int32           `json:"max_width"`
+	Overflow       string          `json:"overflow"`
+	TextColor      string          `json:"text_color"`
+	Title          string          `json:"title"`
+	SubjectLink    string          `json:"subject_link"`
+	StatusLink     string          `json:"status_link"`
+	Value          sql.NullFloat64 `json:"value"`
+	Unit           string          `json:"unit"`
+	ValueFormat    string          `json:"value_format"`
+	ColorScale     string          `json:"color_scale"`
+	Kind           string          `json:"kind"`
+	Progress       float64         `json:"progress"`
+	DarkLabelColor string          `json:"dark_label_color"`
+	DarkColor      string          `json:"dark_color"`
+	DarkTextColor  string          `json:"dark_text_color"`
+	Animation      string          `json:"animation"`
+	Font           string          `json:"font"`
+	FontSize       float64         `json:"font_size"`
+	FontWeight     string          `json:"font_weight"`
+	Segments       json.RawMessage `json:"segments"`
 }
 
 func (q *Queries) UpdateBadge(ctx context.Context, arg UpdateBadgeParams) (Badge, error) {
@@ -112,6 +282,30 @@ func (q *Queries) UpdateBadge(ctx context.Context, arg UpdateBadgeParams) (Badge
 		arg.Status,
 		arg.Color,
 		arg.Style,
+		arg.Logo,
+		arg.LogoColor,
+		arg.LogoWidth,
+		arg.LabelColor,
+		arg.MaxWidth,
+		arg.Overflow,
+		arg.TextColor,
+		arg.Title,
+		arg.SubjectLink,
+		arg.StatusLink,
+		arg.Value,
+		arg.Unit,
+		arg.ValueFormat,
+		arg.ColorScale,
+		arg.Kind,
+		arg.Progress,
+		arg.DarkLabelColor,
+		arg.DarkColor,
+		arg.DarkTextColor,
+		arg.Animation,
+		arg.Font,
+		arg.FontSize,
+		arg.FontWeight,
+		arg.Segments,
 	)
 	var i Badge
 	err := row.Scan(
@@ -123,6 +317,30 @@ func (q *Queries) UpdateBadge(ctx context.Context, arg UpdateBadgeParams) (Badge
 		&i.Style,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Logo,
+		&i.LogoColor,
+		&i.LogoWidth,
+		&i.LabelColor,
+		&i.MaxWidth,
+		&i.Overflow,
+		&i.TextColor,
+		&i.Title,
+		&i.SubjectLink,
+		&i.StatusLink,
+		&i.Value,
+		&i.Unit,
+		&i.ValueFormat,
+		&i.ColorScale,
+		&i.Kind,
+		&i.Progress,
+		&i.DarkLabelColor,
+		&i.DarkColor,
+		&i.DarkTextColor,
+		&i.Animation,
+		&i.Font,
+		&i.FontSize,
+		&i.FontWeight,
+		&i.Segments,
 	)
 	return i, err
 }
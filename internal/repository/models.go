@@ -5,18 +5,44 @@
 package repository
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Badge struct {
-	ID        uuid.UUID `json:"id"`
-	TokenHash string    `json:"token_hash"`
-	Subject   string    `json:"subject"`
-	Status    string    `json:"status"`
-	Color     string    `json:"color"`
-	Style     string    `json:"style"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID             uuid.UUID       `json:"id"`
+	TokenHash      string          `json:"token_hash"`
+	Subject        string          `json:"subject"`
+	Status         string          `json:"status"`
+	Color          string          `json:"color"`
+	Style          string          `json:"style"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	Logo           string          `json:"logo"`
+	LogoColor      string          `json:"logo_color"`
+	LogoWidth      int32           `json:"logo_width"`
+	LabelColor     string          `json:"label_color"`
+	MaxWidth       int32           `json:"max_width"`
+	Overflow       string          `json:"overflow"`
+	TextColor      string          `json:"text_color"`
+	Title          string          `json:"title"`
+	SubjectLink    string          `json:"subject_link"`
+	StatusLink     string          `json:"status_link"`
+	Value          sql.NullFloat64 `json:"value"`
+	Unit           string          `json:"unit"`
+	ValueFormat    string          `json:"value_format"`
+	ColorScale     string          `json:"color_scale"`
+	Kind           string          `json:"kind"`
+	Progress       float64         `json:"progress"`
+	DarkLabelColor string          `json:"dark_label_color"`
+	DarkColor      string          `json:"dark_color"`
+	DarkTextColor  string          `json:"dark_text_color"`
+	Animation      string          `json:"animation"`
+	Font           string          `json:"font"`
+	FontSize       float64         `json:"font_size"`
+	FontWeight     string          `json:"font_weight"`
+	Segments       json.RawMessage `json:"segments"`
 }
@@ -3,8 +3,11 @@ package service
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"slices"
 	"strings"
 	"time"
 
@@ -15,29 +18,144 @@ import (
 
 // Badge represents a stored badge definition.
 type Badge struct {
-	ID        uuid.UUID `json:"id"`
-	Subject   string    `json:"subject"`
-	Status    string    `json:"status"`
-	Color     string    `json:"color"`
-	Style     string    `json:"style"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         uuid.UUID `json:"id"`
+	Subject    string    `json:"subject"`
+	Status     string    `json:"status"`
+	Color      string    `json:"color"`
+	Style      string    `json:"style"`
+	LabelColor string    `json:"label_color"`
+	Logo       string    `json:"logo"`
+	LogoColor  string    `json:"logo_color"`
+	LogoWidth  int32     `json:"logo_width"`
+	MaxWidth   int32     `json:"max_width"`
+	Overflow   string    `json:"overflow"`
+	TextColor  string    `json:"text_color"`
+	Title      string    `json:"title"`
+	Links      [2]string `json:"links"`
+	// Value, when set, determines the status text and color; see BadgeInput.
+	// Unit is appended to the formatted value as is.
+	Value       *float64 `json:"value"`
+	Unit        string   `json:"unit"`
+	ValueFormat string   `json:"value_format"`
+	ColorScale  string   `json:"color_scale"`
+	Kind        string   `json:"kind"`
+	Progress    float64  `json:"progress"`
+	// Dark colors repaint the badge for readers who prefer a dark color scheme.
+	DarkLabelColor string    `json:"dark_label_color"`
+	DarkColor      string    `json:"dark_color"`
+	DarkTextColor  string    `json:"dark_text_color"`
+	Animation      string    `json:"animation"`
+	Font           string    `json:"font"`
+	FontSize       float64   `json:"font_size"`
+	FontWeight     string    `json:"font_weight"`
+	Segments       []Segment `json:"segments"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Segment is one part of a badge with segments; see renderer.Segment.
+type Segment struct {
+	Text  string `json:"text"`
+	Color string `json:"color,omitempty"`
+	Link  string `json:"link,omitempty"`
+	Logo  string `json:"logo,omitempty"`
 }
 
 // BadgeInput is used for create and full updates.
 type BadgeInput struct {
-	Subject string
-	Status  string
-	Color   string
-	Style   string
+	Subject    string
+	Status     string
+	Color      string
+	Style      string
+	LabelColor string
+	Logo       string
+	LogoColor  string
+	LogoWidth  int32
+	MaxWidth   int32
+	Overflow   string
+	TextColor  string
+	Title      string
+	Links      [2]string
+	// Value replaces Status with the value formatted by ValueFormat followed
+	// by Unit, and Color with its color on ColorScale. Empty ValueFormat prints
+	// the shortest decimal and empty ColorScale uses renderer.DefaultColorScale.
+	Value       *float64
+	Unit        string
+	ValueFormat string
+	ColorScale  string
+	// Kind is a renderer.Kind. Progress badges fill their status segment in
+	// proportion to Progress, which follows Value when one is set, and show
+	// the progress as a percentage when Status is empty.
+	Kind     string
+	Progress float64
+	// DarkLabelColor, DarkColor and DarkTextColor form the renderer.Palette
+	// used when the reader prefers a dark color scheme. Empty keeps the light
+	// color.
+	DarkLabelColor string
+	DarkColor      string
+	DarkTextColor  string
+	// Animation is a renderer.Animation, such as a spinner while a CI job
+	// runs. Empty draws a still badge.
+	Animation string
+	// Font names a font registered with the renderer, drawn at FontSize
+	// pixels and FontWeight. Empty keeps the renderer font at 11px regular.
+	Font       string
+	FontSize   float64
+	FontWeight string
+	// Segments replace Subject and Status with up to renderer.MaxSegments
+	// parts, each with its own color, link and logo. Subject, Status, Value,
+	// Logo and Links must then be empty.
+	Segments []Segment
+	RenderOptions
+}
+
+// RenderOptions control how a badge is drawn. They are not stored.
+type RenderOptions struct {
+	// IDPrefix namespaces the element ids of the rendered SVG.
+	IDPrefix string
+	// Scale multiplies the badge size. Zero means 1.
+	Scale float64
+	// Format selects SVG or PNG output. Empty means SVG.
+	Format renderer.Format
 }
 
 // BadgePatch is used for partial updates.
 type BadgePatch struct {
-	Subject *string
-	Status  *string
-	Color   *string
-	Style   *string
+	Subject    *string
+	Status     *string
+	Color      *string
+	Style      *string
+	LabelColor *string
+	Logo       *string
+	LogoColor  *string
+	LogoWidth  *int32
+	MaxWidth   *int32
+	Overflow   *string
+	TextColor  *string
+	Title      *string
+	Links      *[2]string
+	// Value sets the value. Patching Status or Color without it clears the
+	// value, so the badge keeps the given text and color.
+	Value       *float64
+	Unit        *string
+	ValueFormat *string
+	ColorScale  *string
+	Kind        *string
+	Progress    *float64
+	// Dark colors are patched one by one; an empty string removes one.
+	DarkLabelColor *string
+	DarkColor      *string
+	DarkTextColor  *string
+	// Animation is cleared with an empty string, e.g. once a job finishes.
+	Animation *string
+	// Font, FontSize and FontWeight are reset with an empty string and zero.
+	Font       *string
+	FontSize   *float64
+	FontWeight *string
+	// Segments replaces the segments. Non-empty segments clear Subject,
+	// Status, Value, Logo and Links unless they are patched too, and patching
+	// Subject or Status clears the segments.
+	Segments *[]Segment
 }
 
 var (
@@ -51,7 +169,16 @@ func (s *Service) CreateBadge(ctx context.Context, input BadgeInput) (Badge, str
 		return Badge{}, "", errors.New("service is not configured")
 	}
 
-	subject, status, color, style, err := normalizeBadgeInput(input.Subject, input.Status, input.Color, input.Style)
+	input, err := s.normalizeBadgeInput(input)
+	if err != nil {
+		return Badge{}, "", err
+	}
+	// Badges that cannot be drawn are rejected before they are stored.
+	if _, err = s.render(input); err != nil {
+		return Badge{}, "", err
+	}
+
+	segments, err := segmentsJSON(input.Segments)
 	if err != nil {
 		return Badge{}, "", err
 	}
@@ -62,17 +189,45 @@ func (s *Service) CreateBadge(ctx context.Context, input BadgeInput) (Badge, str
 	}
 
 	row, err := s.repo.CreateBadge(ctx, repository.CreateBadgeParams{
-		TokenHash: hash,
-		Subject:   subject,
-		Status:    status,
-		Color:     color,
-		Style:     style,
+		TokenHash:      hash,
+		Subject:        input.Subject,
+		Status:         input.Status,
+		Color:          input.Color,
+		Style:          input.Style,
+		LabelColor:     input.LabelColor,
+		Logo:           input.Logo,
+		LogoColor:      input.LogoColor,
+		LogoWidth:      input.LogoWidth,
+		MaxWidth:       input.MaxWidth,
+		Overflow:       input.Overflow,
+		TextColor:      input.TextColor,
+		Title:          input.Title,
+		SubjectLink:    input.Links[0],
+		StatusLink:     input.Links[1],
+		Value:          nullFloat(input.Value),
+		Unit:           input.Unit,
+		ValueFormat:    input.ValueFormat,
+		ColorScale:     input.ColorScale,
+		Kind:           input.Kind,
+		Progress:       input.Progress,
+		DarkLabelColor: input.DarkLabelColor,
+		DarkColor:      input.DarkColor,
+		DarkTextColor:  input.DarkTextColor,
+		Animation:      input.Animation,
+		Font:           input.Font,
+		FontSize:       input.FontSize,
+		FontWeight:     input.FontWeight,
+		Segments:       segments,
 	})
 	if err != nil {
 		return Badge{}, "", err
 	}
 
-	return toBadge(row), token, nil
+	badge, err := toBadge(row)
+	if err != nil {
+		return Badge{}, "", err
+	}
+	return badge, token, nil
 }
 
 // GetBadge fetches a badge by id.
@@ -84,40 +239,50 @@ func (s *Service) GetBadge(ctx context.Context, id uuid.UUID) (Badge, error) {
 		}
 		return Badge{}, err
 	}
-	return toBadge(row), nil
+	return toBadge(row)
 }
 
 // RenderBadge renders a stored badge.
-func (s *Service) RenderBadge(ctx context.Context, id uuid.UUID) (Badge, []byte, error) {
+func (s *Service) RenderBadge(ctx context.Context, id uuid.UUID, opts RenderOptions) (Badge, []byte, error) {
 	badge, err := s.GetBadge(ctx, id)
 	if err != nil {
 		return Badge{}, nil, err
 	}
 
-	svg, err := s.renderBadge(badge)
+	svg, err := s.renderBadge(badge, opts)
 	if err != nil {
 		return Badge{}, nil, err
 	}
 	return badge, svg, nil
 }
 
-func (s *Service) renderBadge(badge Badge) ([]byte, error) {
-	subject := badge.Subject
-	status := badge.Status
-	color := badge.Color
-	style := badge.Style
-
-	subject, status, color, style, err := normalizeBadgeInput(subject, status, color, style)
+func (s *Service) renderBadge(badge Badge, opts RenderOptions) ([]byte, error) {
+	input := badge.input()
+	input.RenderOptions = opts
+	input, err := s.normalizeBadgeInput(input)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.r.Render(renderer.Badge{
-		Subject: subject,
-		Status:  status,
-		Color:   renderer.Color(color),
-		Style:   renderer.Style(style),
-	})
+	return s.render(input)
+}
+
+// render draws normalized input in the requested format. A max width the
+// badge cannot fit in is invalid input.
+func (s *Service) render(input BadgeInput) ([]byte, error) {
+	var (
+		output []byte
+		err    error
+	)
+	if input.Format == renderer.FormatPNG {
+		output, err = s.r.RenderPNG(input.rendererBadge(), 1)
+	} else {
+		output, err = s.r.Render(input.rendererBadge())
+	}
+	if errors.Is(err, renderer.ErrMaxWidthTooSmall) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBadgeInput, err)
+	}
+	return output, err
 }
 
 // PatchBadge partially updates a badge definition after validating the token.
@@ -131,35 +296,52 @@ func (s *Service) PatchBadge(ctx context.Context, id uuid.UUID, token string, pa
 		return Badge{}, err
 	}
 
-	subject := current.Subject
-	status := current.Status
-	color := current.Color
-	style := current.Style
-
-	if patch.Subject != nil {
-		subject = strings.TrimSpace(*patch.Subject)
-	}
-	if patch.Status != nil {
-		status = strings.TrimSpace(*patch.Status)
+	badge, err := toBadge(current)
+	if err != nil {
+		return Badge{}, err
 	}
-	if patch.Color != nil {
-		color = strings.TrimSpace(*patch.Color)
+	input, err := s.normalizeBadgeInput(patch.apply(badge.input()))
+	if err != nil {
+		return Badge{}, err
 	}
-	if patch.Style != nil {
-		style = strings.TrimSpace(*patch.Style)
+	if _, err = s.render(input); err != nil {
+		return Badge{}, err
 	}
-
-	subject, status, color, style, err = normalizeBadgeInput(subject, status, color, style)
+	segments, err := segmentsJSON(input.Segments)
 	if err != nil {
 		return Badge{}, err
 	}
 
 	row, err := s.repo.UpdateBadge(ctx, repository.UpdateBadgeParams{
-		ID:      id,
-		Subject: subject,
-		Status:  status,
-		Color:   color,
-		Style:   style,
+		ID:             id,
+		Subject:        input.Subject,
+		Status:         input.Status,
+		Color:          input.Color,
+		Style:          input.Style,
+		LabelColor:     input.LabelColor,
+		Logo:           input.Logo,
+		LogoColor:      input.LogoColor,
+		LogoWidth:      input.LogoWidth,
+		MaxWidth:       input.MaxWidth,
+		Overflow:       input.Overflow,
+		TextColor:      input.TextColor,
+		Title:          input.Title,
+		SubjectLink:    input.Links[0],
+		StatusLink:     input.Links[1],
+		Value:          nullFloat(input.Value),
+		Unit:           input.Unit,
+		ValueFormat:    input.ValueFormat,
+		ColorScale:     input.ColorScale,
+		Kind:           input.Kind,
+		Progress:       input.Progress,
+		DarkLabelColor: input.DarkLabelColor,
+		DarkColor:      input.DarkColor,
+		DarkTextColor:  input.DarkTextColor,
+		Animation:      input.Animation,
+		Font:           input.Font,
+		FontSize:       input.FontSize,
+		FontWeight:     input.FontWeight,
+		Segments:       segments,
 	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -168,7 +350,7 @@ func (s *Service) PatchBadge(ctx context.Context, id uuid.UUID, token string, pa
 		return Badge{}, err
 	}
 
-	return toBadge(row), nil
+	return toBadge(row)
 }
 
 // DeleteBadge removes a badge definition after validating the token.
@@ -198,46 +380,475 @@ func (s *Service) authorize(ctx context.Context, id uuid.UUID, token string) (re
 	return row, nil
 }
 
-func toBadge(row repository.Badge) Badge {
+func toBadge(row repository.Badge) (Badge, error) {
+	var segments []Segment
+	if len(row.Segments) > 0 {
+		if err := json.Unmarshal(row.Segments, &segments); err != nil {
+			return Badge{}, fmt.Errorf("decode segments: %w", err)
+		}
+	}
 	return Badge{
-		ID:        row.ID,
-		Subject:   row.Subject,
-		Status:    row.Status,
-		Color:     row.Color,
-		Style:     row.Style,
-		CreatedAt: row.CreatedAt,
-		UpdatedAt: row.UpdatedAt,
+		ID:             row.ID,
+		Subject:        row.Subject,
+		Status:         row.Status,
+		Color:          row.Color,
+		Style:          row.Style,
+		LabelColor:     row.LabelColor,
+		Logo:           row.Logo,
+		LogoColor:      row.LogoColor,
+		LogoWidth:      row.LogoWidth,
+		MaxWidth:       row.MaxWidth,
+		Overflow:       row.Overflow,
+		TextColor:      row.TextColor,
+		Title:          row.Title,
+		Links:          [2]string{row.SubjectLink, row.StatusLink},
+		Value:          floatPointer(row.Value),
+		Unit:           row.Unit,
+		ValueFormat:    row.ValueFormat,
+		ColorScale:     row.ColorScale,
+		Kind:           row.Kind,
+		Progress:       row.Progress,
+		DarkLabelColor: row.DarkLabelColor,
+		DarkColor:      row.DarkColor,
+		DarkTextColor:  row.DarkTextColor,
+		Animation:      row.Animation,
+		Font:           row.Font,
+		FontSize:       row.FontSize,
+		FontWeight:     row.FontWeight,
+		Segments:       segments,
+		CreatedAt:      row.CreatedAt,
+		UpdatedAt:      row.UpdatedAt,
+	}, nil
+}
+
+func (b Badge) input() BadgeInput {
+	return BadgeInput{
+		Subject:        b.Subject,
+		Status:         b.Status,
+		Color:          b.Color,
+		Style:          b.Style,
+		LabelColor:     b.LabelColor,
+		Logo:           b.Logo,
+		LogoColor:      b.LogoColor,
+		LogoWidth:      b.LogoWidth,
+		MaxWidth:       b.MaxWidth,
+		Overflow:       b.Overflow,
+		TextColor:      b.TextColor,
+		Title:          b.Title,
+		Links:          b.Links,
+		Value:          b.Value,
+		Unit:           b.Unit,
+		ValueFormat:    b.ValueFormat,
+		ColorScale:     b.ColorScale,
+		Kind:           b.Kind,
+		Progress:       b.Progress,
+		DarkLabelColor: b.DarkLabelColor,
+		DarkColor:      b.DarkColor,
+		DarkTextColor:  b.DarkTextColor,
+		Animation:      b.Animation,
+		Font:           b.Font,
+		FontSize:       b.FontSize,
+		FontWeight:     b.FontWeight,
+		Segments:       b.Segments,
+	}
+}
+
+// segmentsJSON encodes segments for storage, as an empty array when there
+// are none.
+func segmentsJSON(segments []Segment) (json.RawMessage, error) {
+	if segments == nil {
+		segments = []Segment{}
+	}
+	return json.Marshal(segments)
+}
+
+func nullFloat(v *float64) sql.NullFloat64 {
+	if v == nil {
+		return sql.NullFloat64{}
 	}
+	return sql.NullFloat64{Float64: *v, Valid: true}
 }
 
-func normalizeBadgeInput(subject, status, color, style string) (string, string, string, string, error) {
-	subject = strings.TrimSpace(subject)
-	status = strings.TrimSpace(status)
-	color = strings.TrimSpace(color)
-	style = strings.TrimSpace(style)
+func floatPointer(v sql.NullFloat64) *float64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Float64
+}
 
-	if subject == "" {
-		return "", "", "", "", fmt.Errorf("%w: subject is required", ErrInvalidBadgeInput)
+func (p BadgePatch) apply(input BadgeInput) BadgeInput {
+	if p.Subject != nil {
+		input.Subject = *p.Subject
+	}
+	if p.Status != nil {
+		input.Status = *p.Status
+	}
+	if p.Color != nil {
+		input.Color = *p.Color
+	}
+	if p.Style != nil {
+		input.Style = *p.Style
+	}
+	if p.LabelColor != nil {
+		input.LabelColor = *p.LabelColor
+	}
+	if p.Logo != nil {
+		input.Logo = *p.Logo
+	}
+	if p.LogoColor != nil {
+		input.LogoColor = *p.LogoColor
+	}
+	if p.LogoWidth != nil {
+		input.LogoWidth = *p.LogoWidth
+	}
+	if p.MaxWidth != nil {
+		input.MaxWidth = *p.MaxWidth
+	}
+	if p.Overflow != nil {
+		input.Overflow = *p.Overflow
+	}
+	if p.TextColor != nil {
+		input.TextColor = *p.TextColor
+	}
+	if p.Title != nil {
+		input.Title = *p.Title
+	}
+	if p.Links != nil {
+		input.Links = *p.Links
+	}
+	if p.Value != nil {
+		input.Value = p.Value
+	} else if p.Status != nil || p.Color != nil {
+		input.Value = nil
+	}
+	if p.Unit != nil {
+		input.Unit = *p.Unit
 	}
-	if status == "" {
-		return "", "", "", "", fmt.Errorf("%w: status is required", ErrInvalidBadgeInput)
+	if p.ValueFormat != nil {
+		input.ValueFormat = *p.ValueFormat
 	}
-	if color == "" {
-		return "", "", "", "", fmt.Errorf("%w: color is required", ErrInvalidBadgeInput)
+	if p.ColorScale != nil {
+		input.ColorScale = *p.ColorScale
 	}
-	if style == "" {
-		style = string(renderer.StyleFlat)
+	if p.Kind != nil {
+		input.Kind = *p.Kind
 	}
+	if p.Progress != nil {
+		input.Progress = *p.Progress
+	}
+	if p.DarkLabelColor != nil {
+		input.DarkLabelColor = *p.DarkLabelColor
+	}
+	if p.DarkColor != nil {
+		input.DarkColor = *p.DarkColor
+	}
+	if p.DarkTextColor != nil {
+		input.DarkTextColor = *p.DarkTextColor
+	}
+	if p.Animation != nil {
+		input.Animation = *p.Animation
+	}
+	if p.Font != nil {
+		input.Font = *p.Font
+	}
+	if p.FontSize != nil {
+		input.FontSize = *p.FontSize
+	}
+	if p.FontWeight != nil {
+		input.FontWeight = *p.FontWeight
+	}
+	if p.Segments != nil {
+		input.Segments = *p.Segments
+		if len(input.Segments) > 0 {
+			input = p.clearReplaced(input)
+		}
+	} else if p.Subject != nil || p.Status != nil {
+		input.Segments = nil
+	}
+	return input
+}
 
-	badgeColor := renderer.Color(color)
+// clearReplaced clears the fields that segments replace, unless the patch
+// sets them.
+func (p BadgePatch) clearReplaced(input BadgeInput) BadgeInput {
+	if p.Subject == nil {
+		input.Subject = ""
+	}
+	if p.Status == nil {
+		input.Status = ""
+	}
+	if p.Value == nil {
+		input.Value = nil
+	}
+	if p.Logo == nil {
+		input.Logo = ""
+	}
+	if p.Links == nil {
+		input.Links = [2]string{}
+	}
+	return input
+}
+
+func (input BadgeInput) rendererBadge() renderer.Badge {
+	return renderer.Badge{
+		Subject:    input.Subject,
+		Status:     input.Status,
+		Color:      renderer.Color(input.Color),
+		Style:      renderer.Style(input.Style),
+		LabelColor: renderer.Color(input.LabelColor),
+		Logo:       renderer.Logo(input.Logo),
+		LogoColor:  renderer.Color(input.LogoColor),
+		LogoWidth:  int(input.LogoWidth),
+		MaxWidth:   int(input.MaxWidth),
+		Overflow:   renderer.Overflow(input.Overflow),
+		TextColor:  renderer.Color(input.TextColor),
+		Title:      input.Title,
+		Links:      input.Links,
+		Kind:       renderer.Kind(input.Kind),
+		Progress:   input.Progress,
+		Dark: renderer.Palette{
+			LabelColor: renderer.Color(input.DarkLabelColor),
+			Color:      renderer.Color(input.DarkColor),
+			TextColor:  renderer.Color(input.DarkTextColor),
+		},
+		Animation:  renderer.Animation(input.Animation),
+		Font:       input.Font,
+		FontSize:   input.FontSize,
+		FontWeight: renderer.FontWeight(input.FontWeight),
+		Segments:   input.rendererSegments(),
+		IDPrefix:   input.IDPrefix,
+		Scale:      input.Scale,
+	}
+}
+
+func (input BadgeInput) rendererSegments() []renderer.Segment {
+	if len(input.Segments) == 0 {
+		return nil
+	}
+	segments := make([]renderer.Segment, len(input.Segments))
+	for i, s := range input.Segments {
+		segments[i] = renderer.Segment{
+			Text:  s.Text,
+			Color: renderer.Color(s.Color),
+			Link:  s.Link,
+			Logo:  renderer.Logo(s.Logo),
+		}
+	}
+	return segments
+}
+
+func (s *Service) normalizeBadgeInput(input BadgeInput) (BadgeInput, error) {
+	input.Subject = strings.TrimSpace(input.Subject)
+	input.Status = strings.TrimSpace(input.Status)
+	input.Color = strings.TrimSpace(input.Color)
+	input.Style = strings.TrimSpace(input.Style)
+	input.LabelColor = strings.TrimSpace(input.LabelColor)
+	input.Logo = strings.TrimSpace(input.Logo)
+	input.LogoColor = strings.TrimSpace(input.LogoColor)
+	input.Overflow = strings.TrimSpace(input.Overflow)
+	input.TextColor = strings.TrimSpace(input.TextColor)
+	input.DarkLabelColor = strings.TrimSpace(input.DarkLabelColor)
+	input.DarkColor = strings.TrimSpace(input.DarkColor)
+	input.DarkTextColor = strings.TrimSpace(input.DarkTextColor)
+	input.Title = strings.TrimSpace(input.Title)
+	input.IDPrefix = strings.TrimSpace(input.IDPrefix)
+	for i, link := range input.Links {
+		input.Links[i] = strings.TrimSpace(link)
+	}
+	input.ValueFormat = strings.TrimSpace(input.ValueFormat)
+	input.ColorScale = strings.TrimSpace(input.ColorScale)
+	input.Kind = strings.TrimSpace(input.Kind)
+	input.Animation = strings.TrimSpace(input.Animation)
+	input.Font = strings.TrimSpace(input.Font)
+	input.FontWeight = strings.TrimSpace(input.FontWeight)
+	// Segments are checked first, as applyValue sets the status they replace.
+	input, err := normalizeSegments(input)
+	if err != nil {
+		return BadgeInput{}, err
+	}
+	segmented := len(input.Segments) > 0
+	input, err = applyValue(input)
+	if err != nil {
+		return BadgeInput{}, err
+	}
+	progress := renderer.Kind(input.Kind) == renderer.KindProgress
+	if progress && input.Value != nil {
+		input.Progress = *input.Value
+	}
+
+	if input.Subject == "" && !segmented {
+		return BadgeInput{}, fmt.Errorf("%w: subject is required", ErrInvalidBadgeInput)
+	}
+	if input.Status == "" && !progress && !segmented {
+		return BadgeInput{}, fmt.Errorf("%w: status is required", ErrInvalidBadgeInput)
+	}
+	if input.Color == "" && (!segmented || renderer.SegmentsUseColor(input.rendererSegments())) {
+		return BadgeInput{}, fmt.Errorf("%w: color is required", ErrInvalidBadgeInput)
+	}
+	if input.Style == "" {
+		input.Style = string(renderer.StyleFlat)
+	}
+
+	badgeColor := renderer.Color(input.Color)
 	if !badgeColor.IsValid() {
-		return "", "", "", "", fmt.Errorf("%w: invalid color %q", ErrInvalidBadgeInput, color)
+		return BadgeInput{}, fmt.Errorf("%w: invalid color %q", ErrInvalidBadgeInput, input.Color)
 	}
 
-	badgeStyle := renderer.Style(style)
-	if !badgeStyle.IsValid() {
-		return "", "", "", "", fmt.Errorf("%w: invalid style %q", ErrInvalidBadgeInput, style)
+	if !renderer.Color(input.LabelColor).IsValid() {
+		return BadgeInput{}, fmt.Errorf("%w: invalid label color %q", ErrInvalidBadgeInput, input.LabelColor)
+	}
+	if !renderer.Color(input.TextColor).IsValid() {
+		return BadgeInput{}, fmt.Errorf("%w: invalid text color %q", ErrInvalidBadgeInput, input.TextColor)
+	}
+	if !renderer.Color(input.DarkLabelColor).IsValid() {
+		return BadgeInput{}, fmt.Errorf("%w: invalid dark label color %q", ErrInvalidBadgeInput, input.DarkLabelColor)
+	}
+	if !renderer.Color(input.DarkColor).IsValid() {
+		return BadgeInput{}, fmt.Errorf("%w: invalid dark color %q", ErrInvalidBadgeInput, input.DarkColor)
+	}
+	if !renderer.Color(input.DarkTextColor).IsValid() {
+		return BadgeInput{}, fmt.Errorf("%w: invalid dark text color %q", ErrInvalidBadgeInput, input.DarkTextColor)
+	}
+	if !renderer.Animation(input.Animation).IsValid() {
+		return BadgeInput{}, fmt.Errorf("%w: invalid animation %q", ErrInvalidBadgeInput, input.Animation)
+	}
+	if !renderer.FontWeight(input.FontWeight).IsValid() {
+		return BadgeInput{}, fmt.Errorf("%w: invalid font weight %q", ErrInvalidBadgeInput, input.FontWeight)
+	}
+	if !renderer.ValidFontSize(input.FontSize) {
+		return BadgeInput{}, fmt.Errorf("%w: invalid font size %v", ErrInvalidBadgeInput, input.FontSize)
+	}
+	if input.Font != "" && !s.r.HasFont(input.Font, renderer.FontWeight(input.FontWeight)) {
+		return BadgeInput{}, fmt.Errorf("%w: unknown font %q", ErrInvalidBadgeInput, input.Font)
+	}
+
+	if !s.r.HasStyle(renderer.Style(input.Style)) {
+		return BadgeInput{}, fmt.Errorf("%w: invalid style %q", ErrInvalidBadgeInput, input.Style)
+	}
+	if segmented && !s.r.HasSegments(renderer.Style(input.Style)) {
+		return BadgeInput{}, fmt.Errorf("%w: segments are not supported for style %q", ErrInvalidBadgeInput, input.Style)
 	}
 
-	return subject, status, color, style, nil
+	if err = renderer.Logo(input.Logo).Validate(); err != nil {
+		return BadgeInput{}, fmt.Errorf("%w: %w", ErrInvalidBadgeInput, err)
+	}
+	if !renderer.Color(input.LogoColor).IsValid() {
+		return BadgeInput{}, fmt.Errorf("%w: invalid logo color %q", ErrInvalidBadgeInput, input.LogoColor)
+	}
+	if input.LogoWidth < 0 || input.LogoWidth > renderer.MaxLogoWidth {
+		return BadgeInput{}, fmt.Errorf("%w: invalid logo width %d", ErrInvalidBadgeInput, input.LogoWidth)
+	}
+
+	if input.MaxWidth < 0 {
+		return BadgeInput{}, fmt.Errorf("%w: invalid max width %d", ErrInvalidBadgeInput, input.MaxWidth)
+	}
+	if !renderer.Overflow(input.Overflow).IsValid() {
+		return BadgeInput{}, fmt.Errorf("%w: invalid overflow %q", ErrInvalidBadgeInput, input.Overflow)
+	}
+	if !renderer.Kind(input.Kind).IsValid() {
+		return BadgeInput{}, fmt.Errorf("%w: invalid kind %q", ErrInvalidBadgeInput, input.Kind)
+	}
+	if progress && !renderer.ValidProgress(input.Progress) {
+		return BadgeInput{}, fmt.Errorf("%w: invalid progress %v", ErrInvalidBadgeInput, input.Progress)
+	}
+	if progress && !slices.Contains(renderer.ProgressStyles(), renderer.Style(input.Style)) {
+		return BadgeInput{}, fmt.Errorf("%w: progress badges are not supported for style %q", ErrInvalidBadgeInput, input.Style)
+	}
+	for _, link := range input.Links {
+		if !renderer.ValidLink(link) {
+			return BadgeInput{}, fmt.Errorf("%w: invalid link %q", ErrInvalidBadgeInput, link)
+		}
+	}
+	if !renderer.ValidIDPrefix(input.IDPrefix) {
+		return BadgeInput{}, fmt.Errorf("%w: invalid id prefix %q", ErrInvalidBadgeInput, input.IDPrefix)
+	}
+	if math.IsNaN(input.Scale) || input.Scale < 0 || input.Scale > renderer.MaxScale {
+		return BadgeInput{}, fmt.Errorf("%w: invalid scale %v", ErrInvalidBadgeInput, input.Scale)
+	}
+	if !input.Format.IsValid() {
+		return BadgeInput{}, fmt.Errorf("%w: invalid format %q", ErrInvalidBadgeInput, input.Format)
+	}
+	if input.Format == renderer.FormatPNG && !renderer.Style(input.Style).IsValid() {
+		return BadgeInput{}, fmt.Errorf("%w: png output is not supported for style %q", ErrInvalidBadgeInput, input.Style)
+	}
+
+	return input, nil
+}
+
+// normalizeSegments trims and validates the segments of input and checks
+// the fields they replace are empty.
+func normalizeSegments(input BadgeInput) (BadgeInput, error) {
+	if len(input.Segments) == 0 {
+		input.Segments = nil
+		return input, nil
+	}
+	if len(input.Segments) > renderer.MaxSegments {
+		return BadgeInput{}, fmt.Errorf("%w: too many segments %d, at most %d", ErrInvalidBadgeInput, len(input.Segments), renderer.MaxSegments)
+	}
+	switch {
+	case input.Subject != "" || input.Status != "":
+		return BadgeInput{}, fmt.Errorf("%w: segments replace subject and status", ErrInvalidBadgeInput)
+	case input.Value != nil:
+		return BadgeInput{}, fmt.Errorf("%w: value is not supported with segments", ErrInvalidBadgeInput)
+	case input.Logo != "" || input.Links != [2]string{}:
+		return BadgeInput{}, fmt.Errorf("%w: segments carry their own logos and links", ErrInvalidBadgeInput)
+	case renderer.Kind(input.Kind) == renderer.KindProgress:
+		return BadgeInput{}, fmt.Errorf("%w: progress badges do not support segments", ErrInvalidBadgeInput)
+	default:
+	}
+	segments := make([]Segment, len(input.Segments))
+	for i, segment := range input.Segments {
+		segment.Text = strings.TrimSpace(segment.Text)
+		segment.Color = strings.TrimSpace(segment.Color)
+		segment.Link = strings.TrimSpace(segment.Link)
+		segment.Logo = strings.TrimSpace(segment.Logo)
+		if segment.Text == "" && segment.Logo == "" {
+			return BadgeInput{}, fmt.Errorf("%w: segment %d has neither text nor logo", ErrInvalidBadgeInput, i)
+		}
+		if !renderer.Color(segment.Color).IsValid() {
+			return BadgeInput{}, fmt.Errorf("%w: invalid segment color %q", ErrInvalidBadgeInput, segment.Color)
+		}
+		if !renderer.ValidLink(segment.Link) {
+			return BadgeInput{}, fmt.Errorf("%w: invalid link %q", ErrInvalidBadgeInput, segment.Link)
+		}
+		if err := renderer.Logo(segment.Logo).Validate(); err != nil {
+			return BadgeInput{}, fmt.Errorf("%w: %w", ErrInvalidBadgeInput, err)
+		}
+		segments[i] = segment
+	}
+	input.Segments = segments
+	return input, nil
+}
+
+// applyValue validates the value settings and, when a value is set, derives
+// the status and color from it.
+func applyValue(input BadgeInput) (BadgeInput, error) {
+	if !renderer.ValidValueFormat(input.ValueFormat) {
+		return BadgeInput{}, fmt.Errorf("%w: invalid value format %q", ErrInvalidBadgeInput, input.ValueFormat)
+	}
+	scaleText := renderer.DefaultColorScale
+	if input.ColorScale != "" {
+		scaleText = input.ColorScale
+	}
+	scale, err := renderer.ParseColorScale(scaleText)
+	if err != nil {
+		return BadgeInput{}, fmt.Errorf("%w: %w", ErrInvalidBadgeInput, err)
+	}
+	if input.ColorScale != "" {
+		input.ColorScale = scale.String()
+	}
+	if input.Value == nil {
+		return input, nil
+	}
+	value := *input.Value
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return BadgeInput{}, fmt.Errorf("%w: invalid value %v", ErrInvalidBadgeInput, value)
+	}
+	status, err := renderer.FormatValue(value, input.ValueFormat)
+	if err != nil {
+		return BadgeInput{}, fmt.Errorf("%w: %w", ErrInvalidBadgeInput, err)
+	}
+	input.Status = status + input.Unit
+	input.Color = string(scale.Color(value))
+	return input, nil
 }
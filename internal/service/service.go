@@ -34,6 +34,11 @@ func New(r *renderer.Renderer, repo BadgeRepository, tokens *TokenManager) (*Ser
 	}, nil
 }
 
+// Styles returns the built-in and registered badge styles.
+func (s *Service) Styles() []renderer.Style {
+	return s.r.Styles()
+}
+
 // BadgeRepository defines the data access needed by the service layer.
 type BadgeRepository interface {
 	CreateBadge(ctx context.Context, arg repository.CreateBadgeParams) (repository.Badge, error)
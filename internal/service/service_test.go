@@ -1,9 +1,12 @@
 package service_test
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"errors"
+	"math"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -13,6 +16,8 @@ import (
 	"github.com/rhajizada/signum/internal/service"
 	"github.com/rhajizada/signum/pkg/renderer"
 	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
 )
 
 type fakeRepo struct {
@@ -83,7 +88,7 @@ func TestGetLiveBadgeNormalizesInput(t *testing.T) {
 	if err != nil {
 		t.Fatalf("new service: %v", err)
 	}
-	output, err := svc.GetLiveBadge(" subject ", " status ", " green ", "")
+	output, err := svc.GetLiveBadge(service.BadgeInput{Subject: " subject ", Status: " status ", Color: " green "})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -141,6 +146,108 @@ func TestCreateBadge(t *testing.T) {
 	}
 }
 
+func TestCreateBadgeValue(t *testing.T) {
+	repo := &fakeRepo{
+		createFn: func(_ context.Context, arg repository.CreateBadgeParams) (repository.Badge, error) {
+			if arg.Status != "12 ms" || arg.Color != "yellow" || !arg.Value.Valid || arg.Value.Float64 != 12.4 {
+				t.Fatalf("unexpected create params: %#v", arg)
+			}
+			return repository.Badge{Subject: arg.Subject, Status: arg.Status, Color: arg.Color, Value: arg.Value}, nil
+		},
+	}
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	svc, err := service.New(newRenderer(t), repo, tokens)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	_, _, err = svc.CreateBadge(context.Background(), service.BadgeInput{
+		Subject:     "latency",
+		Value:       ptr(12.4),
+		Unit:        " ms",
+		ValueFormat: "%d",
+		ColorScale:  ">50 red, >10 yellow, else green",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCreateBadgeMaxWidthTooSmall(t *testing.T) {
+	repo := &fakeRepo{
+		createFn: func(_ context.Context, arg repository.CreateBadgeParams) (repository.Badge, error) {
+			t.Fatalf("unrenderable badge was stored: %#v", arg)
+			return repository.Badge{}, nil
+		},
+	}
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	svc, err := service.New(newRenderer(t), repo, tokens)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	_, _, err = svc.CreateBadge(context.Background(), service.BadgeInput{
+		Subject:  "build",
+		Status:   "passing",
+		Color:    "green",
+		MaxWidth: 5,
+	})
+	if !errors.Is(err, service.ErrInvalidBadgeInput) || !errors.Is(err, renderer.ErrMaxWidthTooSmall) {
+		t.Fatalf("expected max width too small error, got %v", err)
+	}
+}
+
+func TestCreateBadgeProgress(t *testing.T) {
+	repo := &fakeRepo{
+		createFn: func(_ context.Context, arg repository.CreateBadgeParams) (repository.Badge, error) {
+			if arg.Kind != "progress" || arg.Progress != 62.5 || arg.Status != "62.5%" || arg.Color != "yellow" {
+				t.Fatalf("unexpected create params: %#v", arg)
+			}
+			return repository.Badge{Subject: arg.Subject, Status: arg.Status, Color: arg.Color, Kind: arg.Kind, Progress: arg.Progress}, nil
+		},
+	}
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	svc, err := service.New(newRenderer(t), repo, tokens)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	badge, _, err := svc.CreateBadge(context.Background(), service.BadgeInput{
+		Subject:  "coverage",
+		Kind:     "progress",
+		Progress: 10,
+		Value:    ptr(62.5),
+		Unit:     "%",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if badge.Kind != "progress" || badge.Progress != 62.5 {
+		t.Fatalf("unexpected badge: %#v", badge)
+	}
+
+	for _, input := range []service.BadgeInput{
+		{Subject: "coverage", Color: "green", Kind: "bar"},
+		{Subject: "coverage", Color: "green", Kind: "progress", Progress: 101},
+		{Subject: "coverage", Color: "green", Kind: "progress", Progress: math.NaN()},
+		{Subject: "coverage", Color: "green", Kind: "progress", Style: "social"},
+		{Subject: "coverage", Color: "green", Kind: "status"},
+	} {
+		if _, _, err = svc.CreateBadge(context.Background(), input); !errors.Is(err, service.ErrInvalidBadgeInput) {
+			t.Fatalf("%+v: expected invalid input, got %v", input, err)
+		}
+	}
+}
+
 func TestCreateBadgeUnconfigured(t *testing.T) {
 	var svc *service.Service
 	if _, _, err := svc.CreateBadge(context.Background(), service.BadgeInput{}); err == nil {
@@ -240,6 +347,323 @@ func TestPatchBadgeSuccess(t *testing.T) {
 	}
 }
 
+// patchStep is one successful patch of a stored badge and the checks on the
+// returned badge and the row saved for it.
+type patchStep struct {
+	patch service.BadgePatch
+	check func(t *testing.T, badge service.Badge, stored repository.Badge)
+	// svg, when set, must appear in the stored badge rendered after the patch.
+	svg string
+}
+
+func TestPatchBadgeFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		stored  repository.Badge
+		font    []byte
+		steps   []patchStep
+		invalid []service.BadgePatch
+	}{
+		{
+			name:   "label color",
+			stored: repository.Badge{Subject: "build", Status: "passing", Color: "green", Style: "flat", LabelColor: "blue"},
+			steps: []patchStep{{
+				patch: service.BadgePatch{LabelColor: ptr(" #333 ")},
+				check: func(t *testing.T, badge service.Badge, stored repository.Badge) {
+					if badge.LabelColor != "#333" || stored.Subject != "build" {
+						t.Fatalf("unexpected badge: %#v", badge)
+					}
+				},
+			}},
+			invalid: []service.BadgePatch{{LabelColor: ptr("not-a-color")}},
+		},
+		{
+			name:   "dark palette",
+			stored: repository.Badge{Subject: "build", Status: "passing", Color: "green", Style: "flat", DarkLabelColor: "#222"},
+			steps: []patchStep{{
+				patch: service.BadgePatch{DarkColor: ptr(" darkgreen ")},
+				check: func(t *testing.T, badge service.Badge, stored repository.Badge) {
+					if badge.DarkLabelColor != "#222" || badge.DarkColor != "darkgreen" || stored.DarkTextColor != "" {
+						t.Fatalf("unexpected badge: %#v", badge)
+					}
+				},
+			}},
+			invalid: []service.BadgePatch{{DarkTextColor: ptr("not-a-color")}},
+		},
+		{
+			name:   "animation",
+			stored: repository.Badge{Subject: "deploy", Status: "running", Color: "blue", Style: "flat", Animation: "spinner"},
+			steps: []patchStep{{
+				patch: service.BadgePatch{Status: ptr("passed"), Color: ptr("green"), Animation: ptr("")},
+				check: func(t *testing.T, badge service.Badge, stored repository.Badge) {
+					if badge.Status != "passed" || badge.Animation != "" || stored.Color != "green" {
+						t.Fatalf("unexpected badge: %#v", badge)
+					}
+				},
+			}},
+			invalid: []service.BadgePatch{{Animation: ptr("spin")}},
+		},
+		{
+			name:   "font",
+			stored: repository.Badge{Subject: "release", Status: "v1.2.3", Color: "blue", Style: "flat", Font: "Go"},
+			font:   goregular.TTF,
+			steps: []patchStep{{
+				patch: service.BadgePatch{FontSize: ptr(13.0), FontWeight: ptr("bold")},
+				check: func(t *testing.T, badge service.Badge, _ repository.Badge) {
+					if badge.Font != "Go" || badge.FontSize != 13 || badge.FontWeight != "bold" {
+						t.Fatalf("unexpected badge: %#v", badge)
+					}
+				},
+			}},
+			invalid: []service.BadgePatch{{Font: ptr("Go Mono")}},
+		},
+		{
+			name:   "max width",
+			stored: repository.Badge{Subject: "branch", Status: "main", Color: "blue", Style: "flat", MaxWidth: 200},
+			steps: []patchStep{{
+				patch: service.BadgePatch{Overflow: ptr("shrink")},
+				check: func(t *testing.T, badge service.Badge, _ repository.Badge) {
+					if badge.MaxWidth != 200 || badge.Overflow != "shrink" {
+						t.Fatalf("unexpected badge: %#v", badge)
+					}
+				},
+			}},
+			invalid: []service.BadgePatch{{MaxWidth: ptr(int32(-5))}, {MaxWidth: ptr(int32(5))}},
+		},
+		{
+			name:   "links",
+			stored: repository.Badge{Subject: "docs", Status: "latest", Color: "blue", Style: "flat"},
+			steps: []patchStep{{
+				patch: service.BadgePatch{Links: &[2]string{" https://example.com/docs "}},
+				check: func(t *testing.T, badge service.Badge, _ repository.Badge) {
+					if badge.Links != [2]string{"https://example.com/docs", ""} {
+						t.Fatalf("unexpected badge links: %#v", badge.Links)
+					}
+				},
+				svg: `xlink:href="https://example.com/docs"`,
+			}},
+			invalid: []service.BadgePatch{{Links: &[2]string{"", "javascript:alert(1)"}}},
+		},
+		{
+			name: "value",
+			stored: repository.Badge{
+				Subject:     "coverage",
+				Status:      "unknown",
+				Color:       "lightgrey",
+				Style:       "flat",
+				Unit:        "%",
+				ValueFormat: "%.1f",
+			},
+			steps: []patchStep{
+				{
+					patch: service.BadgePatch{Value: ptr(87.34)},
+					check: func(t *testing.T, badge service.Badge, _ repository.Badge) {
+						if badge.Status != "87.3%" || badge.Color != "brightgreen" || badge.Value == nil || *badge.Value != 87.34 {
+							t.Fatalf("unexpected badge: %#v", badge)
+						}
+					},
+				},
+				{
+					patch: service.BadgePatch{Value: ptr(42.0), ColorScale: ptr(" >90 red ,>=50 yellow, else green ")},
+					check: func(t *testing.T, badge service.Badge, _ repository.Badge) {
+						if badge.Status != "42.0%" || badge.Color != "green" || badge.ColorScale != ">90 red, >=50 yellow, else green" {
+							t.Fatalf("unexpected badge: %#v", badge)
+						}
+					},
+				},
+				{
+					patch: service.BadgePatch{Status: ptr("flaky")},
+					check: func(t *testing.T, badge service.Badge, _ repository.Badge) {
+						if badge.Status != "flaky" || badge.Color != "green" || badge.Value != nil {
+							t.Fatalf("expected the patched status to clear the value: %#v", badge)
+						}
+					},
+				},
+			},
+			invalid: []service.BadgePatch{
+				{ColorScale: ptr("<50 red")},
+				{ValueFormat: ptr("%s")},
+				{Value: ptr(math.Inf(1))},
+			},
+		},
+		{
+			name: "segments",
+			stored: repository.Badge{
+				Subject:     "build",
+				Status:      "passing",
+				Color:       "green",
+				Style:       "flat",
+				Logo:        "bolt",
+				SubjectLink: "https://example.com",
+			},
+			steps: []patchStep{
+				{
+					patch: service.BadgePatch{Segments: &[]service.Segment{{Text: "build", Logo: "bolt"}, {Text: "linux"}, {Text: "passing"}}},
+					check: func(t *testing.T, badge service.Badge, _ repository.Badge) {
+						if badge.Subject != "" || badge.Status != "" || badge.Logo != "" || badge.Links != [2]string{} || len(badge.Segments) != 3 {
+							t.Fatalf("expected the segments to replace subject and status: %#v", badge)
+						}
+					},
+				},
+				{
+					patch: service.BadgePatch{Subject: ptr("build"), Status: ptr("failing")},
+					check: func(t *testing.T, badge service.Badge, stored repository.Badge) {
+						if badge.Subject != "build" || badge.Status != "failing" || len(badge.Segments) != 0 || string(stored.Segments) != "[]" {
+							t.Fatalf("expected subject and status to clear the segments: %#v", badge)
+						}
+					},
+				},
+			},
+			invalid: []service.BadgePatch{{Segments: &[]service.Segment{{Text: "a"}, {Text: "b"}}, Status: ptr("passing")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRenderer(t)
+			if tt.font != nil {
+				if err := r.RegisterFont(tt.font); err != nil {
+					t.Fatalf("register font: %v", err)
+				}
+			}
+			svc, id, token, stored := newPatchTestService(t, r, tt.stored)
+
+			for _, step := range tt.steps {
+				badge, err := svc.PatchBadge(context.Background(), id, token, step.patch)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				step.check(t, badge, *stored)
+
+				_, svg, err := svc.RenderBadge(context.Background(), id, service.RenderOptions{})
+				if err != nil {
+					t.Fatalf("render: %v", err)
+				}
+				if !strings.Contains(string(svg), step.svg) {
+					t.Fatalf("expected %q in stored badge svg: %s", step.svg, svg)
+				}
+			}
+
+			before := *stored
+			for _, patch := range tt.invalid {
+				_, err := svc.PatchBadge(context.Background(), id, token, patch)
+				if !errors.Is(err, service.ErrInvalidBadgeInput) {
+					t.Fatalf("%+v: expected invalid input error, got %v", patch, err)
+				}
+			}
+			if !reflect.DeepEqual(*stored, before) {
+				t.Fatalf("invalid patch was stored: %#v", *stored)
+			}
+		})
+	}
+}
+
+// newPatchTestService returns a service over a single stored badge row that
+// the repository updates in place, with the id and token to patch it with.
+func newPatchTestService(t *testing.T, r *renderer.Renderer, row repository.Badge) (*service.Service, uuid.UUID, string, *repository.Badge) {
+	t.Helper()
+	token := "token"
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	hash, err := tokens.HashToken(token)
+	if err != nil {
+		t.Fatalf("hash token: %v", err)
+	}
+	row.ID = uuid.New()
+	row.TokenHash = hash
+	stored := &row
+	repo := &fakeRepo{
+		getFn: func(_ context.Context, _ uuid.UUID) (repository.Badge, error) {
+			return *stored, nil
+		},
+		updateFn: func(_ context.Context, arg repository.UpdateBadgeParams) (repository.Badge, error) {
+			if arg.ID != stored.ID {
+				t.Fatalf("unexpected update id: %s", arg.ID)
+			}
+			stored.Subject, stored.Status, stored.Color, stored.Style = arg.Subject, arg.Status, arg.Color, arg.Style
+			stored.Logo, stored.LogoColor, stored.LogoWidth = arg.Logo, arg.LogoColor, arg.LogoWidth
+			stored.LabelColor, stored.TextColor, stored.Title = arg.LabelColor, arg.TextColor, arg.Title
+			stored.MaxWidth, stored.Overflow = arg.MaxWidth, arg.Overflow
+			stored.SubjectLink, stored.StatusLink = arg.SubjectLink, arg.StatusLink
+			stored.Value, stored.Unit, stored.ValueFormat, stored.ColorScale = arg.Value, arg.Unit, arg.ValueFormat, arg.ColorScale
+			stored.Kind, stored.Progress = arg.Kind, arg.Progress
+			stored.DarkLabelColor, stored.DarkColor, stored.DarkTextColor = arg.DarkLabelColor, arg.DarkColor, arg.DarkTextColor
+			stored.Animation = arg.Animation
+			stored.Font, stored.FontSize, stored.FontWeight = arg.Font, arg.FontSize, arg.FontWeight
+			stored.Segments = arg.Segments
+			return *stored, nil
+		},
+	}
+	svc, err := service.New(r, repo, tokens)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	return svc, row.ID, token, stored
+}
+
+func TestCreateBadgeSegments(t *testing.T) {
+	repo := &fakeRepo{
+		createFn: func(_ context.Context, arg repository.CreateBadgeParams) (repository.Badge, error) {
+			want := `[{"text":"build","logo":"bolt"},{"text":"linux","color":"blue","link":"https://example.com"},{"text":"passing"}]`
+			if arg.Subject != "" || arg.Status != "" || string(arg.Segments) != want {
+				t.Fatalf("unexpected create params: %#v", arg)
+			}
+			return repository.Badge{Color: arg.Color, Style: arg.Style, Segments: arg.Segments}, nil
+		},
+	}
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	svc, err := service.New(newRenderer(t), repo, tokens)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	badge, _, err := svc.CreateBadge(context.Background(), service.BadgeInput{
+		Color: "green",
+		Segments: []service.Segment{
+			{Text: " build ", Logo: " bolt "},
+			{Text: "linux", Color: " blue ", Link: "https://example.com"},
+			{Text: "passing"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(badge.Segments) != 3 || badge.Segments[1].Color != "blue" || badge.Segments[0].Logo != "bolt" {
+		t.Fatalf("unexpected badge: %#v", badge)
+	}
+
+	segments := []service.Segment{{Text: "a"}, {Text: "b"}}
+	for _, input := range []service.BadgeInput{
+		{Color: "green", Subject: "a", Segments: segments},
+		{Color: "green", Value: ptr(1.0), Segments: segments},
+		{Color: "green", Logo: "bolt", Segments: segments},
+		{Color: "green", Links: [2]string{"https://example.com"}, Segments: segments},
+		{Color: "green", Kind: "progress", Segments: segments},
+		{Color: "green", Segments: make([]service.Segment, renderer.MaxSegments+1)},
+		{Color: "green", Segments: []service.Segment{{Text: "a"}, {Text: " "}}},
+		{Color: "green", Segments: []service.Segment{{Text: "a", Color: "nope"}}},
+		{Color: "green", Segments: []service.Segment{{Text: "a", Link: "ftp://example.com"}}},
+		{Color: "green", Segments: []service.Segment{{Text: "a", Logo: "nope"}}},
+	} {
+		if _, _, err = svc.CreateBadge(context.Background(), input); !errors.Is(err, service.ErrInvalidBadgeInput) {
+			t.Fatalf("%+v: expected invalid input, got %v", input, err)
+		}
+	}
+	_, _, err = svc.CreateBadge(context.Background(), service.BadgeInput{Color: "green", Value: ptr(1.0), Segments: segments})
+	if err == nil || !strings.Contains(err.Error(), "value is not supported with segments") {
+		t.Fatalf("expected the value to be rejected before it sets a status, got %v", err)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
 func TestDeleteBadgeUnauthorized(t *testing.T) {
 	tokens, err := service.NewTokenManager("secret")
 	if err != nil {
@@ -330,19 +754,287 @@ func TestGetLiveBadge(t *testing.T) {
 	if err != nil {
 		t.Fatalf("new service: %v", err)
 	}
-	output, err := svc.GetLiveBadge("build", "passing", "green", "flat")
+	output, err := svc.GetLiveBadge(service.BadgeInput{Subject: "build", Status: "passing", Color: "green", Style: "flat"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if !strings.Contains(string(output), "build") {
 		t.Fatalf("expected subject in output")
 	}
-	_, err = svc.GetLiveBadge("", "passing", "green", "flat")
+	_, err = svc.GetLiveBadge(service.BadgeInput{Status: "passing", Color: "green", Style: "flat"})
 	if err == nil {
 		t.Fatalf("expected validation error")
 	}
 }
 
+func TestGetLiveBadgeProgress(t *testing.T) {
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	svc, err := service.New(newRenderer(t), &fakeRepo{}, tokens)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	output, err := svc.GetLiveBadge(service.BadgeInput{
+		Subject: "tasks", Color: "blue", Style: "flat-square", Kind: "progress", Progress: 40,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(output), "40%") || !strings.Contains(string(output), "#9f9f9f") {
+		t.Fatalf("expected a progress bar, got %s", output)
+	}
+}
+
+func TestGetLiveBadgeLogo(t *testing.T) {
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	svc, err := service.New(newRenderer(t), &fakeRepo{}, tokens)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	output, err := svc.GetLiveBadge(service.BadgeInput{
+		Subject:   "build",
+		Status:    "passing",
+		Color:     "green",
+		Logo:      " check ",
+		LogoColor: "yellow",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(output), "<image") {
+		t.Fatalf("expected logo in output")
+	}
+
+	invalid := []service.BadgeInput{
+		{Subject: "build", Status: "passing", Color: "green", Logo: "nope"},
+		{Subject: "build", Status: "passing", Color: "green", Logo: "check", LogoColor: "nope"},
+		{Subject: "build", Status: "passing", Color: "green", Logo: "check", LogoWidth: -1},
+	}
+	for _, input := range invalid {
+		_, err = svc.GetLiveBadge(input)
+		if !errors.Is(err, service.ErrInvalidBadgeInput) {
+			t.Fatalf("expected invalid input error for %#v, got %v", input, err)
+		}
+	}
+}
+
+func TestGetLiveBadgeCustomStyle(t *testing.T) {
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	r := newRenderer(t)
+	if err = r.RegisterStyle("corporate", `<svg>{{.Subject}}:{{.Status}}</svg>`); err != nil {
+		t.Fatalf("register style: %v", err)
+	}
+	svc, err := service.New(r, &fakeRepo{}, tokens)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	if styles := svc.Styles(); styles[len(styles)-1] != "corporate" {
+		t.Fatalf("expected registered style in %v", styles)
+	}
+	output, err := svc.GetLiveBadge(service.BadgeInput{Subject: "build", Status: "passing", Color: "green", Style: "corporate"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(output) != "<svg>build:passing</svg>" {
+		t.Fatalf("unexpected output: %s", output)
+	}
+
+	_, err = svc.GetLiveBadge(service.BadgeInput{Subject: "build", Status: "passing", Color: "green", Style: "unknown"})
+	if !errors.Is(err, service.ErrInvalidBadgeInput) {
+		t.Fatalf("expected invalid input error, got %v", err)
+	}
+	segments := []service.Segment{{Text: "build"}, {Text: "passing"}}
+	_, err = svc.GetLiveBadge(service.BadgeInput{Color: "green", Style: "corporate", Segments: segments})
+	if !errors.Is(err, service.ErrInvalidBadgeInput) {
+		t.Fatalf("expected invalid input error for a style without segments, got %v", err)
+	}
+}
+
+func TestGetLiveBadgeSegments(t *testing.T) {
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	svc, err := service.New(newRenderer(t), &fakeRepo{}, tokens)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	output, err := svc.GetLiveBadge(service.BadgeInput{
+		Color:    "green",
+		Segments: []service.Segment{{Text: "build"}, {Text: "linux", Color: "blue"}, {Text: "passing"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(output), `aria-label="build: linux: passing"`) || !strings.Contains(string(output), `fill="#007ec6"`) {
+		t.Fatalf("expected the segments in output: %s", output)
+	}
+
+	// The color is needed only when a segment other than the label has none.
+	colored := []service.Segment{{Text: "build"}, {Text: "linux", Color: "blue"}, {Text: "passing", Color: "green"}}
+	if _, err = svc.GetLiveBadge(service.BadgeInput{Segments: colored}); err != nil {
+		t.Fatalf("expected segments with their own colors to need no color, got %v", err)
+	}
+	for _, segments := range [][]service.Segment{
+		{{Text: "build", Color: "blue"}, {Text: "passing"}},
+		{{Text: "passing"}},
+	} {
+		if _, err = svc.GetLiveBadge(service.BadgeInput{Segments: segments}); err == nil || !strings.Contains(err.Error(), "color is required") {
+			t.Fatalf("%+v: expected color to be required, got %v", segments, err)
+		}
+	}
+}
+
+func TestGetLiveBadgeFont(t *testing.T) {
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	r := newRenderer(t)
+	if err = r.RegisterFont(gomono.TTF); err != nil {
+		t.Fatalf("register font: %v", err)
+	}
+	svc, err := service.New(r, &fakeRepo{}, tokens)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	output, err := svc.GetLiveBadge(service.BadgeInput{
+		Subject:    "commit",
+		Status:     "abc1234",
+		Color:      "blue",
+		Font:       " go mono ",
+		FontSize:   12,
+		FontWeight: " bold ",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(output), `font-family="Go Mono,`) || !strings.Contains(string(output), `font-size="12" font-weight="bold"`) {
+		t.Fatalf("expected bold 12px Go Mono text: %s", output)
+	}
+
+	invalid := []service.BadgeInput{
+		{Subject: "commit", Status: "abc1234", Color: "blue", Font: "Fira Code"},
+		{Subject: "commit", Status: "abc1234", Color: "blue", FontSize: 2},
+		{Subject: "commit", Status: "abc1234", Color: "blue", FontWeight: "heavy"},
+	}
+	for _, input := range invalid {
+		_, err = svc.GetLiveBadge(input)
+		if !errors.Is(err, service.ErrInvalidBadgeInput) {
+			t.Fatalf("expected invalid input error for %#v, got %v", input, err)
+		}
+	}
+}
+
+func TestGetLiveBadgeMaxWidth(t *testing.T) {
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	svc, err := service.New(newRenderer(t), &fakeRepo{}, tokens)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	output, err := svc.GetLiveBadge(service.BadgeInput{
+		Subject:  "branch",
+		Status:   "feature/a-very-long-branch-name",
+		Color:    "blue",
+		MaxWidth: 120,
+		Overflow: " truncate-middle ",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(output), "…") {
+		t.Fatalf("expected truncated status in output: %s", output)
+	}
+
+	invalid := []service.BadgeInput{
+		{Subject: "build", Status: "passing", Color: "green", MaxWidth: -1},
+		{Subject: "build", Status: "passing", Color: "green", Overflow: "wrap"},
+		{Subject: "build", Status: "passing", Color: "green", MaxWidth: 5},
+	}
+	for _, input := range invalid {
+		_, err = svc.GetLiveBadge(input)
+		if !errors.Is(err, service.ErrInvalidBadgeInput) {
+			t.Fatalf("expected invalid input error for %#v, got %v", input, err)
+		}
+	}
+}
+
+func TestGetLiveBadgeTextColor(t *testing.T) {
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	svc, err := service.New(newRenderer(t), &fakeRepo{}, tokens)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	output, err := svc.GetLiveBadge(service.BadgeInput{Subject: "build", Status: "passing", Color: "yellow"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(output), `fill="#333"`) {
+		t.Fatalf("expected dark text on yellow: %s", output)
+	}
+
+	output, err = svc.GetLiveBadge(service.BadgeInput{Subject: "build", Status: "passing", Color: "yellow", TextColor: " black "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(output), `fill="#000"`) {
+		t.Fatalf("expected text color override: %s", output)
+	}
+
+	_, err = svc.GetLiveBadge(service.BadgeInput{Subject: "build", Status: "passing", Color: "green", TextColor: "nope"})
+	if !errors.Is(err, service.ErrInvalidBadgeInput) {
+		t.Fatalf("expected invalid input error, got %v", err)
+	}
+}
+
+func TestGetLiveBadgeAccessibility(t *testing.T) {
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	svc, err := service.New(newRenderer(t), &fakeRepo{}, tokens)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	output, err := svc.GetLiveBadge(service.BadgeInput{
+		Subject: "build",
+		Status:  "passing",
+		Color:   "green",
+		Title:   " Build status ",
+		RenderOptions: service.RenderOptions{
+			IDPrefix: " docs ",
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(output), `aria-label="Build status"`) {
+		t.Fatalf("expected title override: %s", output)
+	}
+	if !strings.Contains(string(output), `id="smooth-docs-`) {
+		t.Fatalf("expected prefixed ids: %s", output)
+	}
+
+	_, err = svc.GetLiveBadge(service.BadgeInput{Subject: "build", Status: "passing", Color: "green", RenderOptions: service.RenderOptions{IDPrefix: "a\"b"}})
+	if !errors.Is(err, service.ErrInvalidBadgeInput) {
+		t.Fatalf("expected invalid input error, got %v", err)
+	}
+}
+
 func TestRenderBadge(t *testing.T) {
 	id := uuid.New()
 	repo := &fakeRepo{
@@ -365,7 +1057,7 @@ func TestRenderBadge(t *testing.T) {
 		t.Fatalf("new service: %v", err)
 	}
 
-	badge, svg, err := svc.RenderBadge(context.Background(), id)
+	badge, svg, err := svc.RenderBadge(context.Background(), id, service.RenderOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -399,7 +1091,7 @@ func TestRenderBadgeInvalidInput(t *testing.T) {
 		t.Fatalf("new service: %v", err)
 	}
 
-	_, _, err = svc.RenderBadge(context.Background(), id)
+	_, _, err = svc.RenderBadge(context.Background(), id, service.RenderOptions{})
 	if err == nil || !errors.Is(err, service.ErrInvalidBadgeInput) {
 		t.Fatalf("expected invalid input error, got %v", err)
 	}
@@ -444,3 +1136,39 @@ func TestTokenManagerNil(t *testing.T) {
 		t.Fatalf("expected comparison to be false")
 	}
 }
+
+func TestRenderBadgePNG(t *testing.T) {
+	id := uuid.New()
+	repo := &fakeRepo{
+		getFn: func(_ context.Context, _ uuid.UUID) (repository.Badge, error) {
+			return repository.Badge{
+				ID:      id,
+				Subject: "build",
+				Status:  "passing",
+				Color:   "green",
+				Style:   "flat",
+			}, nil
+		},
+	}
+	tokens, err := service.NewTokenManager("secret")
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	svc, err := service.New(newRenderer(t), repo, tokens)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	_, output, err := svc.RenderBadge(context.Background(), id, service.RenderOptions{Format: renderer.FormatPNG})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(output, []byte("\x89PNG")) {
+		t.Fatalf("expected png output")
+	}
+
+	_, _, err = svc.RenderBadge(context.Background(), id, service.RenderOptions{Format: "gif"})
+	if !errors.Is(err, service.ErrInvalidBadgeInput) {
+		t.Fatalf("expected invalid input error, got %v", err)
+	}
+}
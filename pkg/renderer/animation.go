@@ -0,0 +1,70 @@
+package renderer
+
+import "slices"
+
+// Animation shows that what a badge reports is still in progress, such as a
+// running deploy. Animations are CSS, so they stop for readers who prefer
+// reduced motion and in PNG output.
+type Animation string
+
+const (
+	// AnimationPulse fades the status segment in and out.
+	AnimationPulse Animation = "pulse"
+	// AnimationBlink blinks the status text.
+	AnimationBlink Animation = "blink"
+	// AnimationSpinner draws a spinning ring before the status text.
+	AnimationSpinner Animation = "spinner"
+)
+
+const (
+	spinnerRadius = 3.5
+	spinnerStroke = 1.5
+	// spinnerShift is the room taken by the spinner in the status segment.
+	spinnerShift = 11
+)
+
+// Animations returns every supported animation.
+func Animations() []Animation {
+	return []Animation{AnimationPulse, AnimationBlink, AnimationSpinner}
+}
+
+// IsValid reports whether the animation is supported.
+// Empty string is treated as valid and means no animation.
+func (a Animation) IsValid() bool {
+	return a == "" || slices.Contains(Animations(), a)
+}
+
+// shift returns the room the animation takes in the status segment.
+func (a Animation) shift() float64 {
+	if a == AnimationSpinner {
+		return spinnerShift
+	}
+	return 0
+}
+
+// placeSpinner makes room for the spinner at the left of the status segment,
+// which layout sized with the spinner shift included.
+func placeSpinner(b *bounds, m styleMetrics) {
+	b.StatusX += spinnerShift / 2
+	b.SpinnerX = b.StatusStart() + m.padding/2 + spinnerRadius + spinnerStroke/2
+}
+
+// rules returns the CSS rules of the animation for elements under scope,
+// applied only while the reader has no preference for reduced motion.
+func (a Animation) rules(scope string) string {
+	var rules string
+	switch a {
+	case AnimationPulse:
+		rules = scope + "status{animation:signum-pulse 1.5s ease-in-out infinite}" +
+			"@keyframes signum-pulse{50%{opacity:.55}}"
+	case AnimationBlink:
+		rules = scope + "status-text," + scope + "status-shadow{animation:signum-blink 1s step-end infinite}" +
+			"@keyframes signum-blink{50%{opacity:0}}"
+	case AnimationSpinner:
+		rules = scope + "spinner{transform-box:fill-box;transform-origin:center;animation:signum-spin 1s linear infinite}" +
+			"@keyframes signum-spin{to{transform:rotate(360deg)}}"
+	default:
+		return ""
+	}
+	return "@media (prefers-reduced-motion:no-preference){" + rules + "}"
+}
@@ -0,0 +1,87 @@
+package renderer_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/rhajizada/signum/pkg/renderer"
+)
+
+func TestRenderAnimation(t *testing.T) {
+	r := newRenderer(t)
+	for _, style := range renderer.Styles() {
+		still := renderer.Badge{Subject: "deploy", Status: "running", Color: renderer.ColorBlue, Style: style}
+		plain, err := r.Render(still)
+		if err != nil {
+			t.Fatalf("render %s: %v", style, err)
+		}
+		for _, animation := range renderer.Animations() {
+			b := still
+			b.Animation = animation
+			svg, renderErr := r.Render(b)
+			if renderErr != nil {
+				t.Fatalf("render %s %s: %v", style, animation, renderErr)
+			}
+			if !strings.Contains(string(svg), "<style>@media (prefers-reduced-motion:no-preference){") {
+				t.Fatalf("%s %s: expected motion to depend on the reader preference: %s", style, animation, svg)
+			}
+			if !strings.Contains(string(svg), "@keyframes signum-") {
+				t.Fatalf("%s %s: expected keyframes: %s", style, animation, svg)
+			}
+			spinner := strings.Contains(string(svg), `class="spinner"`)
+			if spinner != (animation == renderer.AnimationSpinner) {
+				t.Fatalf("%s %s: unexpected spinner presence %v: %s", style, animation, spinner, svg)
+			}
+			wider := badgeWidth(t, string(svg)) > badgeWidth(t, string(plain))
+			if wider != (animation == renderer.AnimationSpinner) {
+				t.Fatalf("%s %s: expected only the spinner to widen the badge", style, animation)
+			}
+		}
+	}
+}
+
+func TestRenderAnimationDarkPalette(t *testing.T) {
+	r := newRenderer(t)
+	svg, err := r.Render(renderer.Badge{
+		Subject: "deploy", Status: "running", Color: renderer.ColorYellow,
+		Animation: renderer.AnimationSpinner, Dark: renderer.Palette{Color: "navy"},
+	})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	for _, want := range []string{"prefers-color-scheme:dark", "spinner{stroke:#fff}", "prefers-reduced-motion"} {
+		if !strings.Contains(string(svg), want) {
+			t.Fatalf("expected %q in %s", want, svg)
+		}
+	}
+}
+
+func TestRenderAnimationInvalid(t *testing.T) {
+	r := newRenderer(t)
+	if _, err := r.Render(renderer.Badge{Subject: "a", Status: "b", Animation: "spin"}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestRenderPNGAnimationSpinner(t *testing.T) {
+	r := newRenderer(t)
+	b := renderer.Badge{
+		Subject: "deploy", Status: "running", Color: renderer.ColorBlue, Animation: renderer.AnimationSpinner,
+	}
+	svg, err := r.Render(b)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	data, err := r.RenderPNG(b, 1)
+	if err != nil {
+		t.Fatalf("render png: %v", err)
+	}
+	img := decodePNG(t, data)
+	if got, want := img.Bounds().Dx(), int(math.Ceil(badgeWidth(t, string(svg)))); got != want {
+		t.Fatalf("expected png width %d, got %d", want, got)
+	}
+	if !strings.Contains(string(svg), `<circle cx="`) {
+		t.Fatalf("expected a spinner ring: %s", svg)
+	}
+}
@@ -1,8 +1,63 @@
 package renderer
 
+// MaxScale is the largest accepted Badge.Scale.
+const MaxScale = 8
+
 type Badge struct {
 	Subject string `json:"subject"`
 	Status  string `json:"status"`
 	Color   Color  `json:"color"`
 	Style   Style  `json:"style"`
+	// LabelColor fills the subject segment. Empty uses the default grey.
+	LabelColor Color `json:"label_color,omitempty"`
+	// Logo is an embedded icon name or a data:image/svg+xml;base64 URI drawn left of the subject.
+	Logo Logo `json:"logo,omitempty"`
+	// TextColor overrides the text color of both segments. Empty picks light or
+	// dark text per segment from the luminance of its fill.
+	TextColor Color `json:"text_color,omitempty"`
+	// LogoColor tints embedded icons. It is ignored for data URIs.
+	LogoColor Color `json:"logo_color,omitempty"`
+	// LogoWidth overrides the default logo width of 14px.
+	LogoWidth int `json:"logo_width,omitempty"`
+	// Title is the accessible name of the badge. Empty uses "subject: status",
+	// or the segment texts joined by ": ".
+	Title string `json:"title,omitempty"`
+	// IDPrefix namespaces the ids of gradients and masks, so inline SVGs on
+	// one page never collide. It may contain ASCII letters, digits, - and _.
+	IDPrefix string `json:"id_prefix,omitempty"`
+	// Links are the targets of the subject and status segments. Each is an
+	// http, https or mailto URL, or empty to leave the segment unlinked.
+	Links [2]string `json:"links,omitempty"`
+	// Scale multiplies the outer size of the badge; geometry and text are
+	// scaled together through the viewBox. Zero means 1.
+	Scale float64 `json:"scale,omitempty"`
+	// MaxWidth caps the badge width in pixels. Zero means unlimited.
+	MaxWidth int `json:"max_width,omitempty"`
+	// Overflow selects how text is fitted into MaxWidth. Empty truncates at the end.
+	Overflow Overflow `json:"overflow,omitempty"`
+	// Kind selects what the status segment shows. Empty means KindStatus.
+	Kind Kind `json:"kind,omitempty"`
+	// Progress fills KindProgress badges, from 0 to MaxProgress. An empty
+	// status shows it as a percentage.
+	Progress float64 `json:"progress,omitempty"`
+	// Dark repaints the badge for readers who prefer a dark color scheme,
+	// through a prefers-color-scheme media query in the SVG. PNG output
+	// ignores it.
+	Dark Palette `json:"dark,omitzero"`
+	// Animation shows ongoing activity, e.g. for a running deploy. Empty
+	// means a static badge.
+	Animation Animation `json:"animation,omitempty"`
+	// Font names a font of RegisterFont to draw the text with, matched
+	// case-insensitively. Empty uses the renderer fonts.
+	Font string `json:"font,omitempty"`
+	// FontSize is the text size in pixels, from MinFontSize to MaxFontSize.
+	// Zero means 11, and StyleForTheBadge keeps its smaller ratio.
+	FontSize float64 `json:"font_size,omitempty"`
+	// FontWeight selects the weight of all text. Empty means regular.
+	FontWeight FontWeight `json:"font_weight,omitempty"`
+	// Segments replace Subject and Status with up to MaxSegments segments,
+	// drawn in order. Subject, Status, Logo and Links must then be empty:
+	// each segment carries its own logo and link. LabelColor and Color fill
+	// the first and the other segments that have no color of their own.
+	Segments []Segment `json:"segments,omitempty"`
 }
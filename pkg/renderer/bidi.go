@@ -0,0 +1,77 @@
+package renderer
+
+import (
+	"golang.org/x/text/unicode/bidi"
+)
+
+// baseDirection applies rules P2 and P3 of the Unicode bidi algorithm: the
+// first strong character decides the direction of s. Strings without strong
+// characters are bidi.Neutral.
+func baseDirection(s string) bidi.Direction {
+	for _, r := range s {
+		props, _ := bidi.LookupRune(r)
+		switch props.Class() {
+		case bidi.L:
+			return bidi.LeftToRight
+		case bidi.R, bidi.AL:
+			return bidi.RightToLeft
+		default:
+		}
+	}
+	return bidi.Neutral
+}
+
+// hasRTL reports whether s contains any right-to-left characters.
+func hasRTL(s string) bool {
+	for _, r := range s {
+		props, _ := bidi.LookupRune(r)
+		switch props.Class() {
+		case bidi.R, bidi.AL, bidi.AN:
+			return true
+		default:
+		}
+	}
+	return false
+}
+
+// bidiRuns splits s into directional runs and reverses right-to-left runs
+// into visual order, so adjacent glyphs are measured in the order they are drawn.
+func bidiRuns(s string) []string {
+	if !hasRTL(s) {
+		return []string{s}
+	}
+	var p bidi.Paragraph
+	if n, err := p.SetString(s); err != nil || n < len(s) {
+		return []string{s}
+	}
+	order, err := p.Order()
+	if err != nil || order.NumRuns() == 0 {
+		return []string{s}
+	}
+	runs := make([]string, 0, order.NumRuns())
+	for i := range order.NumRuns() {
+		run := order.Run(i)
+		text := run.String()
+		if run.Direction() == bidi.RightToLeft {
+			text = bidi.ReverseString(text)
+		}
+		runs = append(runs, text)
+	}
+	return runs
+}
+
+// isRTLBadge reports whether a badge should be laid out right to left: at
+// least one segment is RTL and none is LTR.
+func isRTLBadge(segments ...bidi.Direction) bool {
+	rtl := false
+	for _, dir := range segments {
+		switch dir {
+		case bidi.LeftToRight:
+			return false
+		case bidi.RightToLeft:
+			rtl = true
+		default:
+		}
+	}
+	return rtl
+}
@@ -0,0 +1,59 @@
+package renderer
+
+import (
+	"slices"
+	"testing"
+
+	"golang.org/x/text/unicode/bidi"
+)
+
+func TestBaseDirection(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bidi.Direction
+	}{
+		{input: "build", expected: bidi.LeftToRight},
+		{input: "שלום", expected: bidi.RightToLeft},
+		{input: "مرحبا", expected: bidi.RightToLeft},
+		{input: "100%", expected: bidi.Neutral},
+		{input: "1.2 שלום abc", expected: bidi.RightToLeft},
+		{input: "v1 שלום", expected: bidi.LeftToRight},
+	}
+	for _, tc := range tests {
+		if got := baseDirection(tc.input); got != tc.expected {
+			t.Fatalf("%q: expected direction %v, got %v", tc.input, tc.expected, got)
+		}
+	}
+}
+
+func TestBidiRuns(t *testing.T) {
+	if runs := bidiRuns("build passing"); !slices.Equal(runs, []string{"build passing"}) {
+		t.Fatalf("expected a single LTR run, got %q", runs)
+	}
+	runs := bidiRuns("ab שלום cd")
+	if len(runs) != 3 {
+		t.Fatalf("expected three runs, got %q", runs)
+	}
+	if runs[0] != "ab " || runs[1] != "םולש" || runs[2] != " cd" {
+		t.Fatalf("expected RTL run in visual order, got %q", runs)
+	}
+}
+
+func TestIsRTLBadge(t *testing.T) {
+	tests := []struct {
+		subject  bidi.Direction
+		status   bidi.Direction
+		expected bool
+	}{
+		{subject: bidi.RightToLeft, status: bidi.RightToLeft, expected: true},
+		{subject: bidi.RightToLeft, status: bidi.Neutral, expected: true},
+		{subject: bidi.Neutral, status: bidi.RightToLeft, expected: true},
+		{subject: bidi.RightToLeft, status: bidi.LeftToRight, expected: false},
+		{subject: bidi.Neutral, status: bidi.Neutral, expected: false},
+	}
+	for _, tc := range tests {
+		if got := isRTLBadge(tc.subject, tc.status); got != tc.expected {
+			t.Fatalf("%v/%v: expected %v, got %v", tc.subject, tc.status, tc.expected, got)
+		}
+	}
+}
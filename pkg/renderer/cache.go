@@ -0,0 +1,175 @@
+package renderer
+
+import (
+	"container/list"
+	"hash/maphash"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultCacheSize is the number of bytes of rendered SVG a renderer keeps.
+const DefaultCacheSize = 8 << 20
+
+// cacheShards spreads the cache over locks so parallel renders rarely contend.
+const cacheShards = 16
+
+// svgCache is a bounded LRU of rendered SVGs keyed by badge. Templates and
+// fonts are fixed per renderer, so the badge alone determines the output.
+type svgCache struct {
+	seed maphash.Seed
+	// generation changes on purge, so renders that started before it are not cached.
+	generation atomic.Uint64
+	shards     [cacheShards]cacheShard
+}
+
+type cacheShard struct {
+	mutex   sync.Mutex
+	entries map[cacheKey]*list.Element
+	order   *list.List
+	size    int
+	limit   int
+}
+
+type cacheEntry struct {
+	key cacheKey
+	svg []byte
+}
+
+// cacheKey is a Badge in comparable form, with its segments in an array.
+// Badges with more than MaxSegments segments never render, so they never
+// match a cached key either.
+type cacheKey struct {
+	Subject     string
+	Status      string
+	Color       Color
+	Style       Style
+	LabelColor  Color
+	Logo        Logo
+	TextColor   Color
+	LogoColor   Color
+	LogoWidth   int
+	Title       string
+	IDPrefix    string
+	Links       [2]string
+	Scale       float64
+	MaxWidth    int
+	Overflow    Overflow
+	Kind        Kind
+	Progress    float64
+	Dark        Palette
+	Animation   Animation
+	Font        string
+	FontSize    float64
+	FontWeight  FontWeight
+	Segments    [MaxSegments]Segment
+	NumSegments int
+}
+
+func newCacheKey(b Badge) cacheKey {
+	k := cacheKey{
+		Subject:     b.Subject,
+		Status:      b.Status,
+		Color:       b.Color,
+		Style:       b.Style,
+		LabelColor:  b.LabelColor,
+		Logo:        b.Logo,
+		TextColor:   b.TextColor,
+		LogoColor:   b.LogoColor,
+		LogoWidth:   b.LogoWidth,
+		Title:       b.Title,
+		IDPrefix:    b.IDPrefix,
+		Links:       b.Links,
+		Scale:       b.Scale,
+		MaxWidth:    b.MaxWidth,
+		Overflow:    b.Overflow,
+		Kind:        b.Kind,
+		Progress:    b.Progress,
+		Dark:        b.Dark,
+		Animation:   b.Animation,
+		Font:        b.Font,
+		FontSize:    b.FontSize,
+		FontWeight:  b.FontWeight,
+		NumSegments: len(b.Segments),
+	}
+	copy(k.Segments[:], b.Segments)
+	return k
+}
+
+func newSVGCache(size int) *svgCache {
+	c := &svgCache{seed: maphash.MakeSeed()}
+	c.resize(size)
+	return c
+}
+
+func (c *svgCache) shard(k cacheKey) *cacheShard {
+	return &c.shards[maphash.Comparable(c.seed, k)%cacheShards]
+}
+
+// get returns the cached SVG for k and marks it recently used.
+func (c *svgCache) get(k cacheKey) ([]byte, bool) {
+	s := c.shard(k)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	elem, ok := s.entries[k]
+	if !ok {
+		return nil, false
+	}
+	s.order.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).svg, true
+}
+
+// put caches svg for k, rendered at generation, evicting the least recently
+// used entries of its shard. Outputs larger than a shard are not cached.
+func (c *svgCache) put(k cacheKey, svg []byte, generation uint64) {
+	s := c.shard(k)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(svg) > s.limit || c.generation.Load() != generation {
+		return
+	}
+	if elem, ok := s.entries[k]; ok {
+		s.order.MoveToFront(elem)
+		return
+	}
+	s.entries[k] = s.order.PushFront(&cacheEntry{key: k, svg: svg})
+	s.size += len(svg)
+	for s.size > s.limit {
+		oldest := s.order.Back()
+		entry := s.order.Remove(oldest).(*cacheEntry)
+		delete(s.entries, entry.key)
+		s.size -= len(entry.svg)
+	}
+}
+
+// resize drops every entry and bounds the cache to size bytes.
+func (c *svgCache) resize(size int) {
+	c.generation.Add(1)
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mutex.Lock()
+		s.entries = map[cacheKey]*list.Element{}
+		s.order = list.New()
+		s.size = 0
+		s.limit = max(size, 0) / cacheShards
+		s.mutex.Unlock()
+	}
+}
+
+// purge drops every entry and keeps the size bound.
+func (c *svgCache) purge() {
+	c.generation.Add(1)
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mutex.Lock()
+		clear(s.entries)
+		s.order.Init()
+		s.size = 0
+		s.mutex.Unlock()
+	}
+}
+
+// SetCacheSize bounds the rendered SVGs kept by Render to size bytes, and
+// drops those already cached. Zero disables caching.
+func (r *Renderer) SetCacheSize(size int) {
+	r.cache.resize(size)
+}
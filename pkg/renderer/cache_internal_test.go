@@ -0,0 +1,34 @@
+package renderer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCacheKeyCoversBadge(t *testing.T) {
+	badge, key := reflect.TypeFor[Badge](), reflect.TypeFor[cacheKey]()
+	for i := range badge.NumField() {
+		field := badge.Field(i)
+		if field.Name == "Segments" {
+			continue
+		}
+		keyField, ok := key.FieldByName(field.Name)
+		if !ok || keyField.Type != field.Type {
+			t.Fatalf("expected cacheKey to hold Badge.%s as %s", field.Name, field.Type)
+		}
+	}
+	if badge.NumField()+1 != key.NumField() {
+		t.Fatalf("expected cacheKey to hold the Badge fields and the segment count")
+	}
+
+	b := Badge{Subject: "a", Status: "b", Segments: []Segment{{Text: "c"}}}
+	same := b
+	same.Segments = []Segment{{Text: "c"}}
+	if newCacheKey(b) != newCacheKey(same) {
+		t.Fatalf("expected equal badges to share a key")
+	}
+	same.Segments = append(same.Segments, Segment{})
+	if newCacheKey(b) == newCacheKey(same) {
+		t.Fatalf("expected a trailing empty segment to change the key")
+	}
+}
@@ -0,0 +1,168 @@
+package renderer_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rhajizada/signum/pkg/renderer"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+)
+
+func TestRenderCacheReturnsCopies(t *testing.T) {
+	r := newRenderer(t)
+	badge := renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen}
+	first, err := r.Render(badge)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	want := bytes.Clone(first)
+	clear(first)
+	second, err := r.Render(badge)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !bytes.Equal(second, want) {
+		t.Fatalf("expected cached output to be unaffected by callers")
+	}
+}
+
+func TestRegisterStylePurgesCache(t *testing.T) {
+	r := newRenderer(t)
+	badge := renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen}
+	if _, err := r.Render(badge); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if err := r.RegisterStyle(renderer.StyleFlat, `<svg>{{.Subject}}</svg>`); err != nil {
+		t.Fatalf("register style: %v", err)
+	}
+	output, err := r.Render(badge)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if string(output) != "<svg>build</svg>" {
+		t.Fatalf("expected the replaced template, got %s", output)
+	}
+}
+
+func TestSetCacheSize(t *testing.T) {
+	r := newRenderer(t)
+	badge := renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen}
+	want, err := r.Render(badge)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	for _, size := range []int{0, 1, renderer.DefaultCacheSize} {
+		r.SetCacheSize(size)
+		for range 2 {
+			output, renderErr := r.Render(badge)
+			if renderErr != nil {
+				t.Fatalf("size %d: render: %v", size, renderErr)
+			}
+			if !bytes.Equal(output, want) {
+				t.Fatalf("size %d: expected identical output", size)
+			}
+		}
+	}
+}
+
+func TestRenderConcurrent(t *testing.T) {
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatalf("parse font: %v", err)
+	}
+	newTrueType := func() *renderer.Renderer {
+		r, rendererErr := renderer.NewRendererWithFontFace(newFace(regular))
+		if rendererErr != nil {
+			t.Fatalf("new renderer: %v", rendererErr)
+		}
+		return r
+	}
+	badges := make([]renderer.Badge, 0, 64)
+	for i := range cap(badges) {
+		badges = append(badges, renderer.Badge{
+			Subject: "build",
+			Status:  fmt.Sprintf("run %d %s", i, strings.Repeat("W", i%7)),
+			Color:   renderer.ColorBlue,
+			Style:   renderer.Styles()[i%len(renderer.Styles())],
+			Scale:   float64(1 + i%3),
+		})
+	}
+	want := make([][]byte, len(badges))
+	sequential := newTrueType()
+	for i, badge := range badges {
+		if want[i], err = sequential.Render(badge); err != nil {
+			t.Fatalf("render: %v", err)
+		}
+	}
+
+	r := newTrueType()
+	r.SetCacheSize(0)
+	var wg sync.WaitGroup
+	errs := make(chan error, len(badges)*4)
+	for worker := range 4 {
+		wg.Go(func() {
+			for i := range badges {
+				i = (i + worker*len(badges)/4) % len(badges)
+				output, renderErr := r.Render(badges[i])
+				if renderErr != nil {
+					errs <- renderErr
+					return
+				}
+				if !bytes.Equal(output, want[i]) {
+					errs <- fmt.Errorf("badge %d differs from the sequential render", i)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+}
+
+func TestRenderTo(t *testing.T) {
+	r := newRenderer(t)
+	for _, style := range renderer.Styles() {
+		badge := renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen, Style: style}
+		want, err := r.Render(badge)
+		if err != nil {
+			t.Fatalf("render: %v", err)
+		}
+		r.SetCacheSize(0)
+		buf := &bytes.Buffer{}
+		if err = r.RenderTo(buf, badge); err != nil {
+			t.Fatalf("render to: %v", err)
+		}
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Fatalf("%s: expected RenderTo to match Render", style)
+		}
+		r.SetCacheSize(renderer.DefaultCacheSize)
+	}
+	if err := r.RenderTo(&bytes.Buffer{}, renderer.Badge{Subject: "a", Status: "b", Style: "nope"}); err == nil {
+		t.Fatalf("expected error for invalid style")
+	}
+}
+
+func TestRenderToCachedDoesNotAllocate(t *testing.T) {
+	r := newRenderer(t)
+	badge := renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen}
+	buf := &bytes.Buffer{}
+	if err := r.RenderTo(buf, badge); err != nil {
+		t.Fatalf("render to: %v", err)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		buf.Reset()
+		if err := r.RenderTo(buf, badge); err != nil {
+			t.Fatalf("render to: %v", err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+}
@@ -1,6 +1,18 @@
 package renderer
 
-// Color represents color of the badge.
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/colornames"
+)
+
+// Color represents color of the badge. It accepts the shields.io scheme names
+// and aliases, CSS named colors, #rgb, #rrggbb and #rrggbbaa hex codes and the
+// rgb(), rgba(), hsl() and hsla() functions.
 type Color string
 
 // Standard colors.
@@ -18,29 +30,86 @@ const (
 	ColorLightgray   = Color("lightgray")
 )
 
+// Semantic aliases used by shields.io.
+const (
+	ColorSuccess       = Color("success")
+	ColorImportant     = Color("important")
+	ColorCritical      = Color("critical")
+	ColorInformational = Color("informational")
+	ColorInactive      = Color("inactive")
+)
+
+const (
+	hexShort  = 3
+	hexLong   = 6
+	hexAlpha  = 8
+	maxByte   = 255
+	maxHue    = 360
+	percent   = 100
+	rgbArgs   = 3
+	alphaArgs = 4
+	// opacityPrecision rounds the opacity of svgPaint to three decimals.
+	opacityPrecision = 1000
+)
+
+// String returns the canonical form of the color: the shields.io hex value for
+// scheme names, otherwise the shortest of #rgb and #rrggbb, or #rrggbbaa when
+// the color is translucent. Invalid colors are returned unchanged.
 func (c Color) String() string {
-	color, ok := schemeColor(string(c))
-	if ok {
-		return color
+	rgba, ok := c.NRGBA()
+	if !ok {
+		return string(c)
 	}
-	return string(c)
+	return formatHexColor(rgba)
 }
 
-// IsValid reports whether the color is a named scheme color or a hex code.
+// IsValid reports whether the color can be parsed.
 // Empty string is treated as valid to allow the template default.
 func (c Color) IsValid() bool {
 	if c == "" {
 		return true
 	}
-	if _, ok := schemeColor(string(c)); ok {
-		return true
+	_, ok := c.NRGBA()
+	return ok
+}
+
+// NRGBA parses the color. It reports false for empty and invalid colors.
+func (c Color) NRGBA() (color.NRGBA, bool) {
+	value := strings.ToLower(string(c))
+	if hex, ok := schemeColor(value); ok {
+		value = hex
+	}
+	if hex, ok := strings.CutPrefix(value, "#"); ok {
+		return parseHexColor(hex)
+	}
+	switch value {
+	case "transparent":
+		return color.NRGBA{}, true
+	case "rebeccapurple":
+		// CSS Color 4 addition missing from the SVG 1.1 table of colornames.
+		return color.NRGBA{R: 0x66, G: 0x33, B: 0x99, A: maxByte}, true
+	default:
 	}
-	return isHexColor(string(c))
+	if name, args, ok := cutColorFunc(value); ok {
+		switch name {
+		case "rgb", "rgba":
+			return parseRGBFunc(args)
+		case "hsl", "hsla":
+			return parseHSLFunc(args)
+		default:
+			return color.NRGBA{}, false
+		}
+	}
+	named, ok := colornames.Map[value]
+	if !ok {
+		return color.NRGBA{}, false
+	}
+	return color.NRGBA{R: named.R, G: named.G, B: named.B, A: named.A}, true
 }
 
 func schemeColor(name string) (string, bool) {
 	switch name {
-	case "brightgreen":
+	case "brightgreen", "success":
 		return "#4c1", true
 	case "green":
 		return "#97ca00", true
@@ -48,34 +117,86 @@ func schemeColor(name string) (string, bool) {
 		return "#dfb317", true
 	case "yellowgreen":
 		return "#a4a61d", true
-	case "orange":
+	case "orange", "important":
 		return "#fe7d37", true
-	case "red":
+	case "red", "critical":
 		return "#e05d44", true
-	case "blue":
+	case "blue", "informational":
 		return "#007ec6", true
 	case "grey", "gray":
 		return "#555", true
-	case "lightgrey", "lightgray":
+	case "lightgrey", "lightgray", "inactive":
 		return "#9f9f9f", true
 	default:
 		return "", false
 	}
 }
 
-func isHexColor(value string) bool {
-	if len(value) != 4 && len(value) != 7 {
-		return false
+func formatHexColor(c color.NRGBA) string {
+	if c.A != maxByte {
+		return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
 	}
-	if value[0] != '#' {
-		return false
+	if isShortHex(c.R) && isShortHex(c.G) && isShortHex(c.B) {
+		return fmt.Sprintf("#%x%x%x", c.R>>4, c.G>>4, c.B>>4)
 	}
-	for i := 1; i < len(value); i++ {
-		if !isHexDigit(value[i]) {
-			return false
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// svgPaint splits a color in the form of Color.String into a #rgb or #rrggbb
+// paint, as SVG 1.1 has no hex form with alpha, and the alpha of a translucent
+// color for a fill-opacity or stroke-opacity attribute. The opacity is empty
+// for opaque colors, which are returned unchanged like invalid ones.
+func svgPaint(s string) (string, string) {
+	c, ok := Color(s).NRGBA()
+	if !ok || c.A == maxByte {
+		return s, ""
+	}
+	opacity := strconv.FormatFloat(math.Round(float64(c.A)/maxByte*opacityPrecision)/opacityPrecision, 'f', -1, 64)
+	c.A = maxByte
+	return formatHexColor(c), opacity
+}
+
+// joinPaint reverses svgPaint, for drawing template data that has the alpha of
+// its colors split off.
+func joinPaint(paint, opacity string) Color {
+	c, ok := Color(paint).NRGBA()
+	a, err := strconv.ParseFloat(opacity, 64)
+	if !ok || err != nil {
+		return Color(paint)
+	}
+	c.A = uint8(math.Round(a * maxByte))
+	return Color(formatHexColor(c))
+}
+
+func isShortHex(b uint8) bool {
+	return b>>4 == b&0x0f
+}
+
+// parseHexColor parses the digits of a #rgb, #rrggbb or #rrggbbaa color.
+func parseHexColor(hex string) (color.NRGBA, bool) {
+	for i := range len(hex) {
+		if !isHexDigit(hex[i]) {
+			return color.NRGBA{}, false
 		}
 	}
-	return true
+	digit := func(i int) uint8 {
+		v, _ := strconv.ParseUint(hex[i:i+1], 16, 8)
+		return uint8(v)
+	}
+	pair := func(i int) uint8 {
+		v, _ := strconv.ParseUint(hex[i:i+2], 16, 8)
+		return uint8(v)
+	}
+	switch len(hex) {
+	case hexShort:
+		return color.NRGBA{R: digit(0) * 0x11, G: digit(1) * 0x11, B: digit(2) * 0x11, A: maxByte}, true
+	case hexLong:
+		return color.NRGBA{R: pair(0), G: pair(2), B: pair(4), A: maxByte}, true
+	case hexAlpha:
+		return color.NRGBA{R: pair(0), G: pair(2), B: pair(4), A: pair(6)}, true
+	default:
+		return color.NRGBA{}, false
+	}
 }
 
 func isHexDigit(b byte) bool {
@@ -90,3 +211,139 @@ func isHexDigit(b byte) bool {
 		return false
 	}
 }
+
+// cutColorFunc splits "name(a, b, c)" into its name and arguments. Both the
+// legacy comma syntax and the space syntax with "/ alpha" are accepted.
+func cutColorFunc(value string) (string, []string, bool) {
+	name, rest, ok := strings.Cut(value, "(")
+	if !ok {
+		return "", nil, false
+	}
+	body, ok := strings.CutSuffix(rest, ")")
+	if !ok {
+		return "", nil, false
+	}
+	var args []string
+	if strings.Contains(body, ",") {
+		args = strings.Split(body, ",")
+	} else {
+		channels, alpha, hasAlpha := strings.Cut(body, "/")
+		args = strings.Fields(channels)
+		if hasAlpha {
+			args = append(args, alpha)
+		}
+	}
+	for i, arg := range args {
+		args[i] = strings.TrimSpace(arg)
+	}
+	if len(args) != rgbArgs && len(args) != alphaArgs {
+		return "", nil, false
+	}
+	return strings.TrimSpace(name), args, true
+}
+
+func parseRGBFunc(args []string) (color.NRGBA, bool) {
+	var channels [rgbArgs]uint8
+	for i := range rgbArgs {
+		v, ok := parseComponent(args[i], maxByte)
+		if !ok {
+			return color.NRGBA{}, false
+		}
+		channels[i] = toByte(v / maxByte)
+	}
+	alpha, ok := parseAlpha(args)
+	if !ok {
+		return color.NRGBA{}, false
+	}
+	return color.NRGBA{R: channels[0], G: channels[1], B: channels[2], A: alpha}, true
+}
+
+func parseHSLFunc(args []string) (color.NRGBA, bool) {
+	hue, ok := parseNumber(strings.TrimSuffix(args[0], "deg"))
+	if !ok {
+		return color.NRGBA{}, false
+	}
+	saturation, ok := parseComponent(args[1], percent)
+	if !ok {
+		return color.NRGBA{}, false
+	}
+	lightness, ok := parseComponent(args[2], percent)
+	if !ok {
+		return color.NRGBA{}, false
+	}
+	alpha, ok := parseAlpha(args)
+	if !ok {
+		return color.NRGBA{}, false
+	}
+	r, g, b := hslToRGB(math.Mod(math.Mod(hue, maxHue)+maxHue, maxHue)/maxHue, saturation/percent, lightness/percent)
+	return color.NRGBA{R: toByte(r), G: toByte(g), B: toByte(b), A: alpha}, true
+}
+
+// parseAlpha returns the optional fourth argument as a byte, opaque when absent.
+func parseAlpha(args []string) (uint8, bool) {
+	if len(args) < alphaArgs {
+		return maxByte, true
+	}
+	alpha, ok := parseComponent(args[rgbArgs], 1)
+	if !ok {
+		return 0, false
+	}
+	return toByte(alpha), true
+}
+
+// parseComponent parses a number or a percentage of full, clamped to [0, full].
+func parseComponent(value string, full float64) (float64, bool) {
+	trimmed, isPercent := strings.CutSuffix(value, "%")
+	v, ok := parseNumber(trimmed)
+	if !ok {
+		return 0, false
+	}
+	if isPercent {
+		v = v * full / percent
+	}
+	return math.Min(math.Max(v, 0), full), true
+}
+
+func parseNumber(value string) (float64, bool) {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false
+	}
+	return v, true
+}
+
+func toByte(v float64) uint8 {
+	return uint8(math.Round(math.Min(math.Max(v, 0), 1) * maxByte))
+}
+
+// hslToRGB converts hue, saturation and lightness in [0, 1] to RGB in [0, 1].
+func hslToRGB(h, s, l float64) (float64, float64, float64) {
+	if s == 0 {
+		return l, l, l
+	}
+	q := l + s - l*s
+	if l < 0.5 {
+		q = l * (1 + s)
+	}
+	p := 2*l - q
+	return hueToRGB(p, q, h+1.0/3), hueToRGB(p, q, h), hueToRGB(p, q, h-1.0/3)
+}
+
+func hueToRGB(p, q, t float64) float64 {
+	switch {
+	case t < 0:
+		t++
+	case t > 1:
+		t--
+	}
+	switch {
+	case t < 1.0/6:
+		return p + (q-p)*6*t
+	case t < 1.0/2:
+		return q
+	case t < 2.0/3:
+		return p + (q-p)*(2.0/3-t)*6
+	default:
+		return p
+	}
+}
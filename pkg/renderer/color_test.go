@@ -25,9 +25,47 @@ func TestColorString(t *testing.T) {
 			t.Fatalf("expected %q to map to %q, got %q", input, expected, got)
 		}
 	}
-	custom := renderer.Color("magenta")
-	if got := custom.String(); got != "magenta" {
-		t.Fatalf("expected custom color to return itself, got %q", got)
+	invalid := renderer.Color("not-a-color")
+	if got := invalid.String(); got != "not-a-color" {
+		t.Fatalf("expected invalid color to return itself, got %q", got)
+	}
+}
+
+func TestColorStringCanonical(t *testing.T) {
+	cases := map[renderer.Color]string{
+		"magenta":                  "#f0f",
+		"RebeccaPurple":            "#639",
+		"success":                  "#4c1",
+		"important":                "#fe7d37",
+		"critical":                 "#e05d44",
+		"informational":            "#007ec6",
+		"inactive":                 "#9f9f9f",
+		"#ABCDEF":                  "#abcdef",
+		"#aabbcc":                  "#abc",
+		"#11223344":                "#11223344",
+		"#112233ff":                "#123",
+		"transparent":              "#00000000",
+		"rgb(0,128,0)":             "#008000",
+		"rgb(0 128 0)":             "#008000",
+		"rgb(100%, 0%, 50%)":       "#ff0080",
+		"rgba(0, 0, 0, 0.5)":       "#00000080",
+		"rgb(0 0 0 / 50%)":         "#00000080",
+		"rgb(300, -5, 0)":          "#f00",
+		"hsl(120, 100%, 25%)":      "#008000",
+		"hsl(270deg 50% 40%)":      "#639",
+		"hsla(0, 100%, 50%, 0.25)": "#ff000040",
+		"hsl(-120, 100%, 50%)":     "#00f",
+		"HSL(240, 100%, 50%)":      "#00f",
+		"rgb(10%, 20%, 30%)":       "#1a334d",
+		"hsl(200 50% 50% / 0.5)":   "#4095bf80",
+		"hsl(0.5turn, 100%, 50%)":  "hsl(0.5turn, 100%, 50%)",
+		"rgb(0, 0)":                "rgb(0, 0)",
+		"#ffff":                    "#ffff",
+	}
+	for input, expected := range cases {
+		if got := input.String(); got != expected {
+			t.Fatalf("expected %q to map to %q, got %q", input, expected, got)
+		}
 	}
 }
 
@@ -49,6 +87,16 @@ func TestColorIsValid(t *testing.T) {
 		renderer.Color("#abc"),
 		renderer.Color("#abcdef"),
 		renderer.Color("#ABCDEF"),
+		renderer.Color("#abcdef80"),
+		renderer.Color("rebeccapurple"),
+		renderer.Color("rgb(0,128,0)"),
+		renderer.Color("rgba(0, 128, 0, 0.5)"),
+		renderer.Color("hsl(120, 100%, 25%)"),
+		renderer.ColorSuccess,
+		renderer.ColorImportant,
+		renderer.ColorCritical,
+		renderer.ColorInformational,
+		renderer.ColorInactive,
 	}
 	for _, c := range valid {
 		if !c.IsValid() {
@@ -62,6 +110,10 @@ func TestColorIsValid(t *testing.T) {
 		renderer.Color("#fffff"),
 		renderer.Color("#gggggg"),
 		renderer.Color("not-a-color"),
+		renderer.Color("#abcdef8"),
+		renderer.Color("rgb(0, 128)"),
+		renderer.Color("hsl(120, 100%)"),
+		renderer.Color("rgb(a, b, c)"),
 	}
 	for _, c := range invalid {
 		if c.IsValid() {
@@ -0,0 +1,475 @@
+package renderer
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"text/template/parse"
+)
+
+// styleTemplate is a parsed style template and, when it compiles, its program.
+type styleTemplate struct {
+	tmpl     *template.Template
+	prog     program
+	compiled bool
+	// segments is set when the template draws Badge.Segments.
+	segments bool
+}
+
+func newStyleTemplate(tmpl *template.Template) *styleTemplate {
+	prog, ok := compileTemplate(tmpl)
+	return &styleTemplate{tmpl: tmpl, prog: prog, compiled: ok, segments: usesSegments(tmpl)}
+}
+
+// appendTo appends the output of the template for d to dst.
+func (t *styleTemplate) appendTo(dst []byte, d *badgeTemplateData, scratch *[2][]byte) ([]byte, error) {
+	if t.compiled {
+		return t.prog.run(dst, d, scratch), nil
+	}
+	buf := bytes.NewBuffer(dst)
+	err := t.tmpl.Execute(buf, *d)
+	return buf.Bytes(), err
+}
+
+// program is a style template compiled to append its output directly, without
+// reflection. It covers what the built-in templates use: text, fields of the
+// data contract, the add, sub and or functions, if/else, range over
+// .Segments with $ for the data inside it, and the html/template escapers for
+// text, attributes, URLs and template.CSS fields in style elements. Templates
+// using anything else are executed with html/template.
+type program []instr
+
+type instrKind uint8
+
+const (
+	instrText instrKind = iota
+	instrValue
+	instrIf
+	instrRange
+)
+
+type instr struct {
+	kind     instrKind
+	text     string
+	value    valueFunc
+	escapers []escaper
+	then     program
+	orElse   program
+}
+
+type valueKind uint8
+
+const (
+	valueString valueKind = iota
+	valueURL
+	valueCSS
+	valueNumber
+	valueBool
+)
+
+// templateValue is the result of a pipeline before escaping.
+type templateValue struct {
+	kind valueKind
+	s    string
+	f    float64
+	b    bool
+}
+
+func (v templateValue) truth() bool {
+	switch v.kind {
+	case valueNumber:
+		return v.f != 0
+	case valueBool:
+		return v.b
+	case valueString, valueURL, valueCSS:
+		return v.s != ""
+	default:
+		return false
+	}
+}
+
+func (v templateValue) appendTo(dst []byte) []byte {
+	switch v.kind {
+	case valueNumber:
+		return strconv.AppendFloat(dst, v.f, 'g', -1, 64)
+	case valueBool:
+		return strconv.AppendBool(dst, v.b)
+	case valueString, valueURL, valueCSS:
+		return append(dst, v.s...)
+	default:
+		return dst
+	}
+}
+
+// valueFunc evaluates a pipeline for d, with s the segment of the enclosing
+// range over .Segments, nil outside it.
+type valueFunc func(d *badgeTemplateData, s *templateSegment) templateValue
+
+// templateFields reads the fields of the data contract by their path.
+func templateFields() map[string]valueFunc {
+	str := func(get func(d *badgeTemplateData) string) valueFunc {
+		return func(d *badgeTemplateData, _ *templateSegment) templateValue {
+			return templateValue{kind: valueString, s: get(d)}
+		}
+	}
+	num := func(get func(d *badgeTemplateData) float64) valueFunc {
+		return func(d *badgeTemplateData, _ *templateSegment) templateValue {
+			return templateValue{kind: valueNumber, f: get(d)}
+		}
+	}
+	flag := func(get func(d *badgeTemplateData) bool) valueFunc {
+		return func(d *badgeTemplateData, _ *templateSegment) templateValue {
+			return templateValue{kind: valueBool, b: get(d)}
+		}
+	}
+	return map[string]valueFunc{
+		"Subject":           str(func(d *badgeTemplateData) string { return d.Subject }),
+		"Status":            str(func(d *badgeTemplateData) string { return d.Status }),
+		"Color":             str(func(d *badgeTemplateData) string { return d.Color }),
+		"LabelColor":        str(func(d *badgeTemplateData) string { return d.LabelColor }),
+		"ColorOpacity":      str(func(d *badgeTemplateData) string { return d.ColorOpacity }),
+		"LabelColorOpacity": str(func(d *badgeTemplateData) string { return d.LabelColorOpacity }),
+		"Logo": func(d *badgeTemplateData, _ *templateSegment) templateValue {
+			return templateValue{kind: valueURL, s: string(d.Logo)}
+		},
+		"CSS": func(d *badgeTemplateData, _ *templateSegment) templateValue {
+			return templateValue{kind: valueCSS, s: string(d.CSS)}
+		},
+		"TextPaths":            flag(func(d *badgeTemplateData) bool { return d.TextPaths }),
+		"SubjectPath":          str(func(d *badgeTemplateData) string { return d.SubjectPath }),
+		"StatusPath":           str(func(d *badgeTemplateData) string { return d.StatusPath }),
+		"FontFamily":           str(func(d *badgeTemplateData) string { return d.FontFamily }),
+		"FontSize":             num(func(d *badgeTemplateData) float64 { return d.FontSize }),
+		"FontWeight":           str(func(d *badgeTemplateData) string { return d.FontWeight }),
+		"Width":                num(func(d *badgeTemplateData) float64 { return d.Width }),
+		"Height":               num(func(d *badgeTemplateData) float64 { return d.Height }),
+		"Title":                str(func(d *badgeTemplateData) string { return d.Title }),
+		"SubjectLink":          str(func(d *badgeTemplateData) string { return d.SubjectLink }),
+		"StatusLink":           str(func(d *badgeTemplateData) string { return d.StatusLink }),
+		"SubjectRTL":           flag(func(d *badgeTemplateData) bool { return d.SubjectRTL }),
+		"StatusRTL":            flag(func(d *badgeTemplateData) bool { return d.StatusRTL }),
+		"SubjectTextColor":     str(func(d *badgeTemplateData) string { return d.SubjectTextColor }),
+		"SubjectShadowColor":   str(func(d *badgeTemplateData) string { return d.SubjectShadowColor }),
+		"StatusTextColor":      str(func(d *badgeTemplateData) string { return d.StatusTextColor }),
+		"StatusShadowColor":    str(func(d *badgeTemplateData) string { return d.StatusShadowColor }),
+		"SubjectTextOpacity":   str(func(d *badgeTemplateData) string { return d.SubjectTextOpacity }),
+		"StatusTextOpacity":    str(func(d *badgeTemplateData) string { return d.StatusTextOpacity }),
+		"ID":                   str(func(d *badgeTemplateData) string { return d.ID }),
+		"Bounds.Dx":            num(func(d *badgeTemplateData) float64 { return d.Bounds.Dx() }),
+		"Bounds.SubjectStart":  num(func(d *badgeTemplateData) float64 { return d.Bounds.SubjectStart() }),
+		"Bounds.StatusStart":   num(func(d *badgeTemplateData) float64 { return d.Bounds.StatusStart() }),
+		"Bounds.SubjectDx":     num(func(d *badgeTemplateData) float64 { return d.Bounds.SubjectDx }),
+		"Bounds.SubjectX":      num(func(d *badgeTemplateData) float64 { return d.Bounds.SubjectX }),
+		"Bounds.LogoDx":        num(func(d *badgeTemplateData) float64 { return d.Bounds.LogoDx }),
+		"Bounds.LogoX":         num(func(d *badgeTemplateData) float64 { return d.Bounds.LogoX }),
+		"Bounds.Gap":           num(func(d *badgeTemplateData) float64 { return d.Bounds.Gap }),
+		"Bounds.StatusDx":      num(func(d *badgeTemplateData) float64 { return d.Bounds.StatusDx }),
+		"Bounds.StatusX":       num(func(d *badgeTemplateData) float64 { return d.Bounds.StatusX }),
+		"Bounds.SubjectTextDx": num(func(d *badgeTemplateData) float64 { return d.Bounds.SubjectTextDx }),
+		"Bounds.StatusTextDx":  num(func(d *badgeTemplateData) float64 { return d.Bounds.StatusTextDx }),
+		"Bounds.Mirrored":      flag(func(d *badgeTemplateData) bool { return d.Bounds.Mirrored }),
+		"Bounds.FillX":         num(func(d *badgeTemplateData) float64 { return d.Bounds.FillX }),
+		"Bounds.FillDx":        num(func(d *badgeTemplateData) float64 { return d.Bounds.FillDx }),
+		"Bounds.SpinnerX":      num(func(d *badgeTemplateData) float64 { return d.Bounds.SpinnerX }),
+		"Bounds.NotchX":        num(func(d *badgeTemplateData) float64 { return d.Bounds.NotchX }),
+		"Bounds.NotchTipX":     num(func(d *badgeTemplateData) float64 { return d.Bounds.NotchTipX }),
+	}
+}
+
+// segmentFields reads the fields of a segment of .Segments by their path.
+func segmentFields() map[string]valueFunc {
+	str := func(get func(s *templateSegment) string) valueFunc {
+		return func(_ *badgeTemplateData, s *templateSegment) templateValue {
+			return templateValue{kind: valueString, s: get(s)}
+		}
+	}
+	num := func(get func(s *templateSegment) float64) valueFunc {
+		return func(_ *badgeTemplateData, s *templateSegment) templateValue {
+			return templateValue{kind: valueNumber, f: get(s)}
+		}
+	}
+	flag := func(get func(s *templateSegment) bool) valueFunc {
+		return func(_ *badgeTemplateData, s *templateSegment) templateValue {
+			return templateValue{kind: valueBool, b: get(s)}
+		}
+	}
+	return map[string]valueFunc{
+		"Text":         str(func(s *templateSegment) string { return s.Text }),
+		"Color":        str(func(s *templateSegment) string { return s.Color }),
+		"TextColor":    str(func(s *templateSegment) string { return s.TextColor }),
+		"ShadowColor":  str(func(s *templateSegment) string { return s.ShadowColor }),
+		"ColorOpacity": str(func(s *templateSegment) string { return s.ColorOpacity }),
+		"TextOpacity":  str(func(s *templateSegment) string { return s.TextOpacity }),
+		"Link":         str(func(s *templateSegment) string { return s.Link }),
+		"Logo": func(_ *badgeTemplateData, s *templateSegment) templateValue {
+			return templateValue{kind: valueURL, s: string(s.Logo)}
+		},
+		"RTL":              flag(func(s *templateSegment) bool { return s.RTL }),
+		"Bold":             flag(func(s *templateSegment) bool { return s.Bold }),
+		"Label":            flag(func(s *templateSegment) bool { return s.Label }),
+		"Path":             str(func(s *templateSegment) string { return s.Path }),
+		"Class":            str(func(s *templateSegment) string { return s.Class }),
+		"Bounds.Start":     num(func(s *templateSegment) float64 { return s.Bounds.Start }),
+		"Bounds.Dx":        num(func(s *templateSegment) float64 { return s.Bounds.Dx }),
+		"Bounds.X":         num(func(s *templateSegment) float64 { return s.Bounds.X }),
+		"Bounds.TextDx":    num(func(s *templateSegment) float64 { return s.Bounds.TextDx }),
+		"Bounds.LogoX":     num(func(s *templateSegment) float64 { return s.Bounds.LogoX }),
+		"Bounds.LogoDx":    num(func(s *templateSegment) float64 { return s.Bounds.LogoDx }),
+		"Bounds.NotchX":    num(func(s *templateSegment) float64 { return s.Bounds.NotchX }),
+		"Bounds.NotchTipX": num(func(s *templateSegment) float64 { return s.Bounds.NotchTipX }),
+	}
+}
+
+// compileTemplate escapes tmpl and compiles it. It reports false when the
+// template uses anything program does not cover.
+func compileTemplate(tmpl *template.Template) (program, bool) {
+	// html/template escapes the tree on first execution; the error, if any,
+	// is reported again by every render through html/template.
+	if err := tmpl.Execute(io.Discard, badgeTemplateData{}); err != nil {
+		return nil, false
+	}
+	if len(tmpl.Templates()) != 1 || tmpl.Tree == nil || tmpl.Tree.Root == nil {
+		return nil, false
+	}
+	c := &compiler{fields: templateFields(), segment: segmentFields()}
+	return c.list(tmpl.Tree.Root)
+}
+
+type compiler struct {
+	fields  map[string]valueFunc
+	segment map[string]valueFunc
+	// inRange is set while compiling the body of the range over .Segments,
+	// where dot is a segment.
+	inRange bool
+}
+
+func (c *compiler) list(list *parse.ListNode) (program, bool) {
+	if list == nil {
+		return nil, true
+	}
+	prog := make(program, 0, len(list.Nodes))
+	for _, node := range list.Nodes {
+		switch n := node.(type) {
+		case *parse.TextNode:
+			prog = append(prog, instr{kind: instrText, text: string(n.Text)})
+		case *parse.ActionNode:
+			if len(n.Pipe.Decl) > 0 || len(n.Pipe.Cmds) == 0 {
+				return nil, false
+			}
+			value, ok := c.command(n.Pipe.Cmds[0])
+			if !ok {
+				return nil, false
+			}
+			escapers := make([]escaper, 0, len(n.Pipe.Cmds)-1)
+			for _, cmd := range n.Pipe.Cmds[1:] {
+				esc, escOK := escaperFor(cmd)
+				if !escOK || isCall(cmd, cssFilter) && !c.css(n.Pipe.Cmds[0]) {
+					return nil, false
+				}
+				escapers = append(escapers, esc)
+			}
+			prog = append(prog, instr{kind: instrValue, value: value, escapers: escapers})
+		case *parse.IfNode:
+			if len(n.Pipe.Decl) > 0 || len(n.Pipe.Cmds) != 1 {
+				return nil, false
+			}
+			cond, ok := c.command(n.Pipe.Cmds[0])
+			if !ok {
+				return nil, false
+			}
+			then, ok := c.list(n.List)
+			if !ok {
+				return nil, false
+			}
+			orElse, ok := c.list(n.ElseList)
+			if !ok {
+				return nil, false
+			}
+			prog = append(prog, instr{kind: instrIf, value: cond, then: then, orElse: orElse})
+		case *parse.RangeNode:
+			if c.inRange || len(n.Pipe.Decl) > 0 || n.Pipe.String() != ".Segments" {
+				return nil, false
+			}
+			c.inRange = true
+			body, ok := c.list(n.List)
+			c.inRange = false
+			if !ok {
+				return nil, false
+			}
+			orElse, ok := c.list(n.ElseList)
+			if !ok {
+				return nil, false
+			}
+			prog = append(prog, instr{kind: instrRange, then: body, orElse: orElse})
+		default:
+			return nil, false
+		}
+	}
+	return prog, true
+}
+
+// command compiles the first command of a pipeline: a field, a literal, or
+// a call to or, add or sub.
+func (c *compiler) command(cmd *parse.CommandNode) (valueFunc, bool) {
+	if len(cmd.Args) == 1 {
+		return c.arg(cmd.Args[0])
+	}
+	ident, ok := cmd.Args[0].(*parse.IdentifierNode)
+	if !ok {
+		return nil, false
+	}
+	args := make([]valueFunc, 0, len(cmd.Args)-1)
+	for _, node := range cmd.Args[1:] {
+		arg, argOK := c.arg(node)
+		if !argOK {
+			return nil, false
+		}
+		args = append(args, arg)
+	}
+	switch ident.Ident {
+	case "or":
+		return func(d *badgeTemplateData, s *templateSegment) templateValue {
+			var v templateValue
+			for _, arg := range args {
+				if v = arg(d, s); v.truth() {
+					return v
+				}
+			}
+			return v
+		}, true
+	case "add", "sub":
+		if len(args) != 2 || !c.numeric(cmd.Args[1]) || !c.numeric(cmd.Args[2]) {
+			return nil, false
+		}
+		sign := 1.0
+		if ident.Ident == "sub" {
+			sign = -1
+		}
+		a, b := args[0], args[1]
+		return func(d *badgeTemplateData, s *templateSegment) templateValue {
+			return templateValue{kind: valueNumber, f: a(d, s).f + sign*b(d, s).f}
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+func (c *compiler) arg(node parse.Node) (valueFunc, bool) {
+	switch n := node.(type) {
+	case *parse.FieldNode, *parse.VariableNode:
+		return c.field(n)
+	case *parse.StringNode:
+		v := templateValue{kind: valueString, s: n.Text}
+		return func(*badgeTemplateData, *templateSegment) templateValue { return v }, true
+	case *parse.NumberNode:
+		if !n.IsFloat {
+			return nil, false
+		}
+		v := templateValue{kind: valueNumber, f: n.Float64}
+		return func(*badgeTemplateData, *templateSegment) templateValue { return v }, true
+	default:
+		return nil, false
+	}
+}
+
+// field compiles a field of dot, which is a segment inside the range over
+// .Segments, or a field of the data reached through $.
+func (c *compiler) field(node parse.Node) (valueFunc, bool) {
+	switch n := node.(type) {
+	case *parse.FieldNode:
+		fields := c.fields
+		if c.inRange {
+			fields = c.segment
+		}
+		get, ok := fields[strings.Join(n.Ident, ".")]
+		return get, ok
+	case *parse.VariableNode:
+		if len(n.Ident) < 2 || n.Ident[0] != "$" {
+			return nil, false
+		}
+		get, ok := c.fields[strings.Join(n.Ident[1:], ".")]
+		return get, ok
+	default:
+		return nil, false
+	}
+}
+
+// numeric reports whether node is a number or a numeric field, as add and
+// sub take float64 arguments.
+func (c *compiler) numeric(node parse.Node) bool {
+	if n, ok := node.(*parse.NumberNode); ok {
+		return n.IsFloat
+	}
+	get, ok := c.field(node)
+	return ok && get(&badgeTemplateData{}, &templateSegment{}).kind == valueNumber
+}
+
+// css reports whether cmd is a single template.CSS field.
+func (c *compiler) css(cmd *parse.CommandNode) bool {
+	if len(cmd.Args) != 1 {
+		return false
+	}
+	get, ok := c.field(cmd.Args[0])
+	return ok && get(&badgeTemplateData{}, &templateSegment{}).kind == valueCSS
+}
+
+func isCall(cmd *parse.CommandNode, name string) bool {
+	ident, ok := cmd.Args[0].(*parse.IdentifierNode)
+	return ok && len(cmd.Args) == 1 && ident.Ident == name
+}
+
+// run appends the output of prog for d to dst. scratch holds intermediate
+// escaper results so that running allocates nothing once it has grown.
+func (prog program) run(dst []byte, d *badgeTemplateData, scratch *[2][]byte) []byte {
+	return prog.runSegment(dst, d, nil, scratch)
+}
+
+// runSegment runs prog with s as the segment of the enclosing range.
+func (prog program) runSegment(dst []byte, d *badgeTemplateData, s *templateSegment, scratch *[2][]byte) []byte {
+	for i := range prog {
+		in := &prog[i]
+		switch in.kind {
+		case instrText:
+			dst = append(dst, in.text...)
+		case instrValue:
+			dst = appendEscaped(dst, in.value(d, s), in.escapers, scratch)
+		case instrIf:
+			if in.value(d, s).truth() {
+				dst = in.then.runSegment(dst, d, s, scratch)
+			} else {
+				dst = in.orElse.runSegment(dst, d, s, scratch)
+			}
+		case instrRange:
+			if len(d.Segments) == 0 {
+				dst = in.orElse.runSegment(dst, d, s, scratch)
+			}
+			for j := range d.Segments {
+				dst = in.then.runSegment(dst, d, &d.Segments[j], scratch)
+			}
+		default:
+		}
+	}
+	return dst
+}
+
+func appendEscaped(dst []byte, v templateValue, escapers []escaper, scratch *[2][]byte) []byte {
+	if len(escapers) == 0 {
+		return v.appendTo(dst)
+	}
+	src := v.appendTo(scratch[0][:0])
+	scratch[0] = src
+	isURL := v.kind == valueURL
+	for i, esc := range escapers {
+		if i == len(escapers)-1 {
+			dst = esc(dst, src, isURL)
+			break
+		}
+		out := esc(scratch[(i+1)%2][:0], src, isURL)
+		scratch[i%2] = src
+		scratch[(i+1)%2] = out
+		src, isURL = out, false
+	}
+	return dst
+}
@@ -0,0 +1,238 @@
+package renderer
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"testing"
+
+	"golang.org/x/image/font/basicfont"
+)
+
+// tricky holds text that every escaper treats differently.
+const tricky = "a+b <c> & \"d\" 'e' \x00 %41 %zz é \xff javascript:x"
+
+func compiledOutput(t *testing.T, tmpl *styleTemplate, data badgeTemplateData) string {
+	t.Helper()
+	if !tmpl.compiled {
+		t.Fatalf("expected template to compile")
+	}
+	var scratch [2][]byte
+	return string(tmpl.prog.run(nil, &data, &scratch))
+}
+
+func executedOutput(t *testing.T, tmpl *styleTemplate, data badgeTemplateData) string {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	if err := tmpl.tmpl.Execute(buf, data); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	return buf.String()
+}
+
+func TestCompiledTemplatesMatchExecution(t *testing.T) {
+	r, err := NewRendererWithFontFace(basicfont.Face7x13)
+	if err != nil {
+		t.Fatalf("new renderer: %v", err)
+	}
+	badges := []Badge{
+		{Subject: "build", Status: "passing", Color: "green"},
+		{Subject: "build", Status: "passing", Color: "#4c1", LabelColor: "#555", Logo: "bolt", LogoColor: "white"},
+		{Subject: "a+b <c>", Status: `"q" & 'p'`, Color: "red", Title: "x+y <z> & \"w\""},
+		{
+			Subject: "docs", Status: "v1", Color: "blue", IDPrefix: "p_1",
+			Links: [2]string{"https://example.com/a?b=c&d='e'+(f)", "mailto:me@example.com"},
+		},
+		{Subject: "שלום", Status: "עולם", Color: "orange", Scale: 2.5},
+		{Subject: "coverage", Status: "a very long status message", MaxWidth: 120, Overflow: OverflowShrink},
+		{Subject: "logo", Status: "data", Logo: Logo(logoDataURIPrefix + "PHN2Zz4+PC9zdmc+"), LogoWidth: 20},
+		{Subject: "text", Status: "dark", Color: "yellow", TextColor: "#333"},
+		{Subject: "theme", Status: "auto", Color: "green", Dark: Palette{LabelColor: "#222", Color: "teal"}},
+		{Subject: "deploy", Status: "running", Color: "blue", Animation: AnimationSpinner, Dark: Palette{Color: "navy"}},
+		{Subject: "deploy", Status: "running", Color: "blue", Animation: AnimationPulse},
+		{Subject: "release", Status: "v1.2.3", Color: "blue", FontSize: 13.5, FontWeight: FontWeightBold},
+	}
+	type styleKind struct {
+		style Style
+		kind  Kind
+	}
+	var variants []styleKind
+	for _, style := range Styles() {
+		variants = append(variants, styleKind{style: style})
+	}
+	for _, style := range ProgressStyles() {
+		variants = append(variants, styleKind{style: style, kind: KindProgress})
+	}
+	for _, v := range variants {
+		style := v.style
+		tmpl, ok := r.template(style)
+		if v.kind == KindProgress {
+			tmpl, ok = r.progress[style]
+		}
+		if !ok {
+			t.Fatalf("missing style %q", style)
+		}
+		for _, paths := range []bool{false, true} {
+			r.SetTextPaths(paths)
+			for _, b := range badges {
+				b.Style, b.Kind, b.Progress = style, v.kind, 37.5
+				p, prepErr := r.prepare(b)
+				if prepErr != nil {
+					t.Fatalf("%s %+v: %v", style, b, prepErr)
+				}
+				if got, want := compiledOutput(t, tmpl, p.data), executedOutput(t, tmpl, p.data); got != want {
+					t.Fatalf("%s %+v: compiled output differs\n got: %s\nwant: %s", style, b, got, want)
+				}
+			}
+		}
+		// Values the renderer never produces still escape like html/template.
+		data := badgeTemplateData{
+			Subject: tricky, Status: tricky, Color: tricky, LabelColor: tricky, Title: tricky,
+			Logo: template.URL(tricky), FontFamily: tricky, FontSize: 10.25, FontWeight: tricky, SubjectLink: tricky, StatusLink: "HTTPS://x/" + tricky,
+			SubjectPath: tricky, StatusPath: tricky,
+			SubjectTextColor: tricky, StatusTextColor: tricky, CSS: template.CSS(tricky), ID: tricky, Width: 1e21, Height: 0.1,
+			SubjectRTL: true, Bounds: bounds{SubjectDx: 1.5, SubjectTextDx: 3, StatusTextDx: 1e-7, Mirrored: true, FillDx: 2.25, SpinnerX: 7.5},
+		}
+		for _, paths := range []bool{false, true} {
+			data.TextPaths = paths
+			if got, want := compiledOutput(t, tmpl, data), executedOutput(t, tmpl, data); got != want {
+				t.Fatalf("%s: compiled output differs\n got: %s\nwant: %s", style, got, want)
+			}
+		}
+	}
+}
+
+func TestCompiledSegmentTemplatesMatchExecution(t *testing.T) {
+	r, err := NewRendererWithFontFace(basicfont.Face7x13)
+	if err != nil {
+		t.Fatalf("new renderer: %v", err)
+	}
+	badges := []Badge{
+		{Segments: []Segment{{Text: "build", Logo: "bolt"}, {Text: "a+b <c>", Color: "blue", Link: "https://example.com/?a=b&c"}, {Text: "ok"}}},
+		{Segments: []Segment{{Text: "שלום"}, {Text: "עולם", Color: "orange"}}, LabelColor: "#222", Scale: 2},
+		{Segments: []Segment{{Text: "only"}}, Color: "red", Animation: AnimationSpinner, Dark: Palette{Color: "navy"}},
+		{Segments: []Segment{{Text: "a long segment"}, {Text: "another long one"}, {Text: "x"}}, MaxWidth: 130, Overflow: OverflowShrink},
+		{Segments: []Segment{{Logo: Logo(logoDataURIPrefix + "PHN2Zz4+PC9zdmc+")}, {Text: "v1"}}, FontWeight: FontWeightBold, Animation: AnimationPulse},
+	}
+	for _, style := range Styles() {
+		tmpl := r.segments[style]
+		for _, paths := range []bool{false, true} {
+			r.SetTextPaths(paths)
+			for _, b := range badges {
+				b.Style = style
+				p, prepErr := r.prepare(b)
+				if prepErr != nil {
+					t.Fatalf("%s %+v: %v", style, b, prepErr)
+				}
+				if got, want := compiledOutput(t, tmpl, p.data), executedOutput(t, tmpl, p.data); got != want {
+					t.Fatalf("%s %+v: compiled output differs\n got: %s\nwant: %s", style, b, got, want)
+				}
+			}
+		}
+		segment := templateSegment{
+			Text: tricky, Color: tricky, TextColor: tricky, ShadowColor: tricky, Link: tricky,
+			Logo: template.URL(tricky), RTL: true, Bold: true, Label: true, Path: tricky, Class: tricky,
+			Bounds: segmentBounds{Start: 1.5, Dx: 3, X: 1e21, TextDx: 1e-7, LogoX: 2, LogoDx: 14},
+		}
+		data := badgeTemplateData{Title: tricky, CSS: template.CSS(tricky), ID: tricky, FontWeight: tricky, Segments: []templateSegment{segment, {}}}
+		for _, paths := range []bool{false, true} {
+			data.TextPaths = paths
+			if got, want := compiledOutput(t, tmpl, data), executedOutput(t, tmpl, data); got != want {
+				t.Fatalf("%s: compiled output differs\n got: %s\nwant: %s", style, got, want)
+			}
+		}
+	}
+}
+
+func TestCompileCustomTemplates(t *testing.T) {
+	cases := []struct {
+		src      string
+		compiled bool
+	}{
+		{`<svg data-a="{{.Subject}}" href="{{.StatusLink}}">{{.Title}}{{or .LabelColor "#555" | html}}` +
+			`{{if .Bounds.Mirrored}}{{sub .Bounds.Dx .5}}{{else}}{{add .Width 1}}{{end}}{{.SubjectRTL}}</svg>`, true},
+		{`<svg><image href="{{.Logo}}"/><a href="{{.SubjectLink}}">{{.Status | html}}</a></svg>`, true},
+		{`{{define "part"}}{{.Subject}}{{end}}<svg>{{template "part" .}}</svg>`, false},
+		{`<svg>{{with .Bounds}}{{.Dx}}{{end}}</svg>`, false},
+		{`<svg><style>{{.CSS}}</style></svg>`, true},
+		{`<svg><style>{{.Color}}</style></svg>`, false},
+		{`<svg><style>{{or .CSS .Color}}</style></svg>`, false},
+		{`<svg>{{$x := .Subject}}{{$x}}</svg>`, false},
+		{`<svg>{{if eq .Subject "a"}}a{{end}}</svg>`, false},
+		{`<svg>{{range .Segments}}<a href="{{.Link}}">{{.Text}}{{$.Title}}</a>{{else}}{{.Subject}}{{end}}</svg>`, true},
+		{`<svg>{{range .Segments}}{{add .Bounds.X $.Width}}{{end}}</svg>`, true},
+		{`<svg>{{range $s := .Segments}}{{$s.Text}}{{end}}</svg>`, false},
+		{`<svg>{{range .Segments}}{{range $.Segments}}{{.Text}}{{end}}{{end}}</svg>`, false},
+	}
+	data := badgeTemplateData{
+		Subject: tricky, Status: tricky, Title: tricky, LabelColor: tricky, SubjectLink: tricky,
+		StatusLink: "https://x/" + tricky, Logo: template.URL(tricky), CSS: "a{fill:red}", Width: 12.25,
+		Bounds:   bounds{SubjectDx: 10, StatusDx: 20.5, Mirrored: true},
+		Segments: []templateSegment{{Text: tricky, Link: "https://x/" + tricky, Bounds: segmentBounds{X: 4.5}}, {Text: "b"}},
+	}
+	for _, tc := range cases {
+		parsed, err := parseTemplate("custom", tc.src)
+		if err != nil {
+			t.Fatalf("%s: parse: %v", tc.src, err)
+		}
+		tmpl := newStyleTemplate(parsed)
+		if tmpl.compiled != tc.compiled {
+			t.Fatalf("%s: expected compiled %v, got %v", tc.src, tc.compiled, tmpl.compiled)
+		}
+		var scratch [2][]byte
+		got, err := tmpl.appendTo(nil, &data, &scratch)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.src, err)
+		}
+		if want := executedOutput(t, tmpl, data); string(got) != want {
+			t.Fatalf("%s: output differs\n got: %s\nwant: %s", tc.src, got, want)
+		}
+	}
+}
+
+func TestCompiledTemplateDoesNotAllocate(t *testing.T) {
+	r, err := NewRendererWithFontFace(basicfont.Face7x13)
+	if err != nil {
+		t.Fatalf("new renderer: %v", err)
+	}
+	for _, b := range []Badge{
+		{Subject: "build", Status: "a+b", Color: "green", Logo: "bolt", Title: "<t>"},
+		{Segments: []Segment{{Text: "build", Logo: "bolt"}, {Text: "a+b"}, {Text: "<c>", Link: "https://example.com"}}},
+	} {
+		p, prepErr := r.prepare(b)
+		if prepErr != nil {
+			t.Fatalf("prepare: %v", prepErr)
+		}
+		var scratch [2][]byte
+		out, _ := p.tmpl.appendTo(nil, &p.data, &scratch)
+		allocs := testing.AllocsPerRun(100, func() {
+			out, _ = p.tmpl.appendTo(out[:0], &p.data, &scratch)
+		})
+		if allocs != 0 {
+			t.Fatalf("%+v: expected no allocations, got %v", b, allocs)
+		}
+
+		b.IDPrefix = "badge"
+		allocs = testing.AllocsPerRun(100, func() {
+			_ = renderTemplateID(p.style, b.IDPrefix, &p.data)
+		})
+		if allocs != 1 {
+			t.Fatalf("%+v: expected the id to allocate only its string, got %v", b, allocs)
+		}
+
+		// A cache miss adds to prepare only the prepared badge, which the
+		// template reads through a pointer, and the copy kept for the cache.
+		r.SetCacheSize(0)
+		prepareAllocs := testing.AllocsPerRun(100, func() {
+			_, _ = r.prepare(b)
+		})
+		allocs = testing.AllocsPerRun(100, func() {
+			if renderErr := r.RenderTo(io.Discard, b); renderErr != nil {
+				t.Fatalf("render: %v", renderErr)
+			}
+		})
+		if allocs > prepareAllocs+2 {
+			t.Fatalf("%+v: expected at most %v allocations on a cache miss, got %v", b, prepareAllocs+2, allocs)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package renderer
+
+import (
+	"image/color"
+	"math"
+)
+
+// WCAG 2 minimum contrast ratios.
+const (
+	// ContrastAA is the minimum ratio for normal text at level AA.
+	ContrastAA = 4.5
+	// ContrastAALarge is the minimum ratio for large text at level AA.
+	ContrastAALarge = 3.0
+	// ContrastAAA is the minimum ratio for normal text at level AAA.
+	ContrastAAA = 7.0
+)
+
+const (
+	lightTextColor  = "#fff"
+	lightTextShadow = "#010101"
+	darkTextColor   = "#333"
+
+	// minLightTextContrast keeps white text on brightgreen (2.1:1) and darker
+	// colors, and switches to dark text on green, yellow and lighter ones.
+	minLightTextContrast = 2.0
+	// midLuminance is where a color contrasts equally with black and white.
+	midLuminance = 0.179
+)
+
+// Luminance returns the WCAG relative luminance of the color, from 0 for
+// black to 1 for white. Translucent colors are composited over white.
+// It reports false for empty and invalid colors.
+func (c Color) Luminance() (float64, bool) {
+	rgba, ok := c.NRGBA()
+	if !ok {
+		return 0, false
+	}
+	return relativeLuminance(rgba), true
+}
+
+// ContrastRatio returns the WCAG contrast ratio between two colors, from 1 to
+// 21. Compare it against ContrastAA or ContrastAALarge to check legibility.
+// It reports false when either color is empty or invalid.
+func ContrastRatio(a, b Color) (float64, bool) {
+	la, ok := a.Luminance()
+	if !ok {
+		return 0, false
+	}
+	lb, ok := b.Luminance()
+	if !ok {
+		return 0, false
+	}
+	return contrastRatio(la, lb), true
+}
+
+func contrastRatio(a, b float64) float64 {
+	const flare = 0.05
+	return (math.Max(a, b) + flare) / (math.Min(a, b) + flare)
+}
+
+func relativeLuminance(c color.NRGBA) float64 {
+	alpha := float64(c.A) / maxByte
+	channel := func(v uint8) float64 {
+		s := float64(v)/maxByte*alpha + 1 - alpha
+		if s <= 0.04045 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(c.R) + 0.7152*channel(c.G) + 0.0722*channel(c.B)
+}
+
+// textColors picks the text and shadow color for a segment filled with
+// background. A valid override is used as is, with a shadow that contrasts
+// with it; otherwise light text is kept while it stays legible enough.
+func textColors(background, override Color, m styleMetrics) (string, string) {
+	if override != "" {
+		if l, ok := override.Luminance(); ok && l < midLuminance {
+			return override.String(), m.darkTextShadow
+		}
+		return override.String(), lightTextShadow
+	}
+	l, ok := background.Luminance()
+	if !ok || contrastRatio(l, 1) >= minLightTextContrast {
+		return lightTextColor, lightTextShadow
+	}
+	return darkTextColor, m.darkTextShadow
+}
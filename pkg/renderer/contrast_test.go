@@ -0,0 +1,75 @@
+package renderer_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/rhajizada/signum/pkg/renderer"
+)
+
+func TestContrastRatio(t *testing.T) {
+	cases := []struct {
+		a, b     renderer.Color
+		expected float64
+	}{
+		{"#fff", "#000", 21},
+		{"black", "white", 21},
+		{"#777", "#777", 1},
+		{"#fff", "#767676", 4.54},
+		{"#fff", renderer.ColorBrightgreen, 2.12},
+		{"#00000000", "#fff", 1},
+	}
+	for _, tc := range cases {
+		ratio, ok := renderer.ContrastRatio(tc.a, tc.b)
+		if !ok {
+			t.Fatalf("expected %q and %q to be valid", tc.a, tc.b)
+		}
+		if math.Abs(ratio-tc.expected) > 0.01 {
+			t.Fatalf("expected contrast of %q and %q to be %.2f, got %.2f", tc.a, tc.b, tc.expected, ratio)
+		}
+	}
+	if ratio, _ := renderer.ContrastRatio("#fff", "#767676"); ratio < renderer.ContrastAA {
+		t.Fatalf("expected #767676 on white to meet AA, got %.2f", ratio)
+	}
+	if _, ok := renderer.ContrastRatio("#fff", "not-a-color"); ok {
+		t.Fatalf("expected invalid color to be rejected")
+	}
+	if _, ok := renderer.Color("").Luminance(); ok {
+		t.Fatalf("expected empty color to have no luminance")
+	}
+}
+
+func TestRendererRenderTextContrast(t *testing.T) {
+	r := newRenderer(t)
+	cases := []struct {
+		badge   renderer.Badge
+		subject string
+		status  string
+	}{
+		{renderer.Badge{Color: renderer.ColorBrightgreen}, "#fff", "#fff"},
+		{renderer.Badge{Color: renderer.ColorYellow}, "#fff", "#333"},
+		{renderer.Badge{Color: "#fff", LabelColor: "lightyellow"}, "#333", "#333"},
+		{renderer.Badge{Color: renderer.ColorYellow, TextColor: "navy"}, "#000080", "#000080"},
+		{renderer.Badge{Color: renderer.ColorBlue, Style: renderer.StyleSocial}, "#333", "#333"},
+	}
+	for _, tc := range cases {
+		tc.badge.Subject, tc.badge.Status = "build", "passing"
+		output, err := r.Render(tc.badge)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		svg := string(output)
+		if !strings.Contains(svg, `y="14" fill="`+tc.subject+`">build<`) {
+			t.Fatalf("expected subject text %s for %#v, got %s", tc.subject, tc.badge, svg)
+		}
+		if !strings.Contains(svg, `y="14" fill="`+tc.status+`">passing<`) {
+			t.Fatalf("expected status text %s for %#v, got %s", tc.status, tc.badge, svg)
+		}
+	}
+
+	_, err := r.Render(renderer.Badge{Subject: "build", Status: "passing", Color: "green", TextColor: "not-a-color"})
+	if err == nil {
+		t.Fatalf("expected error for invalid text color")
+	}
+}
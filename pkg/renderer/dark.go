@@ -0,0 +1,124 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Palette is an alternate set of badge colors. Empty colors keep the ones of
+// the badge.
+type Palette struct {
+	// LabelColor fills the subject segment.
+	LabelColor Color `json:"label_color,omitempty"`
+	// Color fills the status segment.
+	Color Color `json:"color,omitempty"`
+	// TextColor overrides the text color of both segments. Empty picks light
+	// or dark text per segment from its fill, as for Badge.TextColor.
+	TextColor Color `json:"text_color,omitempty"`
+}
+
+// IsZero reports whether the palette changes no color.
+func (p Palette) IsZero() bool {
+	return p == Palette{}
+}
+
+func (p Palette) validate() error {
+	if !p.LabelColor.IsValid() {
+		return fmt.Errorf("invalid dark label color: %q", p.LabelColor)
+	}
+	if !p.Color.IsValid() {
+		return fmt.Errorf("invalid dark color: %q", p.Color)
+	}
+	if !p.TextColor.IsValid() {
+		return fmt.Errorf("invalid dark text color: %q", p.TextColor)
+	}
+	return nil
+}
+
+// badgeColors are the resolved fills of a badge.
+type badgeColors struct {
+	subject, status            string
+	subjectText, subjectShadow string
+	statusText, statusShadow   string
+}
+
+// resolveColors returns the colors of b as drawn with the metrics m.
+func resolveColors(b Badge, m styleMetrics) badgeColors {
+	labelColor, color := segmentColors(b, m)
+	var c badgeColors
+	c.subject, c.status = labelColor.String(), color.String()
+	c.subjectText, c.subjectShadow = textColors(labelColor, b.TextColor, m)
+	c.statusText, c.statusShadow = textColors(color, b.TextColor, m)
+	return c
+}
+
+// withDark returns b with the colors of b.Dark in place of its own.
+func withDark(b Badge) Badge {
+	if b.Dark.LabelColor != "" {
+		b.LabelColor = b.Dark.LabelColor
+	}
+	if b.Dark.Color != "" {
+		b.Color = b.Dark.Color
+	}
+	if b.Dark.TextColor != "" {
+		b.TextColor = b.Dark.TextColor
+	}
+	return b
+}
+
+// darkRules returns the CSS rules that repaint the elements under scope with
+// the dark palette when the reader prefers a dark color scheme.
+func darkRules(b Badge, m styleMetrics, scope string) string {
+	light, dark := resolveColors(b, m), resolveColors(withDark(b), m)
+	var sb strings.Builder
+	sb.WriteString("@media (prefers-color-scheme:dark){")
+	for _, rule := range [...][3]string{
+		{"subject", light.subject, dark.subject},
+		{"status", light.status, dark.status},
+		{"subject-text", light.subjectText, dark.subjectText},
+		{"subject-shadow", light.subjectShadow, dark.subjectShadow},
+		{"status-text", light.statusText, dark.statusText},
+		{"status-shadow", light.statusShadow, dark.statusShadow},
+	} {
+		sb.WriteString(scope + rule[0] + "{" + paintRule("fill", rule[1], rule[2]) + "}")
+	}
+	sb.WriteString(scope + "spinner{" + paintRule("stroke", light.statusText, dark.statusText) + "}}")
+	return sb.String()
+}
+
+// darkSegmentRules returns the dark palette rules of a badge with
+// Badge.Segments. The palette colors replace LabelColor and Color, so they
+// repaint the segments without a color of their own.
+func darkSegmentRules(b Badge, m styleMetrics, scope string) string {
+	lightFills := segmentFills(b, m)
+	dark := withDark(b)
+	var sb strings.Builder
+	sb.WriteString("@media (prefers-color-scheme:dark){")
+	var lightText, text string
+	for i, fill := range segmentFills(dark, m) {
+		class := segmentClass(i, len(lightFills))
+		var lightShadow, shadow string
+		lightText, lightShadow = textColors(lightFills[i], b.TextColor, m)
+		text, shadow = textColors(fill, dark.TextColor, m)
+		sb.WriteString(scope + class + "{" + paintRule("fill", lightFills[i].String(), fill.String()) + "}")
+		sb.WriteString(scope + class + "-text{" + paintRule("fill", lightText, text) + "}")
+		sb.WriteString(scope + class + "-shadow{" + paintRule("fill", lightShadow, shadow) + "}")
+	}
+	sb.WriteString(scope + "spinner{" + paintRule("stroke", lightText, text) + "}}")
+	return sb.String()
+}
+
+// paintRule returns the CSS declarations that paint property, fill or
+// stroke, with color in place of light. A translucent color is split into
+// the paint and its opacity as in the templates, and the opacity that a
+// translucent light color left on the element is reset.
+func paintRule(property, light, color string) string {
+	paint, opacity := svgPaint(color)
+	if _, lightOpacity := svgPaint(light); opacity == "" && lightOpacity != "" {
+		opacity = "1"
+	}
+	if opacity == "" {
+		return property + ":" + paint
+	}
+	return property + ":" + paint + ";" + property + "-opacity:" + opacity
+}
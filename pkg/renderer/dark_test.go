@@ -0,0 +1,99 @@
+package renderer_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/rhajizada/signum/pkg/renderer"
+)
+
+var darkID = regexp.MustCompile(`<svg [^>]* id="badge-([0-9a-f]+)"`)
+
+func TestRenderDarkPalette(t *testing.T) {
+	r := newRenderer(t)
+	for _, style := range renderer.Styles() {
+		light := renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen, Style: style}
+		svg, err := r.Render(light)
+		if err != nil {
+			t.Fatalf("render %s: %v", style, err)
+		}
+		if strings.Contains(string(svg), "<style>") || strings.Contains(string(svg), "class=") {
+			t.Fatalf("%s: expected no dark palette without one: %s", style, svg)
+		}
+
+		dark := light
+		dark.Dark = renderer.Palette{LabelColor: "#222", Color: "darkgreen"}
+		svg, err = r.Render(dark)
+		if err != nil {
+			t.Fatalf("render %s: %v", style, err)
+		}
+		match := darkID.FindSubmatch(svg)
+		if match == nil {
+			t.Fatalf("%s: expected a badge id: %s", style, svg)
+		}
+		scope := "#badge-" + string(match[1]) + " ."
+		want := "<style>@media (prefers-color-scheme:dark){" + scope + "subject{fill:#222}"
+		if !strings.Contains(string(svg), want) {
+			t.Fatalf("%s: expected %q in %s", style, want, svg)
+		}
+		for _, class := range []string{"subject", "status", "subject-text", "status-text"} {
+			if !strings.Contains(string(svg), `class="`+class+`"`) {
+				t.Fatalf("%s: expected class %q: %s", style, class, svg)
+			}
+		}
+		if style == renderer.StyleSocial {
+			continue
+		}
+		if !strings.Contains(string(svg), scope+"status{fill:#006400}") {
+			t.Fatalf("%s: expected the dark status color: %s", style, svg)
+		}
+		// Light text on the dark green, where green takes dark text.
+		if !strings.Contains(string(svg), scope+"status-text{fill:#fff}") {
+			t.Fatalf("%s: expected light status text in dark mode: %s", style, svg)
+		}
+	}
+}
+
+func TestRenderDarkPaletteTextColor(t *testing.T) {
+	r := newRenderer(t)
+	svg, err := r.Render(renderer.Badge{
+		Subject: "build", Status: "passing", Color: renderer.ColorBlue,
+		Dark: renderer.Palette{TextColor: "#eee"},
+	})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	for _, class := range []string{"subject-text", "status-text"} {
+		if !strings.Contains(string(svg), class+"{fill:#eee}") {
+			t.Fatalf("expected %s to use the dark text color: %s", class, svg)
+		}
+	}
+}
+
+func TestRenderDarkPaletteOpacity(t *testing.T) {
+	r := newRenderer(t)
+	svg, err := r.Render(renderer.Badge{
+		Subject: "build", Status: "passing", Color: "#00800080", LabelColor: "#222",
+		Dark: renderer.Palette{LabelColor: "rgba(255, 255, 255, 0.25)", Color: "darkgreen"},
+	})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	// The translucent dark subject is split like the fills of the template,
+	// and the opaque dark status resets the opacity of its light fill.
+	for _, want := range []string{"subject{fill:#fff;fill-opacity:0.251}", "status{fill:#006400;fill-opacity:1}"} {
+		if !strings.Contains(string(svg), want) {
+			t.Fatalf("expected %q in %s", want, svg)
+		}
+	}
+}
+
+func TestRenderDarkPaletteInvalid(t *testing.T) {
+	r := newRenderer(t)
+	for _, dark := range []renderer.Palette{{LabelColor: "nope"}, {Color: "nope"}, {TextColor: "nope"}} {
+		if _, err := r.Render(renderer.Badge{Subject: "a", Status: "b", Dark: dark}); err == nil {
+			t.Fatalf("%+v: expected error", dark)
+		}
+	}
+}
@@ -1,2 +1,87 @@
-// Package renderer provides SVG badge rendering with multiple style templates.
+// Package renderer provides SVG and PNG badge rendering with multiple style templates.
+//
+// # Custom styles
+//
+// Renderer.RegisterStyle and Renderer.LoadStyleDir add styles from html/template
+// sources. Templates are executed with the following data, and references to
+// any other field are rejected at registration:
+//
+//	.Subject, .Status     text to draw, already uppercased or truncated by the style
+//	.Color, .LabelColor   status and subject fill colors; empty means the default
+//	.ColorOpacity         fill-opacity of a translucent .Color, which then holds the
+//	                      color without alpha; empty when it is opaque, and likewise
+//	                      .LabelColorOpacity for .LabelColor
+//	.Logo                 logo data URI for xlink:href; empty when there is no logo
+//	.Title                accessible name for <title> and aria-label
+//	.SubjectLink          subject link target; empty when the subject is unlinked
+//	.StatusLink           status link target; empty when the status is unlinked
+//	.FontFamily           CSS font-family list matching the measuring fonts
+//	.FontSize             text size in pixels, 11 unless Badge.FontSize is set
+//	.FontWeight           "bold" for Badge.FontWeight bold text, empty otherwise
+//	.SubjectRTL           true when the subject is right-to-left text
+//	.StatusRTL            true when the status is right-to-left text
+//	.SubjectTextColor     subject text color that contrasts with the subject fill
+//	.SubjectShadowColor   subject text shadow color
+//	.StatusTextColor      status text color that contrasts with the status fill
+//	.StatusShadowColor    status text shadow color
+//	.SubjectTextOpacity   fill-opacity of a translucent .SubjectTextColor, empty when
+//	                      it is opaque; .StatusTextOpacity is that of .StatusTextColor
+//	.TextPaths            true when text is drawn with .SubjectPath and .StatusPath
+//	.SubjectPath          subject outlines as path data, centered on x = 0 with the
+//	                      baseline at y = 0; .StatusPath holds the status outlines
+//	.ID                   short hash to keep gradient and mask ids unique per badge
+//	.CSS                  rules for a <style> element of Badge.Dark and Badge.Animation
+//	.Width, .Height       outer size of the scaled badge, for the svg element
+//	.Bounds.Dx            total badge width, before scaling
+//	.Bounds.SubjectStart  x of the subject segment; .Bounds.SubjectDx is its width
+//	.Bounds.StatusStart   x of the status segment; .Bounds.StatusDx is its width
+//	.Bounds.SubjectX      x of the subject text anchor (text-anchor="middle")
+//	.Bounds.StatusX       x of the status text anchor (text-anchor="middle")
+//	.Bounds.LogoX         x of the logo; .Bounds.LogoDx is its width
+//	.Bounds.Gap           space between the segments
+//	.Bounds.SubjectTextDx textLength for compressed subject text, zero otherwise
+//	.Bounds.StatusTextDx  textLength for compressed status text, zero otherwise
+//	.Bounds.Mirrored      true when the badge is laid out right to left
+//	.Bounds.FillX         x of the progress bar fill; .Bounds.FillDx is its width
+//	.Bounds.SpinnerX      x of the AnimationSpinner ring center, zero without one
+//	.Bounds.NotchX        x where the arrow of the StyleSocial status bubble leaves
+//	                      it, zero without one; .Bounds.NotchTipX is its tip
+//	.Segments             segments of badges with Badge.Segments, nil otherwise
+//
+// Badges with Badge.Segments are drawn by styles whose template ranges over
+// .Segments; the data then has no subject or status. Inside the range, dot
+// is a segment and $ the badge data:
+//
+//	.Text                 text to draw, already uppercased or truncated by the style
+//	.Color                fill color of the segment
+//	.TextColor            text color that contrasts with the fill
+//	.ShadowColor          text shadow color
+//	.ColorOpacity         fill-opacity of a translucent .Color, empty when it is
+//	                      opaque; .TextOpacity is that of .TextColor
+//	.Link                 link target; empty when the segment is unlinked
+//	.Logo                 logo data URI; empty when the segment has no logo
+//	.RTL                  true when the text is right-to-left
+//	.Bold                 true when the style draws the text bold
+//	.Label                true for the first of several segments
+//	.Path                 text outlines when $.TextPaths is set
+//	.Class                class of the segment in the $.CSS rules
+//	.Bounds.Start         x of the segment; .Bounds.Dx is its width
+//	.Bounds.X             x of the text anchor (text-anchor="middle")
+//	.Bounds.TextDx        textLength for compressed text, zero otherwise
+//	.Bounds.LogoX         x of the logo; .Bounds.LogoDx is its width
+//	.Bounds.NotchX        x where the arrow pointing at the previous segment leaves
+//	                      it, zero without one; .Bounds.NotchTipX is its tip
+//
+// Widths are measured for .FontSize text with 13px of padding per segment, as
+// for StyleFlat, and the badge is expected to be 20px tall. Draw in unscaled
+// units and set viewBox="0 0 {{.Bounds.Dx}} 20" on the svg element, sized
+// with .Width and .Height, so Badge.Scale applies. The add and sub functions
+// are available for arithmetic on coordinates. Place the text paths with a
+// transform, e.g. transform="translate({{.Bounds.SubjectX}} 14)".
+//
+// The .CSS rules target elements of the svg element with id="badge-{{.ID}}"
+// by class: subject and status for the segment fills, subject-text,
+// subject-shadow, status-text and status-shadow for the text, and spinner for
+// the spinner ring. Segments take their .Class, with the -text and -shadow
+// suffixes for their text.
 package renderer
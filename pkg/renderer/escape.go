@@ -0,0 +1,124 @@
+package renderer
+
+import (
+	"bytes"
+	"strings"
+	"text/template/parse"
+)
+
+// escaper appends src escaped as an html/template escaper would. isURL marks
+// template.URL content, which the URL escapers trust.
+type escaper func(dst, src []byte, isURL bool) []byte
+
+// escaperFor returns the escaper called by cmd, if it is one html/template
+// inserted for text, attribute, URL or CSS contexts, or the html builtin.
+func escaperFor(cmd *parse.CommandNode) (escaper, bool) {
+	if len(cmd.Args) != 1 {
+		return nil, false
+	}
+	ident, ok := cmd.Args[0].(*parse.IdentifierNode)
+	if !ok {
+		return nil, false
+	}
+	switch ident.Ident {
+	case "_html_template_attrescaper", "_html_template_htmlescaper", "_html_template_rcdataescaper":
+		return escapeAttr, true
+	case "html":
+		return escapeHTML, true
+	case "_html_template_urlfilter":
+		return filterURL, true
+	case "_html_template_urlnormalizer":
+		return normalizeURL, true
+	case cssFilter:
+		return passCSS, true
+	default:
+		return nil, false
+	}
+}
+
+// escapeAttr matches html/template's escaping of plain text in text and
+// attribute contexts.
+func escapeAttr(dst, src []byte, _ bool) []byte {
+	return escapeBytes(dst, src, true)
+}
+
+// escapeHTML matches the html builtin, which leaves '+' as is.
+func escapeHTML(dst, src []byte, _ bool) []byte {
+	return escapeBytes(dst, src, false)
+}
+
+func escapeBytes(dst, src []byte, plus bool) []byte {
+	last := 0
+	for i, c := range src {
+		var repl string
+		switch c {
+		case 0:
+			repl = "\uFFFD"
+		case '"':
+			repl = "&#34;"
+		case '&':
+			repl = "&amp;"
+		case '\'':
+			repl = "&#39;"
+		case '<':
+			repl = "&lt;"
+		case '>':
+			repl = "&gt;"
+		case '+':
+			if !plus {
+				continue
+			}
+			repl = "&#43;"
+		default:
+			continue
+		}
+		dst = append(dst, src[last:i]...)
+		dst = append(dst, repl...)
+		last = i + 1
+	}
+	return append(dst, src[last:]...)
+}
+
+// filterFailsafe replaces URLs with schemes other than http, https and mailto.
+const filterFailsafe = "#ZgotmplZ"
+
+func filterURL(dst, src []byte, isURL bool) []byte {
+	if !isURL {
+		if scheme, _, ok := bytes.Cut(src, []byte(":")); ok && !bytes.Contains(scheme, []byte("/")) &&
+			!bytes.EqualFold(scheme, []byte("http")) && !bytes.EqualFold(scheme, []byte("https")) &&
+			!bytes.EqualFold(scheme, []byte("mailto")) {
+			return append(dst, filterFailsafe...)
+		}
+	}
+	return append(dst, src...)
+}
+
+// normalizeURL percent-encodes bytes that are not valid in a URL, keeping
+// reserved characters and existing escapes.
+func normalizeURL(dst, src []byte, _ bool) []byte {
+	const hexDigits = "0123456789abcdef"
+	for i, c := range src {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case strings.IndexByte("!#$&*+,/:;=?@[]-._~", c) >= 0:
+		case c == '%' && i+2 < len(src) && isHex(src[i+1]) && isHex(src[i+2]):
+		default:
+			dst = append(dst, '%', hexDigits[c>>4], hexDigits[c&0xf])
+			continue
+		}
+		dst = append(dst, c)
+	}
+	return dst
+}
+
+// cssFilter is the escaper of CSS contexts. It passes template.CSS content as
+// is and rejects anything else, so the compiler only accepts it on CSS fields.
+const cssFilter = "_html_template_cssvaluefilter"
+
+func passCSS(dst, src []byte, _ bool) []byte {
+	return append(dst, src...)
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
@@ -0,0 +1,202 @@
+package renderer
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font/sfnt"
+)
+
+// maxFontSize bounds the decoded size of a font file, so a WOFF file cannot
+// inflate without limit.
+const maxFontSize = 64 << 20
+
+// SplitFontIndex splits a face index selector off a font name, as in
+// "fonts.ttc#2" for the third face of a collection. Names without a selector
+// select the first face.
+func SplitFontIndex(name string) (string, int) {
+	i := strings.LastIndexByte(name, '#')
+	if i < 0 {
+		return name, 0
+	}
+	digits := name[i+1:]
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return name, 0
+	}
+	index, err := strconv.Atoi(digits)
+	if err != nil {
+		return name, 0
+	}
+	return name[:i], index
+}
+
+// NewRendererFromFS measures text with the font file name in fsys, which
+// may carry a face index selector, e.g. "fonts/NotoSansCJK.ttc#1". The file
+// may be a TrueType or CFF-based OpenType font, a TTC/OTC collection or a
+// WOFF file.
+func NewRendererFromFS(fsys fs.FS, name string) (*Renderer, error) {
+	file, index := SplitFontIndex(name)
+	data, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return nil, err
+	}
+	return newRendererFromFonts([]fontData{{name: name, data: data, index: index}})
+}
+
+// fontData is the contents of a font file and the face to load from it.
+type fontData struct {
+	name  string
+	data  []byte
+	index int
+}
+
+func newRendererFromFonts(files []fontData) (*Renderer, error) {
+	faces := make([]fontFace, 0, len(files))
+	families := make([]string, 0, len(files))
+	for _, file := range files {
+		f, err := parseFont(file.data, file.index)
+		if err != nil {
+			return nil, fmt.Errorf("parse font %s: %w", file.name, err)
+		}
+		faces = append(faces, newSFNTFace(f, fontsize, dpi))
+		family, _ := f.Name(nil, sfnt.NameIDFamily)
+		if family = fontFamilyName(family); family != "" {
+			families = append(families, family)
+		}
+	}
+	return newRenderer(newFontMeasurer(faces), families)
+}
+
+// readFontPaths reads the font files of paths with face index selectors.
+func readFontPaths(paths []string) ([]fontData, error) {
+	files := make([]fontData, 0, len(paths))
+	for _, path := range paths {
+		if path == "" {
+			return nil, errors.New("font path is required")
+		}
+		file, index := SplitFontIndex(path)
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, fontData{name: path, data: data, index: index})
+	}
+	return files, nil
+}
+
+// parseFont parses face index of a font file or collection.
+func parseFont(data []byte, index int) (*sfnt.Font, error) {
+	collection, err := parseCollection(data)
+	if err != nil {
+		return nil, err
+	}
+	if index >= collection.NumFonts() {
+		return nil, fmt.Errorf("face index %d out of range, the file has %d", index, collection.NumFonts())
+	}
+	return collection.Font(index)
+}
+
+// parseCollection parses the faces of a font file, which holds one face
+// unless it is a collection.
+func parseCollection(data []byte) (*sfnt.Collection, error) {
+	if len(data) >= 4 {
+		switch string(data[:4]) {
+		case "wOFF":
+			var err error
+			if data, err = decodeWOFF(data); err != nil {
+				return nil, err
+			}
+		case "wOF2":
+			return nil, errors.New("WOFF2 fonts are not supported")
+		}
+	}
+	return sfnt.ParseCollection(data)
+}
+
+const (
+	woffHeaderSize      = 44
+	woffTableRecordSize = 20
+	sfntHeaderSize      = 12
+	sfntTableRecordSize = 16
+)
+
+// decodeWOFF rebuilds the SFNT font wrapped in a WOFF 1.0 file, inflating
+// its compressed tables.
+func decodeWOFF(data []byte) ([]byte, error) {
+	if len(data) < woffHeaderSize {
+		return nil, errors.New("invalid WOFF header")
+	}
+	be := binary.BigEndian
+	flavor := be.Uint32(data[4:])
+	numTables := int(be.Uint16(data[12:]))
+	if len(data) < woffHeaderSize+numTables*woffTableRecordSize {
+		return nil, errors.New("invalid WOFF table directory")
+	}
+	offset := sfntHeaderSize + numTables*sfntTableRecordSize
+	out := make([]byte, offset)
+	be.PutUint32(out, flavor)
+	be.PutUint16(out[4:], uint16(numTables))
+	entrySelector := 0
+	for 2<<entrySelector <= numTables {
+		entrySelector++
+	}
+	searchRange := 16 << entrySelector
+	be.PutUint16(out[6:], uint16(searchRange))
+	be.PutUint16(out[8:], uint16(entrySelector))
+	be.PutUint16(out[10:], uint16(numTables*16-searchRange))
+	for i := range numTables {
+		entry := data[woffHeaderSize+i*woffTableRecordSize:]
+		tableOffset := int(be.Uint32(entry[4:]))
+		compLength := int(be.Uint32(entry[8:]))
+		origLength := int(be.Uint32(entry[12:]))
+		if tableOffset < 0 || compLength < 0 || tableOffset > len(data)-compLength {
+			return nil, fmt.Errorf("invalid WOFF table %d", i)
+		}
+		if origLength < compLength || len(out)+origLength > maxFontSize {
+			return nil, fmt.Errorf("invalid WOFF table %d length", i)
+		}
+		table := data[tableOffset : tableOffset+compLength]
+		if compLength < origLength {
+			var err error
+			if table, err = inflate(table, origLength); err != nil {
+				return nil, fmt.Errorf("WOFF table %d: %w", i, err)
+			}
+		}
+		record := out[sfntHeaderSize+i*sfntTableRecordSize:]
+		copy(record, entry[:4])
+		be.PutUint32(record[4:], be.Uint32(entry[16:]))
+		be.PutUint32(record[8:], uint32(len(out)))
+		be.PutUint32(record[12:], uint32(origLength))
+		out = append(out, table...)
+		// Tables start on 4-byte boundaries.
+		for len(out)%4 != 0 {
+			out = append(out, 0)
+		}
+	}
+	return out, nil
+}
+
+// inflate decompresses a zlib stream of exactly size bytes.
+func inflate(src []byte, size int) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = zr.Close() }()
+	out, err := io.ReadAll(io.LimitReader(zr, int64(size)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(out) != size {
+		return nil, errors.New("unexpected decompressed length")
+	}
+	return out, nil
+}
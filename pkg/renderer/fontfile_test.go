@@ -0,0 +1,208 @@
+package renderer_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/rhajizada/signum/pkg/renderer"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+)
+
+// fontTable is one table of an SFNT font.
+type fontTable struct {
+	tag, checksum uint32
+	data          []byte
+}
+
+func fontTables(t *testing.T, data []byte) []fontTable {
+	t.Helper()
+	be := binary.BigEndian
+	tables := make([]fontTable, be.Uint16(data[4:]))
+	for i := range tables {
+		record := data[12+16*i:]
+		offset, length := be.Uint32(record[8:]), be.Uint32(record[12:])
+		if int(offset+length) > len(data) {
+			t.Fatalf("table %d out of range", i)
+		}
+		tables[i] = fontTable{tag: be.Uint32(record), checksum: be.Uint32(record[4:]), data: data[offset : offset+length]}
+	}
+	return tables
+}
+
+// appendFont appends a font of tables whose data starts at base in the file.
+func appendFont(dst []byte, tables []fontTable, base int) []byte {
+	be := binary.BigEndian
+	dst = be.AppendUint32(dst, 0x00010000)
+	dst = be.AppendUint16(dst, uint16(len(tables)))
+	dst = append(dst, make([]byte, 6)...)
+	offset := base + 12 + 16*len(tables)
+	for _, table := range tables {
+		dst = be.AppendUint32(dst, table.tag)
+		dst = be.AppendUint32(dst, table.checksum)
+		dst = be.AppendUint32(dst, uint32(offset))
+		dst = be.AppendUint32(dst, uint32(len(table.data)))
+		offset += (len(table.data) + 3) &^ 3
+	}
+	for _, table := range tables {
+		dst = append(dst, table.data...)
+		dst = append(dst, make([]byte, (4-len(table.data)%4)%4)...)
+	}
+	return dst
+}
+
+// buildCollection packs fonts into a TTC file.
+func buildCollection(t *testing.T, fonts ...[]byte) []byte {
+	t.Helper()
+	be := binary.BigEndian
+	out := []byte("ttcf")
+	out = be.AppendUint32(out, 0x00010000)
+	out = be.AppendUint32(out, uint32(len(fonts)))
+	header := len(out) + 4*len(fonts)
+	var body []byte
+	for _, f := range fonts {
+		out = be.AppendUint32(out, uint32(header+len(body)))
+		body = appendFont(body, fontTables(t, f), header+len(body))
+	}
+	return append(out, body...)
+}
+
+// buildWOFF wraps a font in a WOFF file with compressed tables.
+func buildWOFF(t *testing.T, data []byte) []byte {
+	t.Helper()
+	be := binary.BigEndian
+	tables := fontTables(t, data)
+	compressed := make([][]byte, len(tables))
+	for i, table := range tables {
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		if _, err := zw.Write(table.data); err != nil {
+			t.Fatalf("compress: %v", err)
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatalf("compress: %v", err)
+		}
+		compressed[i] = table.data
+		if buf.Len() < len(table.data) {
+			compressed[i] = buf.Bytes()
+		}
+	}
+	out := []byte("wOFF")
+	out = be.AppendUint32(out, 0x00010000)
+	out = append(out, make([]byte, 36)...)
+	be.PutUint16(out[12:], uint16(len(tables)))
+	offset := len(out) + 20*len(tables)
+	for i, table := range tables {
+		out = be.AppendUint32(out, table.tag)
+		out = be.AppendUint32(out, uint32(offset))
+		out = be.AppendUint32(out, uint32(len(compressed[i])))
+		out = be.AppendUint32(out, uint32(len(table.data)))
+		out = be.AppendUint32(out, table.checksum)
+		offset += (len(compressed[i]) + 3) &^ 3
+	}
+	for _, table := range compressed {
+		out = append(out, table...)
+		out = append(out, make([]byte, (4-len(table)%4)%4)...)
+	}
+	be.PutUint32(out[8:], uint32(len(out)))
+	return out
+}
+
+func TestNewRendererFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"fonts/go.ttf":  {Data: goregular.TTF},
+		"fonts/go.ttc":  {Data: buildCollection(t, goregular.TTF, gobold.TTF)},
+		"fonts/go.woff": {Data: buildWOFF(t, goregular.TTF)},
+		"fonts/go.wof2": {Data: []byte("wOF2" + strings.Repeat("\x00", 44))},
+	}
+	badge := renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen}
+	render := func(name string) string {
+		t.Helper()
+		r, err := renderer.NewRendererFromFS(fsys, name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		svg, err := r.Render(badge)
+		if err != nil {
+			t.Fatalf("%s: render: %v", name, err)
+		}
+		return string(svg)
+	}
+	regular := render("fonts/go.ttf")
+	if !strings.Contains(regular, `font-family="Go,`) {
+		t.Fatalf("expected the font family in output: %s", regular)
+	}
+	for _, name := range []string{"fonts/go.ttc", "fonts/go.ttc#0", "fonts/go.woff"} {
+		if got := render(name); got != regular {
+			t.Fatalf("%s: expected the same badge as the TTF\n got: %s\nwant: %s", name, got, regular)
+		}
+	}
+	if bold := render("fonts/go.ttc#1"); badgeWidth(t, bold) <= badgeWidth(t, regular) {
+		t.Fatalf("expected the second face to be the wider bold face: %s", bold)
+	}
+	for _, name := range []string{"fonts/go.ttc#2", "fonts/go.ttf#1", "fonts/go.wof2", "fonts/missing.ttf"} {
+		if _, err := renderer.NewRendererFromFS(fsys, name); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestSplitFontIndex(t *testing.T) {
+	cases := []struct {
+		name, file string
+		index      int
+	}{
+		{"fonts.ttc", "fonts.ttc", 0},
+		{"fonts.ttc#2", "fonts.ttc", 2},
+		{"dir#1/fonts.ttc#10", "dir#1/fonts.ttc", 10},
+		{"fonts#bold.ttf", "fonts#bold.ttf", 0},
+		{"fonts.ttc#", "fonts.ttc#", 0},
+		{"fonts.ttc#-1", "fonts.ttc#-1", 0},
+		{"fonts.ttc#+1", "fonts.ttc#+1", 0},
+	}
+	for _, tc := range cases {
+		file, index := renderer.SplitFontIndex(tc.name)
+		if file != tc.file || index != tc.index {
+			t.Fatalf("%s: expected %q %d, got %q %d", tc.name, tc.file, tc.index, file, index)
+		}
+	}
+}
+
+func TestMeasureText(t *testing.T) {
+	r, err := renderer.NewRendererFromFS(fstest.MapFS{"go.ttf": {Data: goregular.TTF}}, "go.ttf")
+	if err != nil {
+		t.Fatalf("new renderer: %v", err)
+	}
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatalf("parse font: %v", err)
+	}
+	face, err := opentype.NewFace(regular, &opentype.FaceOptions{Size: 22, DPI: 72})
+	if err != nil {
+		t.Fatalf("new face: %v", err)
+	}
+	// Widths keep the fractions of unhinted advances.
+	want := float64(font.MeasureString(face, "passing")) / 64
+	if got := r.MeasureText("passing", 22); math.Abs(got-want) > 1e-9 || got == math.Round(got) {
+		t.Fatalf("expected width %g, got %g", want, got)
+	}
+	svg, err := r.Render(renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	segment := math.Ceil(r.MeasureText("passing", 11)) + 13
+	if !strings.Contains(string(svg), fmt.Sprintf(`width="%g" height="20" fill="#97ca00"`, segment)) {
+		t.Fatalf("expected the status segment to be %g wide: %s", segment, svg)
+	}
+	if got := r.MeasureText("passing", 0); got != 0 {
+		t.Fatalf("expected no width at size 0, got %g", got)
+	}
+}
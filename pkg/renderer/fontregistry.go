@@ -0,0 +1,265 @@
+package renderer
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font/sfnt"
+)
+
+// FontWeight selects the weight of badge text.
+type FontWeight string
+
+const (
+	// FontWeightRegular draws text with the regular face of a font.
+	FontWeightRegular FontWeight = "regular"
+	// FontWeightBold draws text with the bold face of a font, or emboldens
+	// the regular face when the font has no bold one.
+	FontWeightBold FontWeight = "bold"
+)
+
+// FontWeights returns every supported font weight.
+func FontWeights() []FontWeight {
+	return []FontWeight{FontWeightRegular, FontWeightBold}
+}
+
+// IsValid reports whether the weight is supported.
+// Empty string is treated as valid and means FontWeightRegular.
+func (w FontWeight) IsValid() bool {
+	return w == "" || slices.Contains(FontWeights(), w)
+}
+
+const (
+	// MinFontSize and MaxFontSize bound Badge.FontSize, so text stays
+	// readable and fits the badge height.
+	MinFontSize = 6
+	MaxFontSize = 16
+)
+
+// ValidFontSize reports whether size is an accepted Badge.FontSize. Zero
+// means the default 11px.
+func ValidFontSize(size float64) bool {
+	return size == 0 || size >= MinFontSize && size <= MaxFontSize
+}
+
+// ErrNoFontFace is returned by RegisterFont for fonts without a regular or
+// bold face, such as Light, Medium or italic-only files.
+var ErrNoFontFace = errors.New("font has no named regular or bold face")
+
+// fontExts are the file extensions of fonts loaded by LoadFontDir.
+var fontExts = []string{".ttf", ".otf", ".ttc", ".otc", ".woff"}
+
+// fontRegistry holds the named faces of RegisterFont. It is shared by a
+// renderer and the renderers of its faces.
+type fontRegistry struct {
+	mutex sync.RWMutex
+	faces map[fontKey]*namedFace
+}
+
+// fontKey identifies a face by its lowercased family name and weight.
+type fontKey struct {
+	name   string
+	weight FontWeight
+}
+
+type namedFace struct {
+	family string
+	weight FontWeight
+	font   *sfnt.Font
+	// renderer draws badges with the face; it is created on first use.
+	renderer *Renderer
+}
+
+func newFontRegistry() *fontRegistry {
+	return &fontRegistry{faces: map[fontKey]*namedFace{}}
+}
+
+// RegisterFont makes the regular and bold faces of a font file available to
+// Badge.Font under their family name, matched case-insensitively. The file
+// may be a font, a collection or a WOFF file, as for NewRendererWithFontPaths.
+// Italic and other faces are skipped, and a face replaces one registered
+// earlier with the same name and weight.
+func (r *Renderer) RegisterFont(data []byte) error {
+	if r == nil {
+		return errors.New("renderer is nil")
+	}
+	collection, err := parseCollection(data)
+	if err != nil {
+		return err
+	}
+	var faces []*namedFace
+	for i := range collection.NumFonts() {
+		f, fontErr := collection.Font(i)
+		if fontErr != nil {
+			return fontErr
+		}
+		family, _ := f.Name(nil, sfnt.NameIDFamily)
+		subfamily, _ := f.Name(nil, sfnt.NameIDSubfamily)
+		weight, ok := subfamilyWeight(subfamily)
+		if family = fontFamilyName(family); family == "" || !ok {
+			continue
+		}
+		faces = append(faces, &namedFace{family: family, weight: weight, font: f})
+	}
+	if len(faces) == 0 {
+		return ErrNoFontFace
+	}
+	r.fonts.mutex.Lock()
+	defer r.fonts.mutex.Unlock()
+	for _, face := range faces {
+		r.fonts.faces[fontKey{name: strings.ToLower(face.family), weight: face.weight}] = face
+	}
+	r.cache.purge()
+	return nil
+}
+
+// subfamilyWeight returns the weight of a face from its subfamily name,
+// which is Regular, Bold, Italic or Bold Italic for most fonts.
+func subfamilyWeight(subfamily string) (FontWeight, bool) {
+	switch strings.ToLower(strings.TrimSpace(subfamily)) {
+	case "", "regular", "normal", "book", "roman":
+		return FontWeightRegular, true
+	case "bold":
+		return FontWeightBold, true
+	default:
+		return "", false
+	}
+}
+
+// LoadFontDir registers every .ttf, .otf, .ttc, .otc and .woff file in dir,
+// in name order. Files without a regular or bold face are skipped with a
+// warning on the default slog logger; it fails when no file registers.
+func (r *Renderer) LoadFontDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	loaded := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !slices.Contains(fontExts, strings.ToLower(filepath.Ext(entry.Name()))) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, readErr := os.ReadFile(path)
+		if readErr != nil {
+			return readErr
+		}
+		if err = r.RegisterFont(data); errors.Is(err, ErrNoFontFace) {
+			slog.Warn("skipping font", "path", path, "error", err)
+			continue
+		} else if err != nil {
+			return fmt.Errorf("load %s: %w", path, err)
+		}
+		loaded++
+	}
+	if loaded == 0 {
+		return fmt.Errorf("no fonts in %s", dir)
+	}
+	return nil
+}
+
+// HasFont reports whether the named font is registered with a face to draw
+// weight, as Badge.Font and Badge.FontWeight.
+func (r *Renderer) HasFont(name string, weight FontWeight) bool {
+	_, ok := r.namedFace(name, weight)
+	return ok
+}
+
+// Fonts returns the family names of the registered fonts in name order.
+func (r *Renderer) Fonts() []string {
+	r.fonts.mutex.RLock()
+	defer r.fonts.mutex.RUnlock()
+	var names []string
+	for _, face := range r.fonts.faces {
+		if !slices.Contains(names, face.family) {
+			names = append(names, face.family)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// namedFace returns the face of the named font for weight. Bold text falls
+// back to the regular face, which is then emboldened.
+func (r *Renderer) namedFace(name string, weight FontWeight) (*namedFace, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.fonts.mutex.RLock()
+	defer r.fonts.mutex.RUnlock()
+	name = strings.ToLower(name)
+	if face, ok := r.fonts.faces[fontKey{name: name, weight: weight}]; ok {
+		return face, true
+	}
+	face, ok := r.fonts.faces[fontKey{name: name, weight: FontWeightRegular}]
+	return face, ok
+}
+
+// fontRenderer returns the renderer that draws b: r itself, or the renderer
+// of the registered face b.Font names.
+func (r *Renderer) fontRenderer(b Badge) (*Renderer, error) {
+	if b.Font == "" || r.face != nil {
+		return r, nil
+	}
+	face, ok := r.namedFace(b.Font, b.FontWeight)
+	if !ok {
+		return nil, fmt.Errorf("unknown font: %q", b.Font)
+	}
+	r.fonts.mutex.Lock()
+	defer r.fonts.mutex.Unlock()
+	if face.renderer == nil {
+		face.renderer = r.faceRenderer(face)
+	}
+	return face.renderer, nil
+}
+
+// faceRenderer returns a renderer that shares the styles, cache and
+// settings of r but measures and draws text with face, falling back to the
+// fonts of r that can be resized. Text sizes are cached by its measurer.
+func (r *Renderer) faceRenderer(face *namedFace) *Renderer {
+	faces := []fontFace{newSFNTFace(face.font, fontsize, dpi)}
+	if text, ok := r.text.(*fontMeasurer); ok {
+		for _, fallback := range text.base.faces {
+			if fallback.resize != nil {
+				faces = append(faces, fallback.resize(fontsize))
+			}
+		}
+	}
+	fr := *r
+	fr.text = newFontMeasurer(faces)
+	fr.families = slices.Concat([]string{face.family}, r.families)
+	fr.face = face
+	return &fr
+}
+
+// applyFont adjusts the metrics m of b to its font size and weight.
+func (r *Renderer) applyFont(b Badge, m *styleMetrics) {
+	if b.FontSize != 0 {
+		m.fontScale *= b.FontSize / fontsize
+	}
+	if b.FontWeight == FontWeightBold && (r.face == nil || r.face.weight != FontWeightBold) {
+		m.boldSubject, m.boldStatus = true, true
+	}
+}
+
+// fontSize returns the text size the style with the metrics m draws at, in
+// hundredths of a pixel.
+func fontSize(m styleMetrics) float64 {
+	return math.Round(fontsize*m.fontScale*100) / 100
+}
+
+// fontWeightAttr returns the font-weight of text drawn at weight, empty for
+// the regular weight.
+func fontWeightAttr(weight FontWeight) string {
+	if weight == FontWeightBold {
+		return "bold"
+	}
+	return ""
+}
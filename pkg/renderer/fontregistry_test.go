@@ -0,0 +1,152 @@
+package renderer_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rhajizada/signum/pkg/renderer"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestRegisterFont(t *testing.T) {
+	r := newRenderer(t)
+	for _, data := range [][]byte{goregular.TTF, gobold.TTF, gomono.TTF} {
+		if err := r.RegisterFont(data); err != nil {
+			t.Fatalf("register font: %v", err)
+		}
+	}
+	if err := r.RegisterFont([]byte("not a font")); err == nil {
+		t.Fatalf("expected error for invalid font data")
+	}
+	if got, want := r.Fonts(), []string{"Go", "Go Mono"}; !slices.Equal(got, want) {
+		t.Fatalf("expected fonts %v, got %v", want, got)
+	}
+	if !r.HasFont("go mono", "") || !r.HasFont("Go", renderer.FontWeightBold) || r.HasFont("Arial", "") {
+		t.Fatalf("unexpected font lookup results")
+	}
+
+	render := func(b renderer.Badge) string {
+		t.Helper()
+		b.Subject, b.Status, b.Color = "release", "v1.2.3", renderer.ColorBlue
+		svg, err := r.Render(b)
+		if err != nil {
+			t.Fatalf("render %+v: %v", b, err)
+		}
+		return string(svg)
+	}
+	plain := render(renderer.Badge{})
+	regular := render(renderer.Badge{Font: "GO"})
+	if !strings.Contains(regular, `font-family="Go,DejaVu Sans`) || !strings.Contains(regular, `font-size="11">`) {
+		t.Fatalf("expected the registered font in output: %s", regular)
+	}
+	if badgeWidth(t, regular) == badgeWidth(t, plain) {
+		t.Fatalf("expected the registered font to measure the text")
+	}
+	bold := render(renderer.Badge{Font: "Go", FontWeight: renderer.FontWeightBold})
+	if !strings.Contains(bold, `font-weight="bold"`) || badgeWidth(t, bold) <= badgeWidth(t, regular) {
+		t.Fatalf("expected the bold face to draw wider bold text: %s", bold)
+	}
+	mono := render(renderer.Badge{Font: "Go Mono", FontSize: 14})
+	if !strings.Contains(mono, `font-family="Go Mono,`) || !strings.Contains(mono, `font-size="14"`) {
+		t.Fatalf("expected 14px Go Mono text: %s", mono)
+	}
+	if again := render(renderer.Badge{Font: "Go Mono", FontSize: 14}); again != mono {
+		t.Fatalf("expected the same output from the cached face renderer")
+	}
+
+	for _, b := range []renderer.Badge{
+		{Subject: "a", Status: "b", Font: "Arial"},
+		{Subject: "a", Status: "b", FontWeight: "light"},
+		{Subject: "a", Status: "b", FontSize: 40},
+	} {
+		if _, err := r.Render(b); err == nil {
+			t.Fatalf("expected error for %+v", b)
+		}
+		if _, err := r.RenderPNG(b, 1); err == nil {
+			t.Fatalf("expected png error for %+v", b)
+		}
+	}
+}
+
+func TestRenderFontSizeAndWeight(t *testing.T) {
+	r := newRenderer(t)
+	badge := renderer.Badge{Subject: "commit", Status: "abc1234", Color: renderer.ColorGreen}
+	plain, err := r.Render(badge)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	badge.FontSize, badge.FontWeight = 13, renderer.FontWeightBold
+	styled, err := r.Render(badge)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(string(styled), `font-size="13" font-weight="bold"`) {
+		t.Fatalf("expected the font size and weight in output: %s", styled)
+	}
+	if badgeWidth(t, string(styled)) <= badgeWidth(t, string(plain)) {
+		t.Fatalf("expected larger bold text to widen the badge")
+	}
+	badge.Style = renderer.StyleForTheBadge
+	badge.FontSize = 0
+	forTheBadge, err := r.Render(badge)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(string(forTheBadge), `font-size="10" font-weight="bold"`) {
+		t.Fatalf("expected for-the-badge text to keep its size: %s", forTheBadge)
+	}
+}
+
+func TestLoadFontDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"go-regular.ttf": goregular.TTF,
+		"go-bold.TTF":    gobold.TTF,
+		"go-italic.ttf":  goitalic.TTF,
+		"README.md":      []byte("fonts"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	r := newRenderer(t)
+	if err := r.LoadFontDir(dir); err != nil {
+		t.Fatalf("load font dir: %v", err)
+	}
+	if got := r.Fonts(); !slices.Equal(got, []string{"Go"}) {
+		t.Fatalf("expected the Go font, got %v", got)
+	}
+	b := renderer.Badge{Subject: "release", Status: "v2", Font: "go", FontWeight: renderer.FontWeightBold}
+	if _, err := r.RenderPNG(b, 1); err != nil {
+		t.Fatalf("render png: %v", err)
+	}
+
+	if err := r.LoadFontDir(t.TempDir()); err == nil {
+		t.Fatalf("expected error for a directory without fonts")
+	}
+	italic := t.TempDir()
+	if err := os.WriteFile(filepath.Join(italic, "go-italic.ttf"), goitalic.TTF, 0o600); err != nil {
+		t.Fatalf("write font: %v", err)
+	}
+	if err := r.LoadFontDir(italic); err == nil || !strings.Contains(err.Error(), "no fonts") {
+		t.Fatalf("expected error for a directory with only unsupported faces, got %v", err)
+	}
+	if err := r.RegisterFont(goitalic.TTF); !errors.Is(err, renderer.ErrNoFontFace) {
+		t.Fatalf("expected ErrNoFontFace for an italic font, got %v", err)
+	}
+	bad := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bad, "bad.otf"), []byte("font"), 0o600); err != nil {
+		t.Fatalf("write font: %v", err)
+	}
+	if err := r.LoadFontDir(bad); err == nil || !strings.Contains(err.Error(), "bad.otf") {
+		t.Fatalf("expected error naming the invalid font, got %v", err)
+	}
+}
@@ -0,0 +1,262 @@
+package renderer
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// fontFace is one entry of a font fallback chain.
+type fontFace struct {
+	drawer *font.Drawer
+	// has reports whether the face has a real glyph for r, not .notdef.
+	has func(r rune) bool
+	// resize returns the face at another size, or is nil when the face has a fixed size.
+	resize func(size float64) fontFace
+	// outline returns the glyph outline of r, or is nil when the face has no
+	// outlines. Like the drawer, it is not safe for concurrent use.
+	outline func(r rune) glyphOutline
+}
+
+func newFontFace(face font.Face) fontFace {
+	return fontFace{
+		drawer: &font.Drawer{Face: face},
+		has: func(r rune) bool {
+			_, ok := face.GlyphAdvance(r)
+			return ok
+		},
+	}
+}
+
+// sfntFace is an OpenType face with kerning at its own size, since
+// opentype.Face.Kern scales kerning to the units per em instead.
+type sfntFace struct {
+	font.Face
+	f    *sfnt.Font
+	buf  sfnt.Buffer
+	ppem fixed.Int26_6
+}
+
+func (f *sfntFace) Kern(a, b rune) fixed.Int26_6 {
+	x0, _ := f.f.GlyphIndex(&f.buf, a)
+	x1, _ := f.f.GlyphIndex(&f.buf, b)
+	k, err := f.f.Kern(&f.buf, x0, x1, f.ppem, font.HintingNone)
+	if err != nil {
+		return 0
+	}
+	return k
+}
+
+// newSFNTFace returns f at size, unhinted so advances and kerning keep the
+// fractional widths of the font. Glyph coverage is checked against the cmap,
+// so .notdef is never reported as found.
+func newSFNTFace(f *sfnt.Font, size, dpi float64) fontFace {
+	opts := &opentype.FaceOptions{Size: size, DPI: dpi, Hinting: font.HintingNone}
+	// opentype.NewFace never fails.
+	face, _ := opentype.NewFace(f, opts)
+	sf := &sfntFace{Face: face, f: f, ppem: floatFixed(size * dpi / pointsPerInch)}
+	return fontFace{
+		drawer: &font.Drawer{Face: sf},
+		has: func(r rune) bool {
+			x, err := f.GlyphIndex(&sf.buf, r)
+			return err == nil && x != 0
+		},
+		resize: func(size float64) fontFace {
+			return newSFNTFace(f, size, dpi)
+		},
+		outline: func(r rune) glyphOutline {
+			x, err := f.GlyphIndex(&sf.buf, r)
+			if err != nil {
+				return glyphOutline{}
+			}
+			segments, err := f.LoadGlyph(&sf.buf, x, sf.ppem, nil)
+			if err != nil {
+				return glyphOutline{}
+			}
+			return newGlyphOutline(segments)
+		},
+	}
+}
+
+// maxSizedFaces bounds how many font sizes fontMeasurer keeps faces for.
+const maxSizedFaces = 16
+
+// maxCachedGlyphs bounds the advances and kerning pairs cached per chain.
+const maxCachedGlyphs = 1 << 14
+
+// fontMeasurer measures text with a font fallback chain. Bold text is
+// approximated. It is safe for concurrent use.
+type fontMeasurer struct {
+	base *glyphChain
+	// sized caches the chain at sizes other than fontsize, up to maxSizedFaces.
+	sized      sync.Map
+	sizedCount atomic.Int32
+	sizedMutex sync.Mutex
+}
+
+func newFontMeasurer(faces []fontFace) *fontMeasurer {
+	return &fontMeasurer{base: newGlyphChain(faces)}
+}
+
+func (m *fontMeasurer) measure(s string, bold bool, size float64) float64 {
+	chain, ratio := m.chainAt(size)
+	width := fixedFloat(chain.advance(s)) * ratio
+	if bold {
+		width *= boldWidthFactor
+	}
+	return width
+}
+
+// chainAt returns the chain at size, together with the factor to apply to
+// its widths. Faces that cannot be resized, and sizes past maxSizedFaces,
+// are measured at fontsize and scaled linearly.
+func (m *fontMeasurer) chainAt(size float64) (*glyphChain, float64) {
+	if size == fontsize || m.base.faces[0].resize == nil {
+		return m.base, size / fontsize
+	}
+	if chain, ok := m.sized.Load(size); ok {
+		return chain.(*glyphChain), 1
+	}
+	m.sizedMutex.Lock()
+	defer m.sizedMutex.Unlock()
+	if chain, ok := m.sized.Load(size); ok {
+		return chain.(*glyphChain), 1
+	}
+	if m.sizedCount.Load() >= maxSizedFaces {
+		return m.base, size / fontsize
+	}
+	faces := make([]fontFace, 0, len(m.base.faces))
+	for _, face := range m.base.faces {
+		faces = append(faces, face.resize(size))
+	}
+	chain := newGlyphChain(faces)
+	m.sized.Store(size, chain)
+	m.sizedCount.Add(1)
+	return chain, 1
+}
+
+// glyphChain is a fallback chain at one size. Faces keep internal caches and
+// are not safe for concurrent use, so advances and kerning are cached in
+// concurrent maps and the faces are only used under mutex on a miss.
+type glyphChain struct {
+	faces []fontFace
+	mutex sync.Mutex
+	// glyphs maps a rune to its glyphMetrics; kerns maps a [2]rune pair to
+	// its fixed.Int26_6 adjustment; outlines maps a rune to its glyphOutline.
+	glyphs   sync.Map
+	kerns    sync.Map
+	outlines sync.Map
+	cached   atomic.Int32
+}
+
+type glyphMetrics struct {
+	// face is the index of the first face with a glyph for the rune.
+	face    int
+	advance fixed.Int26_6
+}
+
+func newGlyphChain(faces []fontFace) *glyphChain {
+	return &glyphChain{faces: faces}
+}
+
+// advance sums the advances of s. Kerning applies between runes drawn with
+// the same face, as when each run is measured with MeasureString.
+func (c *glyphChain) advance(s string) fixed.Int26_6 {
+	var total fixed.Int26_6
+	prev, prevFace := rune(-1), -1
+	for _, r := range s {
+		g := c.glyph(r)
+		if g.face == prevFace {
+			total += c.kern(g.face, prev, r)
+		}
+		total += g.advance
+		prev, prevFace = r, g.face
+	}
+	return total
+}
+
+func (c *glyphChain) glyph(r rune) glyphMetrics {
+	if g, ok := c.glyphs.Load(r); ok {
+		return g.(glyphMetrics)
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	i := faceFor(c.faces, r)
+	advance, _ := c.faces[i].drawer.Face.GlyphAdvance(r)
+	g := glyphMetrics{face: i, advance: advance}
+	c.store(&c.glyphs, r, g)
+	return g
+}
+
+func (c *glyphChain) kern(face int, a, b rune) fixed.Int26_6 {
+	key := [2]rune{a, b}
+	if k, ok := c.kerns.Load(key); ok {
+		return k.(fixed.Int26_6)
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	k := c.faces[face].drawer.Face.Kern(a, b)
+	c.store(&c.kerns, key, k)
+	return k
+}
+
+// store caches value until the chain holds maxCachedGlyphs entries.
+func (c *glyphChain) store(m *sync.Map, key, value any) {
+	if c.cached.Load() >= maxCachedGlyphs {
+		return
+	}
+	if _, loaded := m.LoadOrStore(key, value); !loaded {
+		c.cached.Add(1)
+	}
+}
+
+// faceFor returns the index of the first face with a glyph for r, or the
+// primary face when none has one.
+func faceFor(faces []fontFace, r rune) int {
+	for i, face := range faces {
+		if face.has(r) {
+			return i
+		}
+	}
+	return 0
+}
+
+// fontFamilyName strips characters that would break a CSS font-family list.
+func fontFamilyName(name string) string {
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		switch r {
+		case ',', ';', '"', '\'', '<', '>':
+			return -1
+		default:
+			return r
+		}
+	}, name))
+}
+
+// fontFamily lists the renderer's font families in fallback order, followed by
+// the style defaults.
+func (r *Renderer) fontFamily(m styleMetrics) string {
+	if len(r.families) == 0 {
+		return m.fontFamily
+	}
+	defaults := strings.Split(m.fontFamily, ",")
+	families := make([]string, 0, len(r.families)+len(defaults))
+	for _, family := range slices.Concat(r.families, defaults) {
+		if !containsFold(families, family) {
+			families = append(families, family)
+		}
+	}
+	return strings.Join(families, ",")
+}
+
+func containsFold(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(v, value)
+	})
+}
@@ -0,0 +1,32 @@
+package renderer
+
+import "slices"
+
+// Format is an output image format.
+type Format string
+
+const (
+	// FormatSVG is the SVG markup returned by Render.
+	FormatSVG Format = "svg"
+	// FormatPNG is the raster image returned by RenderPNG.
+	FormatPNG Format = "png"
+)
+
+// Formats returns every supported output format.
+func Formats() []Format {
+	return []Format{FormatSVG, FormatPNG}
+}
+
+// IsValid reports whether the format is supported.
+// Empty string is treated as valid and defaults to FormatSVG.
+func (f Format) IsValid() bool {
+	return f == "" || slices.Contains(Formats(), f)
+}
+
+// ContentType returns the media type of the format.
+func (f Format) ContentType() string {
+	if f == FormatPNG {
+		return "image/png"
+	}
+	return "image/svg+xml; charset=utf-8"
+}
@@ -0,0 +1,73 @@
+package renderer
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// Kind selects what the status segment of a badge shows.
+type Kind string
+
+const (
+	// KindStatus draws the status text on a solid fill.
+	KindStatus Kind = "status"
+	// KindProgress draws the status text over a bar filled in proportion to
+	// Badge.Progress.
+	KindProgress Kind = "progress"
+)
+
+// MaxProgress is the value of a full progress bar.
+const MaxProgress = 100
+
+// progressTrackColor fills the empty part of a progress bar, as in the templates.
+const progressTrackColor = Color("#9f9f9f")
+
+// Kinds returns every supported badge kind.
+func Kinds() []Kind {
+	return []Kind{KindStatus, KindProgress}
+}
+
+// IsValid reports whether the kind is supported.
+// Empty string is treated as valid and defaults to KindStatus.
+func (k Kind) IsValid() bool {
+	return k == "" || slices.Contains(Kinds(), k)
+}
+
+// ProgressStyles returns the styles that can draw KindProgress badges.
+func ProgressStyles() []Style {
+	return []Style{StyleFlat, StyleFlatSquare, StylePlastic}
+}
+
+// ValidProgress reports whether v is a progress value from 0 to MaxProgress.
+func ValidProgress(v float64) bool {
+	return v >= 0 && v <= MaxProgress
+}
+
+// progressStatus is the status text of a progress badge without one.
+func progressStatus(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64) + "%"
+}
+
+// progressFill returns the x and width of the filled part of the status
+// segment. Mirrored badges fill from the subject side, right to left.
+func progressFill(b bounds, progress float64) (float64, float64) {
+	dx := b.StatusDx * progress / MaxProgress
+	if b.Mirrored {
+		return b.StatusStart() + b.StatusDx - dx, dx
+	}
+	return b.StatusStart(), dx
+}
+
+// progressTemplate returns the progress template for style.
+func (r *Renderer) progressTemplate(style Style, progress float64) (*styleTemplate, error) {
+	if math.IsNaN(progress) || !ValidProgress(progress) {
+		return nil, fmt.Errorf("invalid progress: %v", progress)
+	}
+	tmpl, ok := r.progress[style]
+	if !ok {
+		return nil, fmt.Errorf("progress badges are not supported for style: %q", style)
+	}
+	return tmpl, nil
+}
@@ -0,0 +1,110 @@
+package renderer_test
+
+import (
+	"image/color"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rhajizada/signum/pkg/renderer"
+)
+
+var progressRects = regexp.MustCompile(
+	`<rect x="([0-9.]+)" width="([0-9.]+)" height="20" fill="#9f9f9f"/><rect x="([0-9.]+)" width="([0-9.]+)" height="20" fill="[^"]+"/>`)
+
+func progressBar(t *testing.T, svg []byte) [4]float64 {
+	t.Helper()
+	match := progressRects.FindSubmatch(svg)
+	if match == nil {
+		t.Fatalf("expected track and fill rects: %s", svg)
+	}
+	var values [4]float64
+	for i := range values {
+		v, err := strconv.ParseFloat(string(match[i+1]), 64)
+		if err != nil {
+			t.Fatalf("parse %q: %v", match[i+1], err)
+		}
+		values[i] = v
+	}
+	return values
+}
+
+func TestRenderProgress(t *testing.T) {
+	r := newRenderer(t)
+	for _, style := range renderer.ProgressStyles() {
+		svg, err := r.Render(renderer.Badge{
+			Subject: "coverage", Color: renderer.ColorGreen, Style: style,
+			Kind: renderer.KindProgress, Progress: 25,
+		})
+		if err != nil {
+			t.Fatalf("render %s: %v", style, err)
+		}
+		if !strings.Contains(string(svg), ">25%</text>") {
+			t.Fatalf("%s: expected the default status text: %s", style, svg)
+		}
+		bar := progressBar(t, svg)
+		trackX, trackDx, fillX, fillDx := bar[0], bar[1], bar[2], bar[3]
+		if fillX != trackX || math.Abs(fillDx-trackDx/4) > 1e-9 {
+			t.Fatalf("%s: expected a quarter fill, got track %v+%v fill %v+%v", style, trackX, trackDx, fillX, fillDx)
+		}
+	}
+}
+
+func TestRenderProgressMirrored(t *testing.T) {
+	r := newRenderer(t)
+	svg, err := r.Render(renderer.Badge{
+		Subject: "שלום", Status: "עולם", Color: renderer.ColorGreen,
+		Kind: renderer.KindProgress, Progress: 50,
+	})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	bar := progressBar(t, svg)
+	if bar[0] != 0 || bar[2]+bar[3] != bar[1] {
+		t.Fatalf("expected the fill to end at the subject side, got %v", bar)
+	}
+}
+
+func TestRenderProgressInvalid(t *testing.T) {
+	r := newRenderer(t)
+	for _, b := range []renderer.Badge{
+		{Subject: "a", Kind: renderer.KindProgress, Progress: 101},
+		{Subject: "a", Kind: renderer.KindProgress, Progress: -1},
+		{Subject: "a", Kind: renderer.KindProgress, Progress: math.NaN()},
+		{Subject: "a", Kind: renderer.KindProgress, Style: renderer.StyleForTheBadge},
+		{Subject: "a", Kind: renderer.KindProgress, Style: renderer.StyleSocial},
+		{Subject: "a", Status: "b", Kind: "gauge"},
+	} {
+		if _, err := r.Render(b); err == nil {
+			t.Fatalf("%+v: expected error", b)
+		}
+	}
+}
+
+func TestRenderPNGProgress(t *testing.T) {
+	r := newRenderer(t)
+	b := renderer.Badge{
+		Subject: "coverage", Status: "50%", Color: "#0000ff", Style: renderer.StyleFlatSquare,
+		Kind: renderer.KindProgress, Progress: 50,
+	}
+	svg, err := r.Render(b)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	bar := progressBar(t, svg)
+	data, err := r.RenderPNG(b, 1)
+	if err != nil {
+		t.Fatalf("render png: %v", err)
+	}
+	img := decodePNG(t, data)
+	fill := color.NRGBAModel.Convert(img.At(int(bar[2])+1, 1)).(color.NRGBA)
+	track := color.NRGBAModel.Convert(img.At(int(bar[0]+bar[1])-2, 1)).(color.NRGBA)
+	if fill.B < 0xc0 || fill.R > 0x40 {
+		t.Fatalf("expected the blue fill at the start of the bar, got %v", fill)
+	}
+	if track.R < 0x80 || track.R != track.B {
+		t.Fatalf("expected the grey track at the end of the bar, got %v", track)
+	}
+}
@@ -0,0 +1,24 @@
+package renderer
+
+import (
+	"net/url"
+	"slices"
+)
+
+// LinkSchemes returns the URL schemes accepted in Badge.Links.
+func LinkSchemes() []string {
+	return []string{"http", "https", "mailto"}
+}
+
+// ValidLink reports whether link is an absolute URL with an allowed scheme.
+// Empty string is treated as valid and leaves the segment unlinked.
+func ValidLink(link string) bool {
+	if link == "" {
+		return true
+	}
+	u, err := url.Parse(link)
+	if err != nil || !slices.Contains(LinkSchemes(), u.Scheme) {
+		return false
+	}
+	return u.Host != "" || u.Opaque != ""
+}
@@ -0,0 +1,145 @@
+package renderer
+
+import (
+	"bytes"
+	"embed"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Icons are adapted from Material Design Icons (Apache License 2.0), except
+// the go mark, drawn from the Go Bold Italic font (BSD license), and a
+// simplified docker whale.
+//
+//go:embed logos/*.svg
+var logoFS embed.FS
+
+// Logo is either the name of an embedded icon or a data:image/svg+xml;base64 URI.
+type Logo string
+
+// MaxLogoWidth is the largest accepted Badge.LogoWidth.
+const MaxLogoWidth = 64
+
+// MaxLogoBytes is the largest accepted SVG in a logo data URI, decoded.
+const MaxLogoBytes = 16 << 10
+
+const (
+	logoDataURIPrefix = "data:image/svg+xml;base64,"
+	logoDefaultWidth  = 14
+	logoPadding       = 3
+	logoInset         = 5
+	logoDefaultColor  = "#fff"
+)
+
+// LogoNames returns the names of the embedded icons.
+func LogoNames() []string {
+	entries, err := fs.ReadDir(logoFS, "logos")
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".svg"))
+	}
+	slices.Sort(names)
+	return names
+}
+
+// IsValid reports whether the logo is an embedded icon name or a base64 SVG data URI.
+// Empty string is treated as valid and renders no logo.
+func (l Logo) IsValid() bool {
+	return l.Validate() == nil
+}
+
+// Validate returns why the logo is not valid, or nil when it is. Data URIs
+// must hold a well-formed SVG document of at most MaxLogoBytes.
+func (l Logo) Validate() error {
+	if l == "" {
+		return nil
+	}
+	if l.isDataURI() {
+		payload := strings.TrimPrefix(string(l), logoDataURIPrefix)
+		if base64.StdEncoding.DecodedLen(len(payload)) > MaxLogoBytes+2 {
+			return fmt.Errorf("logo data URI exceeds %d bytes", MaxLogoBytes)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			return errors.New("logo data URI is not valid base64")
+		}
+		if len(decoded) > MaxLogoBytes {
+			return fmt.Errorf("logo data URI exceeds %d bytes", MaxLogoBytes)
+		}
+		if !isSVG(decoded) {
+			return errors.New("logo data URI is not an SVG document")
+		}
+		return nil
+	}
+	if _, ok := embeddedLogo(string(l)); !ok {
+		return fmt.Errorf("unknown logo %q (available: %s)", string(l), strings.Join(LogoNames(), ", "))
+	}
+	return nil
+}
+
+// isSVG reports whether data is a well-formed XML document with an svg root.
+func isSVG(data []byte) bool {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	root := false
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return root
+		}
+		if err != nil {
+			return false
+		}
+		if start, ok := tok.(xml.StartElement); ok && !root {
+			if start.Name.Local != "svg" {
+				return false
+			}
+			root = true
+		}
+	}
+}
+
+func (l Logo) isDataURI() bool {
+	return strings.HasPrefix(string(l), logoDataURIPrefix)
+}
+
+// dataURI resolves the logo into a data URI. Embedded icons are tinted with color,
+// user supplied data URIs are passed through untouched.
+func (l Logo) dataURI(color string) (template.URL, bool) {
+	if l.isDataURI() {
+		return template.URL(l), true //nolint:gosec // validated by IsValid before rendering
+	}
+	svg, ok := embeddedLogo(string(l))
+	if !ok {
+		return "", false
+	}
+	paint, opacity := svgPaint(color)
+	attrs := `fill="` + template.HTMLEscapeString(paint) + `" `
+	if opacity != "" {
+		attrs += `fill-opacity="` + opacity + `" `
+	}
+	tinted := strings.Replace(svg, "<svg ", "<svg "+attrs, 1)
+	encoded := base64.StdEncoding.EncodeToString([]byte(tinted))
+	return template.URL(logoDataURIPrefix + encoded), true //nolint:gosec // built from embedded icons
+}
+
+func embeddedLogo(name string) (string, bool) {
+	if name == "" || strings.ContainsAny(name, `/\.`) {
+		return "", false
+	}
+	data, err := logoFS.ReadFile(path.Join("logos", strings.ToLower(name)+".svg"))
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(data)), true
+}
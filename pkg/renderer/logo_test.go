@@ -0,0 +1,67 @@
+package renderer_test
+
+import (
+	"encoding/base64"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rhajizada/signum/pkg/renderer"
+)
+
+func TestLogoNames(t *testing.T) {
+	names := renderer.LogoNames()
+	for _, name := range []string{"check", "x", "warning", "info", "go", "docker"} {
+		if !slices.Contains(names, name) {
+			t.Fatalf("expected embedded logo %q in %v", name, names)
+		}
+	}
+}
+
+func TestLogoIsValid(t *testing.T) {
+	svg := base64.StdEncoding.EncodeToString([]byte(`<svg xmlns="http://www.w3.org/2000/svg"/>`))
+	encode := func(s string) renderer.Logo {
+		return renderer.Logo("data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(s)))
+	}
+	valid := []renderer.Logo{
+		"",
+		"check",
+		"Check",
+		"go",
+		"docker",
+		renderer.Logo("data:image/svg+xml;base64," + svg),
+		encode(`<?xml version="1.0"?><!-- logo --><svg xmlns="http://www.w3.org/2000/svg"><path d="M0 0h1v1z"/></svg>`),
+	}
+	for _, l := range valid {
+		if !l.IsValid() {
+			t.Fatalf("expected %q to be valid", l)
+		}
+	}
+
+	invalid := []renderer.Logo{
+		"not-a-logo",
+		"../logos/check",
+		"data:image/svg+xml;base64,",
+		"data:image/svg+xml;base64,%%%",
+		renderer.Logo("data:image/png;base64," + svg),
+		encode("\x89PNG\r\n\x1a\n"),
+		encode(`<html><svg/></html>`),
+		encode(`<svg><path></svg>`),
+		encode(`<svg>` + strings.Repeat(" ", renderer.MaxLogoBytes) + `</svg>`),
+	}
+	for _, l := range invalid {
+		if l.IsValid() {
+			t.Fatalf("expected %q to be invalid", l)
+		}
+	}
+}
+
+func TestLogoValidateNamesProblem(t *testing.T) {
+	if err := renderer.Logo("kubernetes").Validate(); err == nil || !strings.Contains(err.Error(), "available: ") {
+		t.Fatalf("expected the unknown logo error to list the available logos, got %v", err)
+	}
+	blob := renderer.Logo("data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte("GIF89a")))
+	if err := blob.Validate(); err == nil || !strings.Contains(err.Error(), "not an SVG") {
+		t.Fatalf("expected a non-SVG payload to be rejected, got %v", err)
+	}
+}
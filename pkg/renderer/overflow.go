@@ -0,0 +1,179 @@
+package renderer
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// Overflow selects how text is fitted when a badge is wider than Badge.MaxWidth.
+type Overflow string
+
+const (
+	// OverflowTruncateEnd cuts text at the end and appends an ellipsis.
+	OverflowTruncateEnd Overflow = "truncate-end"
+	// OverflowTruncateMiddle keeps both ends of the text around an ellipsis.
+	OverflowTruncateMiddle Overflow = "truncate-middle"
+	// OverflowShrink keeps the text and compresses it with textLength.
+	OverflowShrink Overflow = "shrink"
+)
+
+const ellipsis = "…"
+
+// minShrinkScale bounds how far OverflowShrink compresses text.
+const minShrinkScale = 0.25
+
+// Overflows returns every supported overflow policy.
+func Overflows() []Overflow {
+	return []Overflow{OverflowTruncateEnd, OverflowTruncateMiddle, OverflowShrink}
+}
+
+// IsValid reports whether the overflow policy is supported.
+// Empty string is treated as valid and defaults to OverflowTruncateEnd.
+func (o Overflow) IsValid() bool {
+	return o == "" || slices.Contains(Overflows(), o)
+}
+
+// segment is the text of one badge segment and its padded width.
+type segment struct {
+	text string
+	bold bool
+	dx   float64
+	// textDx is the compressed text length set by OverflowShrink, zero otherwise.
+	textDx float64
+}
+
+// fitSegments applies the overflow policy so the segments fit in budget. It
+// returns false when they cannot: every segment keeps at least its padding
+// and an ellipsis, or minShrinkScale of its text, and minDx is what they
+// need at the least.
+func (r *Renderer) fitSegments(policy Overflow, budget float64, m styleMetrics, segments ...*segment) (minDx float64, ok bool) {
+	total := 0.0
+	for _, seg := range segments {
+		total += seg.dx
+	}
+	if total <= budget {
+		return total, true
+	}
+	fit := r.truncateSegment
+	if policy == OverflowShrink {
+		fit = shrinkSegment
+	}
+	floors := make(map[*segment]float64, len(segments))
+	for _, seg := range segments {
+		floors[seg] = r.minSegmentDx(policy, m, seg)
+		minDx += floors[seg]
+	}
+	if minDx > budget {
+		return minDx, false
+	}
+	originals := make(map[*segment]segment, len(segments))
+	for _, seg := range segments {
+		originals[seg] = *seg
+	}
+	// Shorten the widest segments first, each to an equal share of what the
+	// narrower ones leave, so narrow segments keep their text when they can.
+	// The floors of the segments still to fit are kept out of the share.
+	order := slices.Clone(segments)
+	slices.SortStableFunc(order, func(a, b *segment) int { return cmp.Compare(b.dx, a.dx) })
+	for i, seg := range order {
+		rest := order[i:]
+		dxs := make([]float64, len(rest))
+		reserved := 0.0
+		for j, s := range rest {
+			dxs[j] = s.dx
+			if j > 0 {
+				reserved += floors[s]
+			}
+		}
+		fit(policy, math.Min(fairShare(dxs, budget), budget-reserved), m, seg)
+		budget -= seg.dx
+	}
+	// Give what the narrower segments left unused back to the shortened ones.
+	for _, seg := range order {
+		if budget <= 0 {
+			break
+		}
+		if *seg == originals[seg] {
+			continue
+		}
+		refit := originals[seg]
+		fit(policy, seg.dx+budget, m, &refit)
+		if refit.dx > seg.dx && refit.dx <= seg.dx+budget {
+			budget -= refit.dx - seg.dx
+			*seg = refit
+		}
+	}
+	return minDx, true
+}
+
+// minSegmentDx is the narrowest the overflow policy draws seg.
+func (r *Renderer) minSegmentDx(policy Overflow, m styleMetrics, seg *segment) float64 {
+	natural := seg.dx - m.padding
+	if policy == OverflowShrink {
+		if natural <= 0 {
+			return seg.dx
+		}
+		return math.Min(seg.dx, math.Ceil(natural*minShrinkScale)+m.padding)
+	}
+	if seg.text == "" {
+		return seg.dx
+	}
+	return math.Min(seg.dx, r.measureText(ellipsis, m, seg.bold))
+}
+
+// fairShare returns the width cap at which dxs, each cut to it, add up to
+// budget, or +Inf when they fit uncut.
+func fairShare(dxs []float64, budget float64) float64 {
+	slices.Sort(dxs)
+	for i, dx := range dxs {
+		if n := float64(len(dxs) - i); dx*n >= budget {
+			return budget / n
+		}
+		budget -= dx
+	}
+	return math.Inf(1)
+}
+
+// truncateSegment keeps the most runes that still fit in target together with
+// an ellipsis. Text that cannot fit at all is reduced to the ellipsis alone,
+// which fitSegments makes room for.
+func (r *Renderer) truncateSegment(policy Overflow, target float64, m styleMetrics, seg *segment) {
+	runes := []rune(seg.text)
+	if seg.dx <= target || len(runes) == 0 {
+		return
+	}
+	lo, hi := 0, len(runes)-1
+	best := truncateRunes(policy, runes, 0)
+	bestDx := r.measureText(best, m, seg.bold)
+	for lo < hi {
+		keep := (lo + hi + 1) / 2
+		candidate := truncateRunes(policy, runes, keep)
+		dx := r.measureText(candidate, m, seg.bold)
+		if dx <= target {
+			lo, best, bestDx = keep, candidate, dx
+		} else {
+			hi = keep - 1
+		}
+	}
+	seg.text, seg.dx = best, bestDx
+}
+
+func truncateRunes(policy Overflow, runes []rune, keep int) string {
+	if policy == OverflowTruncateMiddle {
+		head := (keep + 1) / 2
+		return string(runes[:head]) + ellipsis + string(runes[len(runes)-(keep-head):])
+	}
+	return string(runes[:keep]) + ellipsis
+}
+
+// shrinkSegment compresses the text of seg so it fits in target, down to
+// minShrinkScale of its natural length.
+func shrinkSegment(_ Overflow, target float64, m styleMetrics, seg *segment) {
+	natural := seg.dx - m.padding
+	if seg.dx <= target || natural <= 0 {
+		return
+	}
+	seg.textDx = math.Max(math.Floor(target-m.padding), math.Ceil(natural*minShrinkScale))
+	seg.dx = seg.textDx + m.padding
+}
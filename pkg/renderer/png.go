@@ -0,0 +1,564 @@
+package renderer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+const (
+	// kappa places cubic control points to approximate a quarter circle.
+	kappa = 0.5522847498
+
+	roundRadius    = 3
+	socialRadius   = 2
+	socialStroke   = Color("#d5d5d5")
+	shadowOpacity  = 0.3
+	socialOpacity  = 0.7
+	textBaseline   = 14
+	forTheBadgeDy  = 4
+	logoY          = 3
+	logoHeight     = 14
+	boldStrokeSize = 0.04
+)
+
+// RenderPNG rasterizes the badge in pure Go and encodes it as a PNG. The image
+// has the size of the SVG returned by Render multiplied by scale, e.g. 2 for
+// high density displays; zero means 1. Text is drawn with the renderer fonts,
+// or Go Regular for renderers without font files. Animations are drawn at
+// rest and dark palettes are not applied. Built-in styles are drawn as
+// shipped even when their template was replaced, and other registered styles
+// cannot be rasterized.
+func (r *Renderer) RenderPNG(b Badge, scale float64) ([]byte, error) {
+	img, err := r.rasterize(b, scale)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	if err = png.Encode(buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (r *Renderer) rasterize(b Badge, scale float64) (*image.RGBA, error) {
+	if r == nil {
+		return nil, errors.New("renderer is nil")
+	}
+	fr, err := r.fontRenderer(b)
+	if err != nil {
+		return nil, err
+	}
+	if fr != r {
+		return fr.rasterize(b, scale)
+	}
+	pixelScale, err := badgeScale(scale)
+	if err != nil {
+		return nil, err
+	}
+	p, err := r.prepare(b)
+	if err != nil {
+		return nil, err
+	}
+	if !p.style.IsValid() {
+		return nil, fmt.Errorf("png output is not supported for style: %q", p.style)
+	}
+	k := p.metrics.scale * pixelScale
+	if k > MaxScale {
+		return nil, fmt.Errorf("invalid scale: %v", p.metrics.scale*pixelScale)
+	}
+	c := newCanvas(p.data.Bounds.Dx(), p.metrics.height, k)
+	c.drawSegments(p.style, p.data.Bounds, badgeFills(b, p), b.Kind == KindProgress)
+	logoTop := float64(logoY)
+	if p.style == StyleForTheBadge {
+		logoTop += forTheBadgeDy
+	}
+	if p.data.Logo != "" {
+		c.drawLogo(string(p.data.Logo), p.data.Bounds.LogoX, logoTop, p.data.Bounds.LogoDx, logoHeight)
+	}
+	for _, s := range p.data.Segments {
+		if s.Logo != "" {
+			c.drawLogo(string(s.Logo), s.Bounds.LogoX, logoTop, s.Bounds.LogoDx, logoHeight)
+		}
+	}
+	if p.data.Bounds.SpinnerX > 0 {
+		c.drawSpinner(p.data.Bounds.SpinnerX, p.metrics.height/2, uniform(joinPaint(p.data.StatusTextColor, p.data.StatusTextOpacity), 1))
+	}
+	if err = r.drawText(c, p); err != nil {
+		return nil, err
+	}
+	return c.img, nil
+}
+
+// segmentFill is the fill of one segment of a badge.
+type segmentFill struct {
+	x, dx float64
+	color Color
+	// label is set on the segment drawn as a subject.
+	label bool
+	// notchX and notchTipX are the arrow of a social bubble, zero without one.
+	notchX, notchTipX float64
+}
+
+// badgeFills returns the fills of the segments of the badge b prepared as p,
+// in reading order.
+func badgeFills(b Badge, p preparedBadge) []segmentFill {
+	if len(p.data.Segments) > 0 {
+		fills := make([]segmentFill, 0, len(p.data.Segments))
+		for _, s := range p.data.Segments {
+			fills = append(fills, segmentFill{
+				x: s.Bounds.Start, dx: s.Bounds.Dx, color: joinPaint(s.Color, s.ColorOpacity), label: s.Label,
+				notchX: s.Bounds.NotchX, notchTipX: s.Bounds.NotchTipX,
+			})
+		}
+		return fills
+	}
+	labelColor, color := segmentColors(b, p.metrics)
+	return []segmentFill{
+		{x: p.data.Bounds.SubjectStart(), dx: p.data.Bounds.SubjectDx, color: labelColor, label: true},
+		{
+			x: p.data.Bounds.StatusStart(), dx: p.data.Bounds.StatusDx, color: color,
+			notchX: p.data.Bounds.NotchX, notchTipX: p.data.Bounds.NotchTipX,
+		},
+	}
+}
+
+// canvas draws in badge units, scale pixels each.
+type canvas struct {
+	img   *image.RGBA
+	scale float64
+}
+
+func newCanvas(dx, dy, scale float64) *canvas {
+	width := int(math.Ceil(dx * scale))
+	height := int(math.Ceil(dy * scale))
+	return &canvas{img: image.NewRGBA(image.Rect(0, 0, width, height)), scale: scale}
+}
+
+func (c *canvas) rasterizer() *vector.Rasterizer {
+	size := c.img.Bounds().Size()
+	return vector.NewRasterizer(size.X, size.Y)
+}
+
+// roundedRect adds a closed rectangle with corner radius rx to z, clockwise
+// or, with reverse set, counterclockwise so it cuts a hole.
+func (c *canvas) roundedRect(z *vector.Rasterizer, x, y, w, h, rx float64, reverse bool) {
+	rx = math.Min(rx, math.Min(w, h)/2)
+	pt := func(px, py float64) (float32, float32) {
+		return float32(px * c.scale), float32(py * c.scale)
+	}
+	k := rx * kappa
+	// Corners as start point, control points and end point, clockwise from the top right.
+	corners := [4][4][2]float64{
+		{{x + w - rx, y}, {x + w - rx + k, y}, {x + w, y + rx - k}, {x + w, y + rx}},
+		{{x + w, y + h - rx}, {x + w, y + h - rx + k}, {x + w - rx + k, y + h}, {x + w - rx, y + h}},
+		{{x + rx, y + h}, {x + rx - k, y + h}, {x, y + h - rx + k}, {x, y + h - rx}},
+		{{x, y + rx}, {x, y + rx - k}, {x + rx - k, y}, {x + rx, y}},
+	}
+	if reverse {
+		for i := range corners {
+			corners[i] = [4][2]float64{corners[i][3], corners[i][2], corners[i][1], corners[i][0]}
+		}
+		corners = [4][4][2]float64{corners[3], corners[2], corners[1], corners[0]}
+	}
+	z.MoveTo(pt(corners[0][0][0], corners[0][0][1]))
+	for _, corner := range corners {
+		z.LineTo(pt(corner[0][0], corner[0][1]))
+		if rx > 0 {
+			x1, y1 := pt(corner[1][0], corner[1][1])
+			x2, y2 := pt(corner[2][0], corner[2][1])
+			x3, y3 := pt(corner[3][0], corner[3][1])
+			z.CubeTo(x1, y1, x2, y2, x3, y3)
+		}
+	}
+	z.ClosePath()
+}
+
+// fill draws src over the canvas inside the rounded rectangle.
+func (c *canvas) fill(dst draw.Image, x, y, w, h, rx float64, src image.Image) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	z := c.rasterizer()
+	c.roundedRect(z, x, y, w, h, rx, false)
+	z.Draw(dst, dst.Bounds(), src, image.Point{})
+}
+
+// stroke draws a one unit wide outline centered on the rounded rectangle.
+func (c *canvas) stroke(x, y, w, h, rx float64, src image.Image) {
+	const half = 0.5
+	z := c.rasterizer()
+	c.roundedRect(z, x-half, y-half, w+2*half, h+2*half, rx+half, false)
+	c.roundedRect(z, x+half, y+half, w-2*half, h-2*half, math.Max(rx-half, 0), true)
+	z.Draw(c.img, c.img.Bounds(), src, image.Point{})
+}
+
+// drawSegments fills the segments, drawing the last one as a progress bar
+// when progress is set.
+func (c *canvas) drawSegments(style Style, b bounds, fills []segmentFill, progress bool) {
+	m := style.metrics()
+	if style == StyleSocial {
+		border := uniform(socialStroke, 1)
+		smooth := c.gradient(0.5, m.height-1, gradientStop{0, socialLabelColor, 0}, gradientStop{1, "#000", 0.1})
+		for _, seg := range fills {
+			x, y, w, h := seg.x+0.5, 0.5, seg.dx-1, m.height-1
+			c.fill(c.img, x, y, w, h, socialRadius, uniform(seg.color, 1))
+			c.stroke(x, y, w, h, socialRadius, border)
+			if seg.label {
+				c.fill(c.img, x, y, w, h, socialRadius, smooth)
+			}
+			if seg.notchX != 0 {
+				c.drawNotch(seg.notchX, seg.notchTipX, uniform(seg.color, 1), border)
+			}
+		}
+		return
+	}
+	var (
+		overlay image.Image
+		radius  float64
+	)
+	switch style {
+	case StyleFlat:
+		overlay = c.gradient(0, m.height, gradientStop{0, "#bbb", 0.1}, gradientStop{1, "#000", 0.1})
+		radius = roundRadius
+	case StyleFlatSquare:
+		overlay = c.gradient(0, m.height, gradientStop{0, "#bbb", 0.1}, gradientStop{1, "#000", 0.1})
+	case StylePlastic:
+		overlay = c.gradient(0, m.height,
+			gradientStop{0, "#fff", 0.7}, gradientStop{0.1, "#aaa", 0.1},
+			gradientStop{0.9, "#000", 0.3}, gradientStop{1, "#000", 0.5})
+		radius = roundRadius
+	case StyleForTheBadge, StyleSocial:
+	default:
+	}
+	layer := image.NewRGBA(c.img.Bounds())
+	for i, seg := range fills {
+		if progress && i == len(fills)-1 {
+			c.fill(layer, seg.x, 0, seg.dx, m.height, 0, uniform(progressTrackColor, 1))
+			c.fill(layer, b.FillX, 0, b.FillDx, m.height, 0, uniform(seg.color, 1))
+			continue
+		}
+		c.fill(layer, seg.x, 0, seg.dx, m.height, 0, uniform(seg.color, 1))
+	}
+	if overlay != nil {
+		c.fill(layer, 0, 0, b.Dx(), m.height, 0, overlay)
+	}
+	mask := image.NewAlpha(c.img.Bounds())
+	c.fill(mask, 0, 0, b.Dx(), m.height, radius, image.Opaque)
+	draw.DrawMask(c.img, c.img.Bounds(), layer, image.Point{}, mask, image.Point{}, draw.Over)
+}
+
+// drawNotch draws the arrow of a social bubble as the template does: it
+// opens the bubble border between y 7 and 13 at x, fills the arrow to its tip
+// at tipX and strokes its two slanted sides and its tip.
+func (c *canvas) drawNotch(x, tipX float64, fill, border image.Image) {
+	pt := func(px, py float64) (float32, float32) {
+		return float32(px * c.scale), float32(py * c.scale)
+	}
+	c.fill(c.img, x-1, 7, 1.5, 6, 0, fill)
+	z := c.rasterizer()
+	z.MoveTo(pt(x, 6.5))
+	z.LineTo(pt(tipX, 9.5))
+	z.LineTo(pt(tipX, 10.5))
+	z.LineTo(pt(x, 13.5))
+	z.ClosePath()
+	z.Draw(c.img, c.img.Bounds(), fill, image.Point{})
+	z = c.rasterizer()
+	points := [][2]float64{{x, 6.5}, {tipX, 9.5}, {tipX, 10.5}, {x, 13.5}}
+	for i := 1; i < len(points); i++ {
+		c.line(z, points[i-1], points[i], 1)
+	}
+	z.Draw(c.img, c.img.Bounds(), border, image.Point{})
+}
+
+// line adds a line of width w from a to b to z, as a quadrilateral.
+func (c *canvas) line(z *vector.Rasterizer, a, b [2]float64, w float64) {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return
+	}
+	// nx, ny is half the width across the line.
+	nx, ny := -dy/length*w/2, dx/length*w/2
+	pt := func(px, py float64) (float32, float32) {
+		return float32(px * c.scale), float32(py * c.scale)
+	}
+	z.MoveTo(pt(a[0]+nx, a[1]+ny))
+	z.LineTo(pt(b[0]+nx, b[1]+ny))
+	z.LineTo(pt(b[0]-nx, b[1]-ny))
+	z.LineTo(pt(a[0]-nx, a[1]-ny))
+	z.ClosePath()
+}
+
+func (c *canvas) drawLogo(uri string, x, y, w, h float64) {
+	icon, ok := parseSVGIcon(uri)
+	if !ok || icon.viewBox[2] <= 0 || icon.viewBox[3] <= 0 {
+		return
+	}
+	// Fit the view box into the logo box, centered, as preserveAspectRatio does by default.
+	fit := math.Min(w/icon.viewBox[2], h/icon.viewBox[3])
+	dx := x + (w-icon.viewBox[2]*fit)/2 - icon.viewBox[0]*fit
+	dy := y + (h-icon.viewBox[3]*fit)/2 - icon.viewBox[1]*fit
+	for _, path := range icon.paths {
+		z := c.rasterizer()
+		if !addSVGPath(z, path.d, func(px, py float64) (float32, float32) {
+			return float32((px*fit + dx) * c.scale), float32((py*fit + dy) * c.scale)
+		}) {
+			continue
+		}
+		z.Draw(c.img, c.img.Bounds(), image.NewUniform(path.fill), image.Point{})
+	}
+}
+
+// drawSpinner draws the spinner ring centered on x, y, at rest, with the gap
+// of its dash pattern.
+func (c *canvas) drawSpinner(x, y float64, src image.Image) {
+	const (
+		dash  = 16
+		steps = 24
+	)
+	outer, inner := spinnerRadius+spinnerStroke/2, spinnerRadius-spinnerStroke/2
+	sweep := dash / spinnerRadius
+	pt := func(radius, angle float64) (float32, float32) {
+		return float32((x + radius*math.Cos(angle)) * c.scale), float32((y + radius*math.Sin(angle)) * c.scale)
+	}
+	z := c.rasterizer()
+	z.MoveTo(pt(outer, 0))
+	for i := 1; i <= steps; i++ {
+		z.LineTo(pt(outer, sweep*float64(i)/steps))
+	}
+	for i := steps; i >= 0; i-- {
+		z.LineTo(pt(inner, sweep*float64(i)/steps))
+	}
+	z.ClosePath()
+	z.Draw(c.img, c.img.Bounds(), src, image.Point{})
+}
+
+// gradientStop is a stop of a vertical SVG linear gradient.
+type gradientStop struct {
+	offset  float64
+	color   Color
+	opacity float64
+}
+
+// verticalGradient interpolates its stops from top to top+height pixels.
+type verticalGradient struct {
+	top    float64
+	height float64
+	stops  []color.NRGBA
+	offset []float64
+}
+
+func (c *canvas) gradient(top, height float64, stops ...gradientStop) image.Image {
+	g := &verticalGradient{top: top * c.scale, height: height * c.scale}
+	for _, stop := range stops {
+		rgba, _ := stop.color.NRGBA()
+		rgba.A = toByte(stop.opacity)
+		g.stops = append(g.stops, rgba)
+		g.offset = append(g.offset, stop.offset)
+	}
+	return g
+}
+
+func (g *verticalGradient) ColorModel() color.Model { return color.NRGBAModel }
+
+func (g *verticalGradient) Bounds() image.Rectangle {
+	return image.Rect(-1e9, -1e9, 1e9, 1e9)
+}
+
+func (g *verticalGradient) At(_, y int) color.Color {
+	t := (float64(y) + 0.5 - g.top) / g.height
+	if t <= g.offset[0] {
+		return g.stops[0]
+	}
+	for i := 1; i < len(g.stops); i++ {
+		if t > g.offset[i] {
+			continue
+		}
+		f := (t - g.offset[i-1]) / (g.offset[i] - g.offset[i-1])
+		lerp := func(a, b uint8) uint8 {
+			return uint8(math.Round(float64(a) + (float64(b)-float64(a))*f))
+		}
+		a, b := g.stops[i-1], g.stops[i]
+		return color.NRGBA{R: lerp(a.R, b.R), G: lerp(a.G, b.G), B: lerp(a.B, b.B), A: lerp(a.A, b.A)}
+	}
+	return g.stops[len(g.stops)-1]
+}
+
+func uniform(c Color, opacity float64) *image.Uniform {
+	rgba, _ := c.NRGBA()
+	rgba.A = toByte(float64(rgba.A) / maxByte * opacity)
+	return image.NewUniform(rgba)
+}
+
+// drawText draws the text and shadows of the segments as the style template
+// does.
+func (r *Renderer) drawText(c *canvas, p preparedBadge) error {
+	m := p.metrics
+	chain, err := r.rasterChain(fontsize * m.fontScale * c.scale)
+	if err != nil {
+		return err
+	}
+	chain.mutex.Lock()
+	defer chain.mutex.Unlock()
+	baseline, shadow := float64(textBaseline), shadowOpacity
+	switch p.style {
+	case StyleForTheBadge:
+		baseline, shadow = textBaseline+forTheBadgeDy, 0
+	case StyleSocial:
+		shadow = socialOpacity
+	case StyleFlat, StyleFlatSquare, StylePlastic:
+	default:
+	}
+	for _, t := range badgeTexts(p) {
+		line := textLine{faces: chain.faces, bold: t.bold, spacing: m.letterSpacing * c.scale}
+		line.layout(t.text, t.textDx*c.scale)
+		x := t.x*c.scale - line.width/2
+		if shadow > 0 {
+			line.draw(c.img, uniform(Color(t.shadow), shadow), x, (baseline+1)*c.scale)
+		}
+		line.draw(c.img, uniform(Color(t.color), 1), x, baseline*c.scale)
+	}
+	return nil
+}
+
+// segmentText is the text of one segment as drawn.
+type segmentText struct {
+	text          string
+	bold          bool
+	x, textDx     float64
+	color, shadow string
+}
+
+// badgeTexts returns the text of the segments of p.
+func badgeTexts(p preparedBadge) []segmentText {
+	m, d := p.metrics, p.data
+	if len(d.Segments) > 0 {
+		texts := make([]segmentText, 0, len(d.Segments))
+		for _, s := range d.Segments {
+			texts = append(texts, segmentText{s.Text, s.Bold, s.Bounds.X, s.Bounds.TextDx, string(joinPaint(s.TextColor, s.TextOpacity)), s.ShadowColor})
+		}
+		return texts
+	}
+	return []segmentText{
+		{d.Subject, m.boldSubject, d.Bounds.SubjectX, d.Bounds.SubjectTextDx, string(joinPaint(d.SubjectTextColor, d.SubjectTextOpacity)), d.SubjectShadowColor},
+		{d.Status, m.boldStatus, d.Bounds.StatusX, d.Bounds.StatusTextDx, string(joinPaint(d.StatusTextColor, d.StatusTextOpacity)), d.StatusShadowColor},
+	}
+}
+
+// rasterChain returns the font chain at size pixels for drawing. Renderers
+// without font files draw with Go Regular. Callers must hold the chain mutex
+// while they use its faces.
+func (r *Renderer) rasterChain(size float64) (*glyphChain, error) {
+	text, ok := r.text.(*fontMeasurer)
+	if !ok {
+		var err error
+		if text, err = r.raster(); err != nil {
+			return nil, err
+		}
+	}
+	return sizedChain(text, size), nil
+}
+
+// sizedChain returns the chain of text at size. Past maxSizedFaces, it is a
+// chain of its own rather than one with rescaled widths.
+func sizedChain(text *fontMeasurer, size float64) *glyphChain {
+	chain, ratio := text.chainAt(size)
+	if ratio == 1 || chain.faces[0].resize == nil {
+		return chain
+	}
+	faces := make([]fontFace, 0, len(chain.faces))
+	for _, face := range chain.faces {
+		faces = append(faces, face.resize(size))
+	}
+	return newGlyphChain(faces)
+}
+
+// newRasterMeasurer measures and draws PNG text with Go Regular.
+func newRasterMeasurer() (*fontMeasurer, error) {
+	f, err := sfnt.Parse(goregular.TTF)
+	if err != nil {
+		return nil, err
+	}
+	return newFontMeasurer([]fontFace{newSFNTFace(f, fontsize, dpi)}), nil
+}
+
+// textLine places the glyphs of one line of text in visual order.
+type textLine struct {
+	faces   []fontFace
+	bold    bool
+	spacing float64
+	glyphs  []placedGlyph
+	width   float64
+}
+
+type placedGlyph struct {
+	r    rune
+	face font.Face
+	x    float64
+}
+
+// layout positions the runes of s, compressing them to textDx pixels when it
+// is non-zero.
+func (l *textLine) layout(s string, textDx float64) {
+	x := 0.0
+	prev, prevFace := rune(-1), -1
+	for _, run := range bidiRuns(s) {
+		for _, r := range run {
+			i := faceFor(l.faces, r)
+			face := l.faces[i].drawer.Face
+			if i == prevFace {
+				x += fixedFloat(face.Kern(prev, r))
+			}
+			advance, _ := face.GlyphAdvance(r)
+			l.glyphs = append(l.glyphs, placedGlyph{r: r, face: face, x: x})
+			step := fixedFloat(advance)
+			if l.bold {
+				step *= boldWidthFactor
+			}
+			x += step + l.spacing
+			prev, prevFace = r, i
+		}
+	}
+	l.width = x
+	if textDx > 0 && x > 0 {
+		for i := range l.glyphs {
+			l.glyphs[i].x *= textDx / x
+		}
+		l.width = textDx
+	}
+}
+
+// draw renders the line with its left edge at x. Bold text is emboldened by
+// drawing every glyph twice, slightly offset.
+func (l *textLine) draw(dst draw.Image, src image.Image, x, baseline float64) {
+	for _, g := range l.glyphs {
+		d := font.Drawer{Dst: dst, Src: src, Face: g.face}
+		d.Dot = fixed.Point26_6{X: floatFixed(x + g.x), Y: floatFixed(baseline)}
+		d.DrawString(string(g.r))
+		if l.bold {
+			size := fixedFloat(g.face.Metrics().Height)
+			d.Dot = fixed.Point26_6{X: floatFixed(x + g.x + size*boldStrokeSize), Y: floatFixed(baseline)}
+			d.DrawString(string(g.r))
+		}
+	}
+}
+
+func fixedFloat(v fixed.Int26_6) float64 {
+	return float64(v) / (1 << measureShift)
+}
+
+func floatFixed(v float64) fixed.Int26_6 {
+	return fixed.Int26_6(math.Round(v * (1 << measureShift)))
+}
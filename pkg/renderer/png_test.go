@@ -0,0 +1,157 @@
+package renderer_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"testing"
+
+	"github.com/rhajizada/signum/pkg/renderer"
+)
+
+func decodePNG(tb testing.TB, data []byte) image.Image {
+	tb.Helper()
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		tb.Fatalf("decode png: %v", err)
+	}
+	return img
+}
+
+func TestRenderPNGMatchesSVGSize(t *testing.T) {
+	r := newRenderer(t)
+	for _, style := range renderer.Styles() {
+		b := renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen, Style: style}
+		svg, err := r.Render(b)
+		if err != nil {
+			t.Fatalf("render %s: %v", style, err)
+		}
+		data, err := r.RenderPNG(b, 2)
+		if err != nil {
+			t.Fatalf("render png %s: %v", style, err)
+		}
+		img := decodePNG(t, data)
+		want := int(math.Ceil(badgeWidth(t, string(svg)) * 2))
+		if got := img.Bounds().Dx(); got != want {
+			t.Fatalf("%s: expected png width %d, got %d", style, want, got)
+		}
+	}
+}
+
+func TestRenderPNGDrawsSegments(t *testing.T) {
+	r := newRenderer(t)
+	data, err := r.RenderPNG(renderer.Badge{
+		Subject:    "build",
+		Status:     "passing",
+		Color:      "#0000ff",
+		LabelColor: "#ff0000",
+		Style:      renderer.StyleForTheBadge,
+	}, 1)
+	if err != nil {
+		t.Fatalf("render png: %v", err)
+	}
+	img := decodePNG(t, data)
+	bounds := img.Bounds()
+	if got := color.NRGBAModel.Convert(img.At(1, 1)); got != (color.NRGBA{R: 0xff, A: 0xff}) {
+		t.Fatalf("expected label color at the left edge, got %v", got)
+	}
+	if got := color.NRGBAModel.Convert(img.At(bounds.Max.X-2, 1)); got != (color.NRGBA{B: 0xff, A: 0xff}) {
+		t.Fatalf("expected status color at the right edge, got %v", got)
+	}
+	text := false
+	for x := bounds.Max.X / 2; x < bounds.Max.X && !text; x++ {
+		for y := range bounds.Max.Y {
+			if red8, _, _, _ := img.At(x, y).RGBA(); red8 != 0 {
+				text = true
+				break
+			}
+		}
+	}
+	if !text {
+		t.Fatalf("expected status text to be drawn")
+	}
+}
+
+func TestRenderPNGRoundsCorners(t *testing.T) {
+	r := newRenderer(t)
+	data, err := r.RenderPNG(renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen}, 1)
+	if err != nil {
+		t.Fatalf("render png: %v", err)
+	}
+	img := decodePNG(t, data)
+	if _, _, _, a := img.At(0, 0).RGBA(); a == 0xffff {
+		t.Fatalf("expected a rounded, translucent corner")
+	}
+	if _, _, _, a := img.At(0, 10).RGBA(); a != 0xffff {
+		t.Fatalf("expected an opaque left edge")
+	}
+}
+
+func TestRenderPNGDrawsLogo(t *testing.T) {
+	r := newRenderer(t)
+	b := renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen, Style: renderer.StyleFlatSquare}
+	plain, err := r.RenderPNG(b, 1)
+	if err != nil {
+		t.Fatalf("render png: %v", err)
+	}
+	b.Logo = renderer.Logo(renderer.LogoNames()[0])
+	b.LogoColor = "#ff0000"
+	withLogo, err := r.RenderPNG(b, 1)
+	if err != nil {
+		t.Fatalf("render png with logo: %v", err)
+	}
+	img := decodePNG(t, withLogo)
+	if img.Bounds().Dx() <= decodePNG(t, plain).Bounds().Dx() {
+		t.Fatalf("expected the logo to widen the badge")
+	}
+	red := false
+	for x := 5; x < 19 && !red; x++ {
+		for y := 3; y < 17; y++ {
+			if red8, green8, _, _ := img.At(x, y).RGBA(); red8 > 2*green8 {
+				red = true
+				break
+			}
+		}
+	}
+	if !red {
+		t.Fatalf("expected the tinted logo to be drawn")
+	}
+}
+
+func TestRenderPNGVerdanaRenderer(t *testing.T) {
+	r, err := renderer.NewVerdanaRenderer()
+	if err != nil {
+		t.Fatalf("new renderer: %v", err)
+	}
+	data, err := r.RenderPNG(renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen, Scale: 2}, 1.5)
+	if err != nil {
+		t.Fatalf("render png: %v", err)
+	}
+	if got := decodePNG(t, data).Bounds().Dy(); got != 60 {
+		t.Fatalf("expected png height 60, got %d", got)
+	}
+}
+
+func TestRenderPNGInvalid(t *testing.T) {
+	r := newRenderer(t)
+	if err := r.RegisterStyle("custom", `<svg>{{.Subject}}</svg>`); err != nil {
+		t.Fatalf("register style: %v", err)
+	}
+	cases := []struct {
+		name  string
+		badge renderer.Badge
+		scale float64
+	}{
+		{"custom style", renderer.Badge{Subject: "a", Status: "b", Color: renderer.ColorGreen, Style: "custom"}, 1},
+		{"negative scale", renderer.Badge{Subject: "a", Status: "b", Color: renderer.ColorGreen}, -1},
+		{"too large", renderer.Badge{Subject: "a", Status: "b", Color: renderer.ColorGreen, Scale: 4}, 4},
+		{"invalid color", renderer.Badge{Subject: "a", Status: "b", Color: "nope"}, 1},
+	}
+	for _, tc := range cases {
+		if _, err := r.RenderPNG(tc.badge, tc.scale); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package renderer
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"text/template/parse"
+)
+
+// TemplateExt is the file extension of style templates loaded by LoadStyleDir.
+const TemplateExt = ".svg.tmpl"
+
+// RegisterStyle parses tmpl and makes it available as style name. Registering
+// a built-in name replaces its template but keeps its text metrics; new styles
+// are measured like StyleFlat. The template may only reference fields of the
+// data contract documented in the package overview, and draws Badge.Segments
+// only if it ranges over .Segments.
+func (r *Renderer) RegisterStyle(name Style, tmpl string) error {
+	if r == nil {
+		return errors.New("renderer is nil")
+	}
+	if !validStyleName(name) {
+		return fmt.Errorf("invalid style name: %q", name)
+	}
+	parsed, err := parseTemplate(name, tmpl)
+	if err != nil {
+		return err
+	}
+	if err = validateTemplateFields(parsed); err != nil {
+		return fmt.Errorf("style %q: %w", name, err)
+	}
+	compiled := newStyleTemplate(parsed)
+	r.stylesMutex.Lock()
+	defer r.stylesMutex.Unlock()
+	r.tmpls[name] = compiled
+	r.cache.purge()
+	return nil
+}
+
+// LoadStyleDir registers every *.svg.tmpl file in dir as a style named after
+// the file, e.g. corporate.svg.tmpl becomes "corporate".
+func (r *Renderer) LoadStyleDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+TemplateExt))
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no %s templates in %s", TemplateExt, dir)
+	}
+	for _, path := range paths {
+		data, readErr := os.ReadFile(path)
+		if readErr != nil {
+			return readErr
+		}
+		name := Style(strings.TrimSuffix(filepath.Base(path), TemplateExt))
+		if err = r.RegisterStyle(name, string(data)); err != nil {
+			return fmt.Errorf("load %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// validStyleName accepts lowercase ASCII letters, digits and inner hyphens.
+func validStyleName(name Style) bool {
+	if name == "" || name[0] == '-' {
+		return false
+	}
+	for _, c := range name {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// HasStyle reports whether style is built in or registered.
+func (r *Renderer) HasStyle(style Style) bool {
+	_, ok := r.template(style)
+	return ok
+}
+
+// HasSegments reports whether style can draw badges with Badge.Segments:
+// every built-in style can, and registered ones that range over .Segments.
+func (r *Renderer) HasSegments(style Style) bool {
+	tmpl, ok := r.template(style)
+	if !ok {
+		return false
+	}
+	_, err := r.segmentsTemplate(style, tmpl)
+	return err == nil
+}
+
+// Styles returns the built-in styles followed by registered ones in name order.
+func (r *Renderer) Styles() []Style {
+	styles := Styles()
+	r.stylesMutex.RLock()
+	defer r.stylesMutex.RUnlock()
+	custom := make([]Style, 0, len(r.tmpls))
+	for style := range r.tmpls {
+		if !slices.Contains(styles, style) {
+			custom = append(custom, style)
+		}
+	}
+	slices.Sort(custom)
+	return append(styles, custom...)
+}
+
+func (r *Renderer) template(style Style) (*styleTemplate, bool) {
+	r.stylesMutex.RLock()
+	defer r.stylesMutex.RUnlock()
+	tmpl, ok := r.tmpls[style]
+	return tmpl, ok
+}
+
+// validateTemplateFields checks that every field reached from dot or $ exists
+// on badgeTemplateData, so typos fail at registration instead of at render time.
+func validateTemplateFields(tmpl *template.Template) error {
+	root := reflect.TypeFor[badgeTemplateData]()
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil || t.Tree.Root == nil {
+			continue
+		}
+		if err := checkNode(t.Tree.Root, root, root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkNode walks n with dot of type dot. A nil dot means the type is unknown,
+// e.g. inside a range over anything but a slice field, and field references
+// on it are not checked.
+func checkNode(n parse.Node, dot, root reflect.Type) error {
+	switch node := n.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return nil
+		}
+		for _, child := range node.Nodes {
+			if err := checkNode(child, dot, root); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return checkNode(node.Pipe, dot, root)
+	case *parse.PipeNode:
+		if node == nil {
+			return nil
+		}
+		for _, cmd := range node.Cmds {
+			if err := checkNode(cmd, dot, root); err != nil {
+				return err
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range node.Args {
+			if err := checkNode(arg, dot, root); err != nil {
+				return err
+			}
+		}
+	case *parse.FieldNode:
+		_, err := resolveFields(dot, node.Ident, node.String())
+		return err
+	case *parse.VariableNode:
+		if len(node.Ident) > 1 && node.Ident[0] == "$" {
+			_, err := resolveFields(root, node.Ident[1:], node.String())
+			return err
+		}
+	case *parse.ChainNode:
+		return checkNode(node.Node, dot, root)
+	case *parse.IfNode:
+		return checkBranch(&node.BranchNode, dot, dot, root)
+	case *parse.WithNode:
+		return checkBranch(&node.BranchNode, pipeType(node.Pipe, dot), dot, root)
+	case *parse.RangeNode:
+		return checkBranch(&node.BranchNode, elemType(pipeType(node.Pipe, dot)), dot, root)
+	case *parse.TemplateNode:
+		return checkNode(node.Pipe, dot, root)
+	default:
+	}
+	return nil
+}
+
+func checkBranch(b *parse.BranchNode, inner, dot, root reflect.Type) error {
+	if err := checkNode(b.Pipe, dot, root); err != nil {
+		return err
+	}
+	if err := checkNode(b.List, inner, root); err != nil {
+		return err
+	}
+	return checkNode(b.ElseList, dot, root)
+}
+
+// pipeType returns the type a single-field pipeline such as .Bounds evaluates
+// to, or nil when it cannot be determined statically.
+func pipeType(pipe *parse.PipeNode, dot reflect.Type) reflect.Type {
+	if pipe == nil || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return nil
+	}
+	switch arg := pipe.Cmds[0].Args[0].(type) {
+	case *parse.FieldNode:
+		t, _ := resolveFields(dot, arg.Ident, arg.String())
+		return t
+	case *parse.DotNode:
+		return dot
+	default:
+		return nil
+	}
+}
+
+// elemType returns the element type of a slice type, or nil for other types.
+func elemType(t reflect.Type) reflect.Type {
+	if t == nil || t.Kind() != reflect.Slice {
+		return nil
+	}
+	return t.Elem()
+}
+
+func resolveFields(t reflect.Type, idents []string, ref string) (reflect.Type, error) {
+	for _, ident := range idents {
+		if t == nil {
+			break
+		}
+		if method, ok := t.MethodByName(ident); ok && method.Type.NumOut() > 0 {
+			t = method.Type.Out(0)
+			continue
+		}
+		if t.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("template references unknown field %s", ref)
+		}
+		field, ok := t.FieldByName(ident)
+		if !ok || !field.IsExported() {
+			return nil, fmt.Errorf("template references unknown field %s", ref)
+		}
+		t = field.Type
+	}
+	return t, nil
+}
@@ -0,0 +1,123 @@
+package renderer_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rhajizada/signum/pkg/renderer"
+)
+
+const corporateTemplate = `<svg xmlns="http://www.w3.org/2000/svg" width="{{.Bounds.Dx}}" height="20">
+  <rect x="{{.Bounds.SubjectStart}}" width="{{.Bounds.SubjectDx}}" height="20" fill="{{or .LabelColor "#123456"}}"/>
+  <rect x="{{.Bounds.StatusStart}}" width="{{.Bounds.StatusDx}}" height="20" fill="{{.Color}}"/>
+  {{with .Bounds}}<line x1="{{.StatusStart}}" x2="{{.StatusStart}}" y2="20" stroke="#fff"/>{{end -}}
+  <g font-family="{{.FontFamily}}" font-size="11" text-anchor="middle">
+    <text x="{{.Bounds.SubjectX}}" y="14">{{.Subject}}</text>
+    <text x="{{$.Bounds.StatusX}}" y="14">{{.Status}}</text>
+  </g>
+</svg>`
+
+func TestRendererRegisterStyle(t *testing.T) {
+	r := newRenderer(t)
+	if err := r.RegisterStyle("corporate", corporateTemplate); err != nil {
+		t.Fatalf("register style: %v", err)
+	}
+	if !r.HasStyle("corporate") {
+		t.Fatalf("expected registered style")
+	}
+	if styles := r.Styles(); styles[len(styles)-1] != "corporate" || !slices.Contains(styles, renderer.StyleFlat) {
+		t.Fatalf("expected built-in and registered styles, got %v", styles)
+	}
+
+	output, err := r.Render(renderer.Badge{
+		Subject: "build",
+		Status:  "passing",
+		Color:   renderer.ColorGreen,
+		Style:   "corporate",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := string(output)
+	if !strings.Contains(result, `fill="#123456"`) || !strings.Contains(result, "<line") {
+		t.Fatalf("expected custom template output: %s", result)
+	}
+}
+
+func TestRendererRegisterStyleOverridesBuiltIn(t *testing.T) {
+	r := newRenderer(t)
+	if err := r.RegisterStyle(renderer.StyleFlat, corporateTemplate); err != nil {
+		t.Fatalf("register style: %v", err)
+	}
+	output, err := r.Render(renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(output), `fill="#123456"`) {
+		t.Fatalf("expected overridden flat template: %s", output)
+	}
+	if len(r.Styles()) != len(renderer.Styles()) {
+		t.Fatalf("expected no duplicate styles, got %v", r.Styles())
+	}
+}
+
+func TestRendererRegisterStyleInvalid(t *testing.T) {
+	r := newRenderer(t)
+	tests := []struct {
+		name  renderer.Style
+		tmpl  string
+		error string
+	}{
+		{name: "", tmpl: corporateTemplate, error: "invalid style name"},
+		{name: "Corporate", tmpl: corporateTemplate, error: "invalid style name"},
+		{name: "../flat", tmpl: corporateTemplate, error: "invalid style name"},
+		{name: "empty", tmpl: "  ", error: "empty template"},
+		{name: "syntax", tmpl: "<svg>{{.Subject</svg>", error: "template: syntax"},
+		{name: "field", tmpl: "<svg>{{.Caption}}</svg>", error: "unknown field .Caption"},
+		{name: "bounds", tmpl: "<svg>{{.Bounds.Width}}</svg>", error: "unknown field .Bounds.Width"},
+		{name: "with", tmpl: "<svg>{{with .Bounds}}{{.Subject}}{{end}}</svg>", error: "unknown field .Subject"},
+		{name: "root", tmpl: "<svg>{{$.Token}}</svg>", error: "unknown field $.Token"},
+		{name: "unexported", tmpl: "<svg>{{.fd}}</svg>", error: "unknown field .fd"},
+	}
+	for _, tc := range tests {
+		err := r.RegisterStyle(tc.name, tc.tmpl)
+		if err == nil || !strings.Contains(err.Error(), tc.error) {
+			t.Fatalf("%q: expected error containing %q, got %v", tc.name, tc.error, err)
+		}
+	}
+	if r.HasStyle("field") {
+		t.Fatalf("expected invalid style not to be registered")
+	}
+}
+
+func TestRendererLoadStyleDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "corporate.svg.tmpl"), []byte(corporateTemplate), 0o600); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	r := newRenderer(t)
+	if err := r.LoadStyleDir(dir); err != nil {
+		t.Fatalf("load style dir: %v", err)
+	}
+	if !r.HasStyle("corporate") || r.HasStyle("notes") {
+		t.Fatalf("unexpected styles: %v", r.Styles())
+	}
+
+	if err := r.LoadStyleDir(t.TempDir()); err == nil {
+		t.Fatalf("expected error for empty template dir")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.svg.tmpl"), []byte("<svg>{{.Nope}}</svg>"), 0o600); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	if err := r.LoadStyleDir(dir); err == nil || !strings.Contains(err.Error(), "broken.svg.tmpl") {
+		t.Fatalf("expected error naming the broken template, got %v", err)
+	}
+}
@@ -5,40 +5,154 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"hash/fnv"
 	"html/template"
-	"os"
+	"io"
+	"math"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"unicode/utf8"
 
-	"github.com/golang/freetype/truetype"
 	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/text/unicode/bidi"
 )
 
 type bounds struct {
-	// SubjectDx is the width of subject string of the badge.
+	// SubjectDx is the width of subject string of the badge, including the logo.
 	SubjectDx float64
 	SubjectX  float64
+	// LogoDx is the width of the logo, zero when the badge has no logo.
+	LogoDx float64
+	LogoX  float64
+	// Gap is the space between the subject and status segments.
+	Gap float64
 	// StatusDx is the width of status string of the badge.
 	StatusDx float64
 	StatusX  float64
+	// SubjectTextDx and StatusTextDx compress text to a textLength when
+	// non-zero.
+	SubjectTextDx float64
+	StatusTextDx  float64
+	// Mirrored places the subject segment on the right for RTL badges.
+	Mirrored bool
+	// FillX and FillDx are the filled part of a progress bar, zero for other kinds.
+	FillX  float64
+	FillDx float64
+	// SpinnerX is the center of the AnimationSpinner ring, zero without one.
+	SpinnerX float64
+	// NotchX and NotchTipX are the arrow of the status bubble, zero for
+	// styles without one; see segmentBounds.
+	NotchX    float64
+	NotchTipX float64
+	// segments are the bounds of Badge.Segments in reading order, nil for
+	// badges with a subject and status. The subject and status fields then
+	// hold the first and last segments.
+	segments []segmentBounds
 }
 
 func (b bounds) Dx() float64 {
-	return b.SubjectDx + b.StatusDx
+	if len(b.segments) > 0 {
+		dx := b.Gap * float64(len(b.segments)-1)
+		for _, s := range b.segments {
+			dx += s.Dx
+		}
+		return dx
+	}
+	return b.SubjectDx + b.Gap + b.StatusDx
+}
+
+// SubjectStart is the x offset where the subject segment begins.
+func (b bounds) SubjectStart() float64 {
+	if len(b.segments) > 0 {
+		return b.segments[0].Start
+	}
+	if b.Mirrored {
+		return b.StatusDx + b.Gap
+	}
+	return 0
+}
+
+// StatusStart is the x offset where the status segment begins.
+func (b bounds) StatusStart() float64 {
+	if n := len(b.segments); n > 0 {
+		return b.segments[n-1].Start
+	}
+	if b.Mirrored {
+		return 0
+	}
+	return b.SubjectDx + b.Gap
 }
 
 type badgeTemplateData struct {
-	Subject string
-	Status  string
-	Color   string
-	ID      string
-	Bounds  bounds
+	Subject    string
+	Status     string
+	Color      string
+	LabelColor string
+	// ColorOpacity and LabelColorOpacity hold the alpha of translucent
+	// colors, which Color and LabelColor then hold without it; see
+	// splitOpacity. They are empty for opaque colors.
+	ColorOpacity      string
+	LabelColorOpacity string
+	Logo              template.URL
+	FontFamily        string
+	// FontSize is the text size in pixels; FontWeight is "bold" for bold
+	// text and empty otherwise.
+	FontSize   float64
+	FontWeight string
+	// Width and Height are the outer size of the badge after scaling.
+	Width  float64
+	Height float64
+	// Title is the accessible name of the badge.
+	Title string
+	// SubjectLink and StatusLink are the segment link targets, empty when unlinked.
+	SubjectLink string
+	StatusLink  string
+	// SubjectRTL and StatusRTL mark text whose base direction is right to left.
+	SubjectRTL bool
+	StatusRTL  bool
+	// The text and shadow colors contrast with the fill of their segment.
+	SubjectTextColor   string
+	SubjectShadowColor string
+	StatusTextColor    string
+	StatusShadowColor  string
+	SubjectTextOpacity string
+	StatusTextOpacity  string
+	// CSS holds the style rules of the dark palette and the animation, empty
+	// without either.
+	CSS template.CSS
+	// TextPaths is set when SubjectPath and StatusPath replace <text>; see
+	// Renderer.SetTextPaths.
+	TextPaths   bool
+	SubjectPath string
+	StatusPath  string
+	// Segments are the segments of badges with Badge.Segments, nil otherwise.
+	Segments []templateSegment
+	ID       string
+	Bounds   bounds
 }
 
 type Renderer struct {
-	fd    *font.Drawer
-	tmpls map[Style]*template.Template
-	mutex *sync.Mutex
+	text measurer
+	// families are the font family names of the fallback chain, when known.
+	families []string
+	// raster draws PNG text for renderers without font files; see rasterChain.
+	raster func() (*fontMeasurer, error)
+	tmpls  map[Style]*styleTemplate
+	// progress holds the KindProgress templates and segments those of
+	// Badge.Segments, which are not replaceable.
+	progress map[Style]*styleTemplate
+	segments map[Style]*styleTemplate
+	// stylesMutex guards tmpls against RegisterStyle.
+	stylesMutex *sync.RWMutex
+	cache       *svgCache
+	buffers     *sync.Pool
+	// paths is set by SetTextPaths.
+	paths *atomic.Bool
+	// fonts holds the faces of RegisterFont; face is set on the renderers
+	// that draw with one of them.
+	fonts *fontRegistry
+	face  *namedFace
 }
 
 // shield.io uses Verdana.ttf to measure text width with an extra 10px.
@@ -51,110 +165,666 @@ const (
 	measureShift = 6
 )
 
+// boldWidthFactor approximates the extra advance of bold text, since only
+// the regular face is loaded.
+const boldWidthFactor = 1.1
+
+// NewRenderer measures text with the font at fontPath.
 func NewRenderer(fontPath string) (*Renderer, error) {
-	if fontPath == "" {
+	return NewRendererWithFontPaths(fontPath)
+}
+
+// NewRendererWithFontPaths measures text with an ordered fallback chain of
+// fonts. Each rune is measured with the first font that has its glyph. Fonts
+// may be TrueType or CFF-based OpenType files, TTC/OTC collections or WOFF
+// files, and a path may select a face of a collection as with SplitFontIndex.
+func NewRendererWithFontPaths(fontPaths ...string) (*Renderer, error) {
+	if len(fontPaths) == 0 {
 		return nil, errors.New("font path is required")
 	}
-	fontBytes, err := os.ReadFile(fontPath)
+	files, err := readFontPaths(fontPaths)
 	if err != nil {
 		return nil, err
 	}
-	fd, err := newFontDrawerFromBytes(fontBytes, fontsize, dpi)
+	return newRendererFromFonts(files)
+}
+
+func NewRendererWithFontFace(face font.Face) (*Renderer, error) {
+	return NewRendererWithFontFaces([]font.Face{face})
+}
+
+// NewRendererWithFontFaces measures text with an ordered fallback chain of faces.
+// Each rune is measured with the first face that reports a glyph for it.
+func NewRendererWithFontFaces(faces []font.Face) (*Renderer, error) {
+	if len(faces) == 0 {
+		return nil, errors.New("font face is required")
+	}
+	chain := make([]fontFace, 0, len(faces))
+	for _, face := range faces {
+		if face == nil {
+			return nil, errors.New("font face is required")
+		}
+		chain = append(chain, newFontFace(face))
+	}
+	return newRenderer(newFontMeasurer(chain), nil)
+}
+
+// NewVerdanaRenderer returns a renderer that measures text with embedded
+// Verdana 11px width tables, so no font file is needed and widths match shields.io.
+func NewVerdanaRenderer() (*Renderer, error) {
+	text, err := newVerdanaMeasurer()
 	if err != nil {
 		return nil, err
 	}
+	return newRenderer(text, nil)
+}
+
+// NewDefaultRenderer returns a renderer that measures and draws text with Go
+// Regular, embedded in the package, so no font file is needed and widths,
+// PNG text and text paths all come from the same font, kerning included.
+func NewDefaultRenderer() (*Renderer, error) {
+	return newRendererFromFonts([]fontData{{name: "Go Regular", data: goregular.TTF}})
+}
+
+func newRenderer(text measurer, families []string) (*Renderer, error) {
 	tmpls, err := parseTemplates()
 	if err != nil {
 		return nil, err
 	}
-	return &Renderer{
-		fd:    fd,
-		tmpls: tmpls,
-		mutex: &sync.Mutex{},
-	}, nil
-}
-
-func NewRendererWithFontFace(face font.Face) (*Renderer, error) {
-	if face == nil {
-		return nil, errors.New("font face is required")
+	progress, err := parseProgressTemplates()
+	if err != nil {
+		return nil, err
 	}
-	tmpls, err := parseTemplates()
+	segments, err := parseSegmentTemplates()
 	if err != nil {
 		return nil, err
 	}
 	return &Renderer{
-		fd:    &font.Drawer{Face: face},
-		tmpls: tmpls,
-		mutex: &sync.Mutex{},
+		text:        text,
+		families:    families,
+		tmpls:       tmpls,
+		progress:    progress,
+		segments:    segments,
+		raster:      sync.OnceValues(newRasterMeasurer),
+		stylesMutex: &sync.RWMutex{},
+		cache:       newSVGCache(DefaultCacheSize),
+		buffers:     &sync.Pool{New: func() any { return &renderBuffer{} }},
+		paths:       &atomic.Bool{},
+		fonts:       newFontRegistry(),
 	}, nil
 }
 
+// Render returns the badge as SVG. Renders are safe for concurrent use, and
+// recent outputs are cached; see SetCacheSize.
 func (r *Renderer) Render(b Badge) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := r.RenderTo(buf, b); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderTo writes the badge as SVG to w, with the same output as Render.
+// Built-in styles are written by templates compiled at construction rather
+// than executed per call, and cached badges are written without allocating.
+func (r *Renderer) RenderTo(w io.Writer, b Badge) error {
 	if r == nil {
-		return nil, errors.New("renderer is nil")
+		return errors.New("renderer is nil")
+	}
+	key := newCacheKey(b)
+	if svg, ok := r.cache.get(key); ok {
+		_, err := w.Write(svg)
+		return err
+	}
+	generation := r.cache.generation.Load()
+	p, err := r.prepare(b)
+	if err != nil {
+		return err
+	}
+	buf, _ := r.buffers.Get().(*renderBuffer)
+	defer r.putBuffer(buf)
+	if buf.out, err = p.tmpl.appendTo(buf.out[:0], &p.data, &buf.scratch); err != nil {
+		return err
+	}
+	r.cache.put(key, bytes.Clone(buf.out), generation)
+	_, err = w.Write(buf.out)
+	return err
+}
+
+// renderBuffer holds the output and escaper scratch space of one render.
+type renderBuffer struct {
+	out     []byte
+	scratch [2][]byte
+}
+
+// maxPooledBuffer keeps buffers grown by unusually large badges out of the pool.
+const maxPooledBuffer = 64 << 10
+
+func (r *Renderer) putBuffer(buf *renderBuffer) {
+	if cap(buf.out) > maxPooledBuffer || cap(buf.scratch[0]) > maxPooledBuffer || cap(buf.scratch[1]) > maxPooledBuffer {
+		return
+	}
+	r.buffers.Put(buf)
+}
+
+// preparedBadge is a validated badge laid out for drawing.
+type preparedBadge struct {
+	style   Style
+	tmpl    *styleTemplate
+	metrics styleMetrics
+	data    badgeTemplateData
+}
+
+// prepare validates b, measures its text and computes the template data.
+func (r *Renderer) prepare(b Badge) (preparedBadge, error) {
+	if r == nil {
+		return preparedBadge{}, errors.New("renderer is nil")
 	}
 	if !b.Color.IsValid() {
-		return nil, fmt.Errorf("invalid color: %q", b.Color)
+		return preparedBadge{}, fmt.Errorf("invalid color: %q", b.Color)
+	}
+	if !b.LabelColor.IsValid() {
+		return preparedBadge{}, fmt.Errorf("invalid label color: %q", b.LabelColor)
+	}
+	if !b.TextColor.IsValid() {
+		return preparedBadge{}, fmt.Errorf("invalid text color: %q", b.TextColor)
+	}
+	if err := b.Dark.validate(); err != nil {
+		return preparedBadge{}, err
+	}
+	if !b.Animation.IsValid() {
+		return preparedBadge{}, fmt.Errorf("invalid animation: %q", b.Animation)
+	}
+	if !b.FontWeight.IsValid() {
+		return preparedBadge{}, fmt.Errorf("invalid font weight: %q", b.FontWeight)
+	}
+	if !ValidFontSize(b.FontSize) {
+		return preparedBadge{}, fmt.Errorf("invalid font size: %v", b.FontSize)
+	}
+	fr, err := r.fontRenderer(b)
+	if err != nil {
+		return preparedBadge{}, err
+	}
+	if fr != r {
+		return fr.prepare(b)
 	}
 	style := b.Style
 	if style == "" {
 		style = StyleFlat
 	}
-	if !style.IsValid() {
-		return nil, fmt.Errorf("invalid style: %q", style)
-	}
-	tmpl, ok := r.tmpls[style]
+	tmpl, ok := r.template(style)
 	if !ok {
-		return nil, fmt.Errorf("missing template for style: %q", style)
+		return preparedBadge{}, fmt.Errorf("invalid style: %q", style)
+	}
+	if !b.Kind.IsValid() {
+		return preparedBadge{}, fmt.Errorf("invalid kind: %q", b.Kind)
+	}
+	if err = validateSegments(b); err != nil {
+		return preparedBadge{}, err
+	}
+	if len(b.Segments) > 0 {
+		if tmpl, err = r.segmentsTemplate(style, tmpl); err != nil {
+			return preparedBadge{}, err
+		}
+	}
+	if b.Kind == KindProgress {
+		if tmpl, err = r.progressTemplate(style, b.Progress); err != nil {
+			return preparedBadge{}, err
+		}
+		if b.Status == "" {
+			b.Status = progressStatus(b.Progress)
+		}
+	}
+	if b.MaxWidth < 0 {
+		return preparedBadge{}, fmt.Errorf("invalid max width: %d", b.MaxWidth)
+	}
+	if !b.Overflow.IsValid() {
+		return preparedBadge{}, fmt.Errorf("invalid overflow: %q", b.Overflow)
+	}
+	if !ValidIDPrefix(b.IDPrefix) {
+		return preparedBadge{}, fmt.Errorf("invalid id prefix: %q", b.IDPrefix)
+	}
+	scale, err := badgeScale(b.Scale)
+	if err != nil {
+		return preparedBadge{}, err
+	}
+	for _, link := range b.Links {
+		if !ValidLink(link) {
+			return preparedBadge{}, fmt.Errorf("invalid link: %q", link)
+		}
 	}
-	resolvedColor := b.Color.String()
-	templateID := renderTemplateID(style, b.Subject, b.Status)
-	r.mutex.Lock()
-	subjectDx := r.measureString(b.Subject)
-	statusDx := r.measureString(b.Status)
-	r.mutex.Unlock()
+	logo, logoDx, err := resolveLogo(b)
+	if err != nil {
+		return preparedBadge{}, err
+	}
+	metrics := style.metrics()
+	metrics.scale = scale
+	r.applyFont(b, &metrics)
+	if len(b.Segments) > 0 {
+		return r.prepareSegmented(b, style, tmpl, metrics)
+	}
+	subject, status, err := r.measureSegments(b, metrics, logoDx)
+	if err != nil {
+		return preparedBadge{}, err
+	}
+	subjectDir, statusDir := baseDirection(subject.text), baseDirection(status.text)
+	bounds := layout(metrics, subject.dx, status.dx, logoDx, isRTLBadge(subjectDir, statusDir))
+	bounds.SubjectTextDx, bounds.StatusTextDx = subject.textDx, status.textDx
+	if b.Animation == AnimationSpinner {
+		placeSpinner(&bounds, metrics)
+	}
+	if b.Kind == KindProgress {
+		bounds.FillX, bounds.FillDx = progressFill(bounds, b.Progress)
+	}
+	labelColor, color := segmentColors(b, metrics)
+	subjectText, subjectShadow := textColors(labelColor, b.TextColor, metrics)
+	statusText, statusShadow := textColors(color, b.TextColor, metrics)
 
 	renderData := badgeTemplateData{
-		Subject: b.Subject,
-		Status:  b.Status,
-		Color:   resolvedColor,
-		ID:      templateID,
-		Bounds: bounds{
-			SubjectDx: subjectDx,
-			SubjectX:  subjectDx/2.0 + 1,
-			StatusDx:  statusDx,
-			StatusX:   subjectDx + statusDx/2.0 - 1,
-		},
+		Subject:            subject.text,
+		Status:             status.text,
+		Color:              b.Color.String(),
+		LabelColor:         b.LabelColor.String(),
+		Logo:               logo,
+		FontFamily:         r.fontFamily(metrics),
+		FontSize:           fontSize(metrics),
+		FontWeight:         fontWeightAttr(b.FontWeight),
+		Width:              scaled(bounds.Dx(), scale),
+		Height:             scaled(metrics.height, scale),
+		Title:              badgeTitle(b),
+		SubjectLink:        b.Links[0],
+		StatusLink:         b.Links[1],
+		SubjectRTL:         subjectDir == bidi.RightToLeft,
+		StatusRTL:          statusDir == bidi.RightToLeft,
+		SubjectTextColor:   subjectText,
+		SubjectShadowColor: subjectShadow,
+		StatusTextColor:    statusText,
+		StatusShadowColor:  statusShadow,
+		Bounds:             bounds,
 	}
-	buf := &bytes.Buffer{}
-	if err := tmpl.Execute(buf, renderData); err != nil {
-		return nil, err
+	renderData.splitOpacity()
+	if r.paths.Load() {
+		if err = r.textPaths(&renderData, metrics); err != nil {
+			return preparedBadge{}, err
+		}
 	}
-	return buf.Bytes(), nil
+	// The rules are hashed into the id before they are scoped to it.
+	renderData.CSS = badgeCSS(b, metrics, "")
+	renderData.ID = renderTemplateID(style, b.IDPrefix, &renderData)
+	if renderData.CSS != "" {
+		renderData.CSS = badgeCSS(b, metrics, renderData.ID)
+	}
+	return preparedBadge{style: style, tmpl: tmpl, metrics: metrics, data: renderData}, nil
 }
 
-func (r *Renderer) measureString(s string) float64 {
-	return float64(r.fd.MeasureString(s)>>measureShift) + extraDx
+// prepareSegmented computes the template data of a badge with Badge.Segments.
+func (r *Renderer) prepareSegmented(b Badge, style Style, tmpl *styleTemplate, m styleMetrics) (preparedBadge, error) {
+	segments, bounds, err := r.prepareSegments(b, m)
+	if err != nil {
+		return preparedBadge{}, err
+	}
+	last := segments[len(segments)-1]
+	renderData := badgeTemplateData{
+		Color:             b.Color.String(),
+		LabelColor:        b.LabelColor.String(),
+		FontFamily:        r.fontFamily(m),
+		FontSize:          fontSize(m),
+		FontWeight:        fontWeightAttr(b.FontWeight),
+		Width:             scaled(bounds.Dx(), m.scale),
+		Height:            scaled(m.height, m.scale),
+		Title:             badgeTitle(b),
+		StatusTextColor:   last.TextColor,
+		StatusShadowColor: last.ShadowColor,
+		Segments:          segments,
+		Bounds:            bounds,
+	}
+	renderData.splitOpacity()
+	if r.paths.Load() {
+		if err = r.textPaths(&renderData, m); err != nil {
+			return preparedBadge{}, err
+		}
+	}
+	renderData.CSS = badgeCSS(b, m, "")
+	renderData.ID = renderTemplateID(style, b.IDPrefix, &renderData)
+	if renderData.CSS != "" {
+		renderData.CSS = badgeCSS(b, m, renderData.ID)
+	}
+	return preparedBadge{style: style, tmpl: tmpl, metrics: m, data: renderData}, nil
 }
 
-func renderTemplateID(style Style, subject, status string) string {
-	hasher := fnv.New64a()
-	buf := fmt.Appendf(nil, "%s|%s|%s", style, subject, status)
-	_, _ = hasher.Write(buf)
-	sum := hasher.Sum(nil)
-	return hex.EncodeToString(sum[:4])
+// segmentsTemplate returns the template that draws Badge.Segments in style,
+// whose template is tmpl: tmpl itself when it ranges over .Segments, or the
+// built-in segments template of the style.
+func (r *Renderer) segmentsTemplate(style Style, tmpl *styleTemplate) (*styleTemplate, error) {
+	if tmpl.segments {
+		return tmpl, nil
+	}
+	if segments, ok := r.segments[style]; ok {
+		return segments, nil
+	}
+	return nil, fmt.Errorf("segments are not supported for style: %q", style)
 }
 
-func newFontDrawerFromBytes(ttfBytes []byte, size, dpi float64) (*font.Drawer, error) {
-	ttf, err := truetype.Parse(ttfBytes)
-	if err != nil {
-		return nil, err
+// badgeCSS returns the style rules of the dark palette and the animation of b,
+// scoped to the badge with the id so that inline SVGs on one page keep their
+// own. It is empty when b has neither.
+func badgeCSS(b Badge, m styleMetrics, id string) template.CSS {
+	scope := "#badge-" + id + " ."
+	css := b.Animation.rules(scope)
+	switch {
+	case b.Dark.IsZero():
+	case len(b.Segments) > 0:
+		css = darkSegmentRules(b, m, scope) + css
+	default:
+		css = darkRules(b, m, scope) + css
 	}
-	return &font.Drawer{
-		Face: truetype.NewFace(ttf, &truetype.Options{
-			Size:    size,
-			DPI:     dpi,
-			Hinting: font.HintingFull,
-		}),
-	}, nil
+	return template.CSS(css) //nolint:gosec // fixed class names, validated ids and hex colors
+}
+
+// splitOpacity moves the alpha of translucent colors into the opacity fields,
+// leaving paints that SVG 1.1 accepts in the color fields.
+func (d *badgeTemplateData) splitOpacity() {
+	d.Color, d.ColorOpacity = svgPaint(d.Color)
+	d.LabelColor, d.LabelColorOpacity = svgPaint(d.LabelColor)
+	d.SubjectTextColor, d.SubjectTextOpacity = svgPaint(d.SubjectTextColor)
+	d.StatusTextColor, d.StatusTextOpacity = svgPaint(d.StatusTextColor)
+	for i := range d.Segments {
+		seg := &d.Segments[i]
+		seg.Color, seg.ColorOpacity = svgPaint(seg.Color)
+		seg.TextColor, seg.TextOpacity = svgPaint(seg.TextColor)
+	}
+}
+
+// segmentColors returns the fills of the subject and status segments as
+// drawn by a template with the metrics m.
+func segmentColors(b Badge, m styleMetrics) (Color, Color) {
+	labelColor, color := b.LabelColor, b.Color
+	if labelColor == "" {
+		labelColor = m.labelColor
+	}
+	if color == "" || m.fixedColor {
+		color = m.color
+	}
+	return labelColor, color
+}
+
+// measureSegments measures the subject and status as displayed by the style
+// and fits them into b.MaxWidth. The status makes room for a spinner.
+func (r *Renderer) measureSegments(b Badge, m styleMetrics, logoDx float64) (segment, segment, error) {
+	subject := segment{text: b.Subject, bold: m.boldSubject}
+	status := segment{text: b.Status, bold: m.boldStatus}
+	if m.uppercase {
+		subject.text, status.text = strings.ToUpper(subject.text), strings.ToUpper(status.text)
+	}
+	subject.dx = r.measureText(subject.text, m, subject.bold)
+	status.dx = r.measureText(status.text, m, status.bold)
+	if b.MaxWidth > 0 {
+		budget := float64(b.MaxWidth) - logoShift(logoDx) - b.Animation.shift() - m.gap
+		if minDx, ok := r.fitSegments(b.Overflow, budget, m, &subject, &status); !ok {
+			return segment{}, segment{}, maxWidthError(b.MaxWidth, budget, minDx)
+		}
+	}
+	status.dx += b.Animation.shift()
+	return subject, status, nil
+}
+
+// ErrMaxWidthTooSmall is returned for a Badge.MaxWidth below the narrowest
+// width the badge can be drawn at with its overflow policy.
+var ErrMaxWidthTooSmall = errors.New("max width too small")
+
+// maxWidthError reports a max width below what a badge needs at the least,
+// when its segments need minDx of budget.
+func maxWidthError(maxWidth int, budget, minDx float64) error {
+	return fmt.Errorf("%w: %d is below the minimum %g of the badge", ErrMaxWidthTooSmall, maxWidth, math.Ceil(float64(maxWidth)-budget+minDx))
+}
+
+func resolveLogo(b Badge) (template.URL, float64, error) {
+	if b.Logo == "" {
+		return "", 0, nil
+	}
+	if err := b.Logo.Validate(); err != nil {
+		return "", 0, fmt.Errorf("invalid logo: %w", err)
+	}
+	if !b.LogoColor.IsValid() {
+		return "", 0, fmt.Errorf("invalid logo color: %q", b.LogoColor)
+	}
+	if b.LogoWidth < 0 || b.LogoWidth > MaxLogoWidth {
+		return "", 0, fmt.Errorf("invalid logo width: %d", b.LogoWidth)
+	}
+	width := b.LogoWidth
+	if width == 0 {
+		width = logoDefaultWidth
+	}
+	color := b.LogoColor.String()
+	if color == "" {
+		color = logoDefaultColor
+	}
+	uri, ok := b.Logo.dataURI(color)
+	if !ok {
+		return "", 0, fmt.Errorf("invalid logo: %q", b.Logo)
+	}
+	return uri, float64(width), nil
+}
+
+// layout positions the subject and status given the padded text widths.
+// Mirrored badges put the status on the left and the logo at the right edge.
+func layout(m styleMetrics, subjectDx, statusDx, logoDx float64, mirrored bool) bounds {
+	segments := layoutSegments(m, []float64{subjectDx, statusDx}, []float64{logoDx, 0}, mirrored)
+	subject, status := segments[0], segments[1]
+	return bounds{
+		SubjectDx: subject.Dx,
+		SubjectX:  subject.X,
+		LogoDx:    logoDx,
+		LogoX:     subject.LogoX,
+		Gap:       m.gap,
+		StatusDx:  status.Dx,
+		StatusX:   status.X,
+		Mirrored:  mirrored,
+		NotchX:    status.NotchX,
+		NotchTipX: status.NotchTipX,
+	}
+}
+
+// logoShift is the room taken by a logo in the subject segment.
+func logoShift(logoDx float64) float64 {
+	if logoDx > 0 {
+		return logoDx + logoPadding
+	}
+	return 0
+}
+
+// MeasureText returns the width of s in pixels at font size size, measured
+// as badge text is: each rune with the first font of the chain that has its
+// glyph, kerned against its neighbors in the same font. Badge segments add
+// the style padding to this width, rounded up.
+func (r *Renderer) MeasureText(s string, size float64) float64 {
+	if size <= 0 {
+		return 0
+	}
+	width := 0.0
+	for _, run := range bidiRuns(s) {
+		width += r.text.measure(run, false, size)
+	}
+	return width
+}
+
+// measureText returns the segment width of s including the style padding.
+func (r *Renderer) measureText(s string, m styleMetrics, bold bool) float64 {
+	width := 0.0
+	for _, run := range bidiRuns(s) {
+		width += r.text.measure(run, bold, fontsize*m.scale)
+	}
+	width *= m.fontScale / m.scale
+	width += m.letterSpacing * float64(utf8.RuneCountInString(s))
+	return math.Ceil(width) + m.padding
+}
+
+// renderTemplateID hashes the style and everything it draws, so only badges
+// with identical output share gradient and mask ids, and prepends prefix.
+// The fields are written to the hash one by one, so it allocates only the id.
+func renderTemplateID(style Style, prefix string, data *badgeTemplateData) string {
+	h := newIDHash()
+	h.string(string(style))
+	h.string(data.Subject)
+	h.string(data.Status)
+	h.string(data.Color)
+	h.string(data.LabelColor)
+	h.string(data.ColorOpacity)
+	h.string(data.LabelColorOpacity)
+	h.string(string(data.Logo))
+	h.string(data.FontFamily)
+	h.float(data.FontSize)
+	h.string(data.FontWeight)
+	h.float(data.Width)
+	h.float(data.Height)
+	h.string(data.Title)
+	h.string(data.SubjectLink)
+	h.string(data.StatusLink)
+	h.bool(data.SubjectRTL)
+	h.bool(data.StatusRTL)
+	h.string(data.SubjectTextColor)
+	h.string(data.SubjectShadowColor)
+	h.string(data.StatusTextColor)
+	h.string(data.StatusShadowColor)
+	h.string(data.SubjectTextOpacity)
+	h.string(data.StatusTextOpacity)
+	h.string(string(data.CSS))
+	h.bool(data.TextPaths)
+	h.string(data.SubjectPath)
+	h.string(data.StatusPath)
+	h.uint(uint64(len(data.Segments)))
+	for i := range data.Segments {
+		seg := &data.Segments[i]
+		h.string(seg.Text)
+		h.string(seg.Color)
+		h.string(seg.TextColor)
+		h.string(seg.ShadowColor)
+		h.string(seg.ColorOpacity)
+		h.string(seg.TextOpacity)
+		h.string(seg.Link)
+		h.string(string(seg.Logo))
+		h.bool(seg.RTL)
+		h.bool(seg.Bold)
+		h.bool(seg.Label)
+		h.string(seg.Path)
+		h.string(seg.Class)
+		h.segmentBounds(seg.Bounds)
+	}
+	bounds := &data.Bounds
+	h.float(bounds.SubjectDx)
+	h.float(bounds.SubjectX)
+	h.float(bounds.LogoDx)
+	h.float(bounds.LogoX)
+	h.float(bounds.Gap)
+	h.float(bounds.StatusDx)
+	h.float(bounds.StatusX)
+	h.float(bounds.SubjectTextDx)
+	h.float(bounds.StatusTextDx)
+	h.bool(bounds.Mirrored)
+	h.float(bounds.FillX)
+	h.float(bounds.FillDx)
+	h.float(bounds.SpinnerX)
+	h.float(bounds.NotchX)
+	h.float(bounds.NotchTipX)
+	h.uint(uint64(len(bounds.segments)))
+	for _, sb := range bounds.segments {
+		h.segmentBounds(sb)
+	}
+
+	var buf [64]byte
+	id := buf[:0]
+	if prefix != "" {
+		id = append(append(id, prefix...), '-')
+	}
+	sum := uint32(h >> 32)
+	id = hex.AppendEncode(id, []byte{byte(sum >> 24), byte(sum >> 16), byte(sum >> 8), byte(sum)})
+	return string(id)
+}
+
+// idHash is a 64-bit FNV-1a hash written field by field without allocating.
+type idHash uint64
+
+func newIDHash() idHash {
+	return 14695981039346656037
+}
+
+func (h *idHash) byte(c byte) {
+	*h = (*h ^ idHash(c)) * 1099511628211
+}
+
+func (h *idHash) uint(v uint64) {
+	for range 8 {
+		h.byte(byte(v))
+		v >>= 8
+	}
+}
+
+// string writes the length of s before it, so adjacent fields cannot run
+// into each other.
+func (h *idHash) string(s string) {
+	h.uint(uint64(len(s)))
+	for i := range len(s) {
+		h.byte(s[i])
+	}
+}
+
+func (h *idHash) float(v float64) {
+	h.uint(math.Float64bits(v))
+}
+
+func (h *idHash) segmentBounds(b segmentBounds) {
+	h.float(b.Start)
+	h.float(b.Dx)
+	h.float(b.X)
+	h.float(b.TextDx)
+	h.float(b.LogoX)
+	h.float(b.LogoDx)
+	h.float(b.NotchX)
+	h.float(b.NotchTipX)
+}
+
+func (h *idHash) bool(v bool) {
+	if v {
+		h.byte(1)
+	} else {
+		h.byte(0)
+	}
+}
+
+// ValidIDPrefix reports whether prefix may be used as Badge.IDPrefix: ASCII
+// letters, digits, hyphens and underscores.
+func ValidIDPrefix(prefix string) bool {
+	for _, c := range prefix {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') && c != '-' && c != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// badgeScale validates Badge.Scale, where zero means 1.
+func badgeScale(scale float64) (float64, error) {
+	if scale == 0 {
+		return 1, nil
+	}
+	if !(scale > 0 && scale <= MaxScale) {
+		return 0, fmt.Errorf("invalid scale: %v", scale)
+	}
+	return scale, nil
+}
+
+// scaled multiplies v by scale and rounds to hundredths of a pixel.
+func scaled(v, scale float64) float64 {
+	const precision = 100
+	return math.Round(v*scale*precision) / precision
+}
+
+func badgeTitle(b Badge) string {
+	if b.Title != "" {
+		return b.Title
+	}
+	if len(b.Segments) > 0 {
+		return segmentsTitle(b)
+	}
+	return b.Subject + ": " + b.Status
 }
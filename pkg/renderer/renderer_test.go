@@ -1,14 +1,24 @@
 package renderer_test
 
 import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"math"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/rhajizada/signum/pkg/renderer"
+	"golang.org/x/image/font"
 	"golang.org/x/image/font/basicfont"
 	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
 )
 
 func newRenderer(tb testing.TB) *renderer.Renderer {
@@ -20,6 +30,28 @@ func newRenderer(tb testing.TB) *renderer.Renderer {
 	return r
 }
 
+// newFace returns f as an 11px face.
+func newFace(f *opentype.Font) font.Face {
+	// opentype.NewFace never fails.
+	face, _ := opentype.NewFace(f, &opentype.FaceOptions{Size: 11, DPI: 72})
+	return face
+}
+
+// badgeWidth returns the width attribute of the root svg element.
+func badgeWidth(tb testing.TB, svg string) float64 {
+	tb.Helper()
+	_, rest, ok := strings.Cut(svg, `width="`)
+	if !ok {
+		tb.Fatalf("missing width in output: %s", svg)
+	}
+	value, _, _ := strings.Cut(rest, `"`)
+	width, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		tb.Fatalf("parse width %q: %v", value, err)
+	}
+	return width
+}
+
 func TestRendererRender(t *testing.T) {
 	r := newRenderer(t)
 	badge := renderer.Badge{
@@ -78,6 +110,133 @@ func TestNewRendererValidFontBytes(t *testing.T) {
 	}
 }
 
+func TestNewRendererWithFontFacesFallback(t *testing.T) {
+	if _, err := renderer.NewRendererWithFontFaces(nil); err == nil {
+		t.Fatalf("expected error for empty face list")
+	}
+	if _, err := renderer.NewRendererWithFontFaces([]font.Face{basicfont.Face7x13, nil}); err == nil {
+		t.Fatalf("expected error for nil face")
+	}
+
+	wide := &basicfont.Face{
+		Advance: 20,
+		Width:   7,
+		Height:  13,
+		Ascent:  11,
+		Descent: 2,
+		Mask:    basicfont.Face7x13.Mask,
+		Ranges:  []basicfont.Range{{Low: '日', High: '日' + 1}},
+	}
+	single := newRenderer(t)
+	chain, err := renderer.NewRendererWithFontFaces([]font.Face{basicfont.Face7x13, wide})
+	if err != nil {
+		t.Fatalf("new renderer: %v", err)
+	}
+	badge := renderer.Badge{Subject: "ab日", Status: "ok", Color: renderer.ColorGreen}
+	singleOutput, err := single.Render(badge)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chainOutput, err := chain.Render(badge)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(singleOutput), `<rect x="0" width="34"`) {
+		t.Fatalf("expected missing glyph to use the primary face: %s", singleOutput)
+	}
+	if !strings.Contains(string(chainOutput), `<rect x="0" width="47"`) {
+		t.Fatalf("expected fallback face to measure missing glyph: %s", chainOutput)
+	}
+}
+
+func TestNewRendererWithFontPathsFamilies(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "goregular.ttf")
+	if err := os.WriteFile(path, goregular.TTF, 0o600); err != nil {
+		t.Fatalf("write temp font: %v", err)
+	}
+	if _, err := renderer.NewRendererWithFontPaths(); err == nil {
+		t.Fatalf("expected error for empty path list")
+	}
+	if _, err := renderer.NewRendererWithFontPaths(path, filepath.Join(dir, "missing.ttf")); err == nil {
+		t.Fatalf("expected error for missing fallback font")
+	}
+	r, err := renderer.NewRendererWithFontPaths(path, path)
+	if err != nil {
+		t.Fatalf("new renderer: %v", err)
+	}
+	output, err := r.Render(renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(output), `font-family="Go,DejaVu Sans,Verdana,Geneva,sans-serif"`) {
+		t.Fatalf("expected font family list in output: %s", output)
+	}
+}
+
+func TestNewVerdanaRenderer(t *testing.T) {
+	r, err := renderer.NewVerdanaRenderer()
+	if err != nil {
+		t.Fatalf("new verdana renderer: %v", err)
+	}
+	output, err := r.Render(renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if width := badgeWidth(t, string(output)); width != 95 {
+		t.Fatalf("expected width 95, got %v", width)
+	}
+
+	bold, err := r.Render(renderer.Badge{
+		Subject: "build",
+		Status:  "passing",
+		Color:   renderer.ColorGreen,
+		Style:   renderer.StyleSocial,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if badgeWidth(t, string(bold)) <= badgeWidth(t, string(output)) {
+		t.Fatalf("expected bold social badge to be wider")
+	}
+
+	unknown, err := r.Render(renderer.Badge{Subject: "日本", Status: "ok", Color: renderer.ColorGreen})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if width := badgeWidth(t, string(unknown)); width <= 2*13 {
+		t.Fatalf("expected unknown runes to use a fallback width, got %v", width)
+	}
+}
+
+func TestNewDefaultRenderer(t *testing.T) {
+	r, err := renderer.NewDefaultRenderer()
+	if err != nil {
+		t.Fatalf("new default renderer: %v", err)
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "goregular.ttf")
+	if err = os.WriteFile(path, goregular.TTF, 0o600); err != nil {
+		t.Fatalf("write temp font: %v", err)
+	}
+	file, err := renderer.NewRenderer(path)
+	if err != nil {
+		t.Fatalf("new renderer: %v", err)
+	}
+	badge := renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen}
+	output, err := r.Render(badge)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want, err := file.Render(badge)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(output, want) {
+		t.Fatalf("expected the embedded font to render as Go Regular\n got: %s\nwant: %s", output, want)
+	}
+}
+
 func TestRendererRenderInvalidColor(t *testing.T) {
 	r := newRenderer(t)
 	_, err := r.Render(renderer.Badge{
@@ -90,6 +249,62 @@ func TestRendererRenderInvalidColor(t *testing.T) {
 	}
 }
 
+func TestRendererRenderCSSColors(t *testing.T) {
+	r := newRenderer(t)
+	output, err := r.Render(renderer.Badge{
+		Subject:    "build",
+		Status:     "passing",
+		Color:      renderer.Color("rgb(0, 128, 0)"),
+		LabelColor: renderer.Color("hsla(0, 0%, 0%, 0.5)"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(output), `fill="#008000"`) {
+		t.Fatalf("expected canonical status color, got %s", output)
+	}
+	// SVG 1.1 has no #rrggbbaa, so the alpha goes to fill-opacity.
+	if !strings.Contains(string(output), `fill="#000" fill-opacity="0.502"`) {
+		t.Fatalf("expected translucent label color, got %s", output)
+	}
+
+	output, err = r.Render(renderer.Badge{
+		Subject:   "build",
+		Status:    "passing",
+		Color:     renderer.Color("#00800080"),
+		TextColor: renderer.Color("rgba(255, 255, 255, 0.25)"),
+		Animation: renderer.AnimationSpinner,
+		Logo:      "check",
+		LogoColor: renderer.Color("#ff000080"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		`fill="#008000" fill-opacity="0.502"`,
+		`fill="#fff" fill-opacity="0.251"`,
+		`stroke="#fff" stroke-opacity="0.251"`,
+	} {
+		if !strings.Contains(string(output), want) {
+			t.Fatalf("expected %s, got %s", want, output)
+		}
+	}
+	if strings.Contains(string(output), "#00800080") || strings.Contains(string(output), "#ffffff40") {
+		t.Fatalf("expected no 8-digit hex colors, got %s", output)
+	}
+	match := regexp.MustCompile(`;base64,([^"]+)`).FindSubmatch(output)
+	if match == nil {
+		t.Fatalf("expected a logo data URI, got %s", output)
+	}
+	logo, err := base64.StdEncoding.DecodeString(html.UnescapeString(string(match[1])))
+	if err != nil {
+		t.Fatalf("decode logo: %v", err)
+	}
+	if !bytes.Contains(logo, []byte(`<svg fill="#f00" fill-opacity="0.502" `)) {
+		t.Fatalf("expected a translucent logo fill, got %s", logo)
+	}
+}
+
 func TestRendererRenderInvalidStyle(t *testing.T) {
 	r := newRenderer(t)
 	_, err := r.Render(renderer.Badge{
@@ -143,6 +358,8 @@ func TestRendererRenderStyleVariants(t *testing.T) {
 		{name: "flat", style: renderer.StyleFlat, containString: "url(#smooth-"},
 		{name: "flat-square", style: renderer.StyleFlatSquare, containString: "url(#square-"},
 		{name: "plastic", style: renderer.StylePlastic, containString: "url(#shine-"},
+		{name: "for-the-badge", style: renderer.StyleForTheBadge, containString: `height="28"`},
+		{name: "social", style: renderer.StyleSocial, containString: `stroke="#d5d5d5"`},
 	}
 
 	for _, tc := range tests {
@@ -161,6 +378,330 @@ func TestRendererRenderStyleVariants(t *testing.T) {
 	}
 }
 
+func TestRendererRenderForTheBadge(t *testing.T) {
+	r := newRenderer(t)
+	flat, err := r.Render(renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	output, err := r.Render(renderer.Badge{
+		Subject: "build",
+		Status:  "passing",
+		Color:   renderer.ColorGreen,
+		Style:   renderer.StyleForTheBadge,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := string(output)
+	if !strings.Contains(result, ">BUILD<") || !strings.Contains(result, ">PASSING<") {
+		t.Fatalf("expected uppercase text in output: %s", result)
+	}
+	if strings.Contains(result, ">build<") {
+		t.Fatalf("expected no lowercase subject in output: %s", result)
+	}
+	if badgeWidth(t, result) <= badgeWidth(t, string(flat)) {
+		t.Fatalf("expected for-the-badge to be wider than flat")
+	}
+}
+
+func TestRendererRenderSocial(t *testing.T) {
+	r := newRenderer(t)
+	output, err := r.Render(renderer.Badge{
+		Subject: "stars",
+		Status:  "42",
+		Color:   renderer.ColorGreen,
+		Style:   renderer.StyleSocial,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := string(output)
+	if !strings.Contains(result, `font-weight="bold"`) {
+		t.Fatalf("expected bold text in output: %s", result)
+	}
+	if strings.Count(result, `rx="2"`) != 3 {
+		t.Fatalf("expected separate subject and status boxes: %s", result)
+	}
+}
+
+func TestStyles(t *testing.T) {
+	styles := renderer.Styles()
+	if len(styles) == 0 || styles[0] != renderer.StyleFlat {
+		t.Fatalf("expected flat to be the first style: %v", styles)
+	}
+	for _, style := range styles {
+		if !style.IsValid() {
+			t.Fatalf("expected %q to be valid", style)
+		}
+	}
+}
+
+func TestRendererRenderRTL(t *testing.T) {
+	r, err := renderer.NewVerdanaRenderer()
+	if err != nil {
+		t.Fatalf("new renderer: %v", err)
+	}
+	for _, style := range renderer.Styles() {
+		output, err := r.Render(renderer.Badge{
+			Subject: "בנייה",
+			Status:  "עבר",
+			Color:   renderer.ColorGreen,
+			Style:   style,
+			Logo:    "check",
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", style, err)
+		}
+		result := string(output)
+		if !strings.Contains(result, `direction="rtl" unicode-bidi="embed"`) {
+			t.Fatalf("%s: expected rtl text attributes: %s", style, result)
+		}
+		if strings.Contains(result, `<image x="5"`) || strings.Contains(result, `<image x="9"`) {
+			t.Fatalf("%s: expected logo on the right edge: %s", style, result)
+		}
+	}
+
+	output, err := r.Render(renderer.Badge{Subject: "בנייה", Status: "עבר", Color: renderer.ColorGreen})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(output), `<rect x="0" width="46" height="20" fill="#97ca00"/>`) {
+		t.Fatalf("expected status segment on the left: %s", output)
+	}
+}
+
+func TestRendererRenderMixedDirection(t *testing.T) {
+	mirroredStatus := regexp.MustCompile(`<rect x="0" width="[0-9.]+" height="20" fill="#97ca00"/>`)
+	r, err := renderer.NewVerdanaRenderer()
+	if err != nil {
+		t.Fatalf("new renderer: %v", err)
+	}
+	tests := []struct {
+		name     string
+		subject  string
+		status   string
+		rtlAttrs int
+		mirrored bool
+	}{
+		{name: "ltr with rtl word", subject: "build שלום", status: "passing", rtlAttrs: 0},
+		{name: "rtl subject ltr status", subject: "שלום", status: "passing", rtlAttrs: 2},
+		{name: "rtl with number", subject: "גרסה 2", status: "1.0", rtlAttrs: 2, mirrored: true},
+		{name: "rtl with ltr word", subject: "בנייה", status: "עבר ok", rtlAttrs: 4, mirrored: true},
+	}
+	for _, tc := range tests {
+		output, err := r.Render(renderer.Badge{Subject: tc.subject, Status: tc.status, Color: renderer.ColorGreen})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		result := string(output)
+		if got := strings.Count(result, `direction="rtl"`); got != tc.rtlAttrs {
+			t.Fatalf("%s: expected %d rtl attributes, got %d: %s", tc.name, tc.rtlAttrs, got, result)
+		}
+		if mirrored := mirroredStatus.MatchString(result); mirrored != tc.mirrored {
+			t.Fatalf("%s: expected mirrored=%v: %s", tc.name, tc.mirrored, result)
+		}
+	}
+}
+
+func TestRendererRenderMaxWidth(t *testing.T) {
+	r, err := renderer.NewVerdanaRenderer()
+	if err != nil {
+		t.Fatalf("new renderer: %v", err)
+	}
+	const status = "feature/very-long-branch-name-that-goes-on"
+	tests := []struct {
+		overflow renderer.Overflow
+		contains []string
+	}{
+		{overflow: "", contains: []string{">feat", "…<"}},
+		{overflow: renderer.OverflowTruncateEnd, contains: []string{">feat", "…<"}},
+		{overflow: renderer.OverflowTruncateMiddle, contains: []string{">feat", "…", "on<"}},
+		{overflow: renderer.OverflowShrink, contains: []string{">" + status + "<", `lengthAdjust="spacingAndGlyphs"`}},
+	}
+	for _, tc := range tests {
+		for _, style := range renderer.Styles() {
+			output, err := r.Render(renderer.Badge{
+				Subject:  "branch",
+				Status:   status,
+				Color:    renderer.ColorBlue,
+				Style:    style,
+				MaxWidth: 160,
+				Overflow: tc.overflow,
+			})
+			if err != nil {
+				t.Fatalf("%s/%s: unexpected error: %v", tc.overflow, style, err)
+			}
+			result := string(output)
+			if width := badgeWidth(t, result); width > 160 {
+				t.Fatalf("%s/%s: expected width <= 160, got %v", tc.overflow, style, width)
+			}
+			for _, want := range tc.contains {
+				if !strings.Contains(strings.ToLower(result), strings.ToLower(want)) {
+					t.Fatalf("%s/%s: expected output to contain %q: %s", tc.overflow, style, want, result)
+				}
+			}
+			if !strings.Contains(strings.ToLower(result), ">branch<") {
+				t.Fatalf("%s/%s: expected short subject to be kept: %s", tc.overflow, style, result)
+			}
+		}
+	}
+
+	natural, err := r.Render(renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	capped, err := r.Render(renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen, MaxWidth: 200})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(natural) != string(capped) {
+		t.Fatalf("expected badges within max width to be unchanged")
+	}
+}
+
+func TestRendererRenderMaxWidthBounds(t *testing.T) {
+	r, err := renderer.NewVerdanaRenderer()
+	if err != nil {
+		t.Fatalf("new renderer: %v", err)
+	}
+	minimum := regexp.MustCompile(`below the minimum (\d+) `)
+	for _, overflow := range renderer.Overflows() {
+		for _, style := range renderer.Styles() {
+			for maxWidth := 10; maxWidth <= 200; maxWidth += 7 {
+				b := renderer.Badge{
+					Subject:   "branch",
+					Status:    "feature/very-long-branch-name-that-goes-on",
+					Style:     style,
+					Logo:      "check",
+					Animation: renderer.AnimationSpinner,
+					MaxWidth:  maxWidth,
+					Overflow:  overflow,
+				}
+				output, renderErr := r.Render(b)
+				if renderErr != nil {
+					match := minimum.FindStringSubmatch(renderErr.Error())
+					if match == nil {
+						t.Fatalf("%s/%s/%d: unexpected error: %v", overflow, style, maxWidth, renderErr)
+					}
+					// The badge fits the minimum it reports.
+					b.MaxWidth, _ = strconv.Atoi(match[1])
+					if output, renderErr = r.Render(b); renderErr != nil {
+						t.Fatalf("%s/%s: render at the minimum %d: %v", overflow, style, b.MaxWidth, renderErr)
+					}
+				}
+				if width := badgeWidth(t, string(output)); width > float64(b.MaxWidth) {
+					t.Fatalf("%s/%s: expected width <= %d, got %v", overflow, style, b.MaxWidth, width)
+				}
+			}
+		}
+	}
+
+	// The status takes what the truncated subject leaves unused.
+	output, err := r.Render(renderer.Badge{
+		Subject:  "branch",
+		Status:   "feature/very-long-branch-name-that-goes-on",
+		MaxWidth: 60,
+		Overflow: renderer.OverflowTruncateMiddle,
+	})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if strings.Contains(string(output), ">…</text>") || badgeWidth(t, string(output)) > 60 {
+		t.Fatalf("expected the status to keep text within 60px: %s", output)
+	}
+}
+
+func TestRendererRenderInvalidMaxWidth(t *testing.T) {
+	r := newRenderer(t)
+	cases := []renderer.Badge{
+		{Subject: "a", Status: "b", MaxWidth: -1},
+		{Subject: "a", Status: "b", MaxWidth: 100, Overflow: "wrap"},
+		{Subject: "build", Status: "passing", MaxWidth: 10},
+		{Subject: "build", Status: "passing", MaxWidth: 30, Overflow: renderer.OverflowShrink},
+	}
+	for _, badge := range cases {
+		if _, err := r.Render(badge); err == nil {
+			t.Fatalf("expected error for %+v", badge)
+		}
+	}
+}
+
+func TestRendererRenderLabelColor(t *testing.T) {
+	r := newRenderer(t)
+	for _, style := range []renderer.Style{renderer.StyleFlat, renderer.StyleFlatSquare, renderer.StylePlastic} {
+		output, err := r.Render(renderer.Badge{
+			Subject:    "build",
+			Status:     "passing",
+			Color:      renderer.ColorGreen,
+			LabelColor: renderer.ColorBlue,
+			Style:      style,
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", style, err)
+		}
+		if !strings.Contains(string(output), `fill="#007ec6"`) || strings.Contains(string(output), `fill="#555"`) {
+			t.Fatalf("%s: expected label color in output: %s", style, output)
+		}
+	}
+
+	output, err := r.Render(renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(output), `fill="#555"`) {
+		t.Fatalf("expected default label color in output: %s", output)
+	}
+
+	_, err = r.Render(renderer.Badge{Subject: "a", Status: "b", LabelColor: "not-a-color"})
+	if err == nil {
+		t.Fatalf("expected error for invalid label color")
+	}
+}
+
+func TestRendererRenderLogo(t *testing.T) {
+	r := newRenderer(t)
+	plain, err := r.Render(renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	withLogo, err := r.Render(renderer.Badge{
+		Subject:   "build",
+		Status:    "passing",
+		Color:     renderer.ColorGreen,
+		Logo:      "check",
+		LogoColor: renderer.ColorYellow,
+		LogoWidth: 16,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := string(withLogo)
+	if !strings.Contains(result, "<image") || !strings.Contains(result, `width="16"`) {
+		t.Fatalf("expected logo image in output: %s", result)
+	}
+	if strings.Contains(string(plain), "<image") {
+		t.Fatalf("expected no logo image without logo")
+	}
+	if len(withLogo) <= len(plain) {
+		t.Fatalf("expected logo badge to be larger")
+	}
+}
+
+func TestRendererRenderInvalidLogo(t *testing.T) {
+	r := newRenderer(t)
+	cases := []renderer.Badge{
+		{Subject: "a", Status: "b", Logo: "nope"},
+		{Subject: "a", Status: "b", Logo: "check", LogoColor: "not-a-color"},
+		{Subject: "a", Status: "b", Logo: "check", LogoWidth: -1},
+	}
+	for _, badge := range cases {
+		if _, err := r.Render(badge); err == nil {
+			t.Fatalf("expected error for %#v", badge)
+		}
+	}
+}
+
 func BenchmarkRender(b *testing.B) {
 	r := newRenderer(b)
 	badge := renderer.Badge{Subject: "XXX", Status: "YYY", Color: renderer.ColorBlue}
@@ -186,3 +727,236 @@ func BenchmarkRenderParallel(b *testing.B) {
 		}
 	})
 }
+
+// BenchmarkRenderUncached measures layout and template execution without
+// the SVG cache that BenchmarkRender hits.
+func BenchmarkRenderUncached(b *testing.B) {
+	r := newRenderer(b)
+	r.SetCacheSize(0)
+	badge := renderer.Badge{Subject: "XXX", Status: "YYY", Color: renderer.ColorBlue}
+
+	for b.Loop() {
+		_, err := r.Render(badge)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkRenderToUncached measures RenderTo with the compiled templates
+// writing into a reused buffer.
+func BenchmarkRenderToUncached(b *testing.B) {
+	r := newRenderer(b)
+	r.SetCacheSize(0)
+	badge := renderer.Badge{Subject: "XXX", Status: "YYY", Color: renderer.ColorBlue}
+	buf := &bytes.Buffer{}
+	b.ReportAllocs()
+
+	for b.Loop() {
+		buf.Reset()
+		if err := r.RenderTo(buf, badge); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkRenderParallelUncached renders distinct badges with a TrueType
+// font, so every render measures text; run it with -cpu 1,4,8 to see scaling.
+func BenchmarkRenderParallelUncached(b *testing.B) {
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		b.Fatal(err)
+	}
+	r, err := renderer.NewRendererWithFontFace(newFace(regular))
+	if err != nil {
+		b.Fatal(err)
+	}
+	r.SetCacheSize(0)
+	var n atomic.Int64
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			badge := renderer.Badge{Subject: "coverage", Status: strconv.FormatInt(n.Add(1), 10) + "%", Color: renderer.ColorBlue}
+			_, renderErr := r.Render(badge)
+			if renderErr != nil {
+				b.Fatal(renderErr)
+			}
+		}
+	})
+}
+
+func TestRendererRenderAccessibility(t *testing.T) {
+	r := newRenderer(t)
+	for _, style := range renderer.Styles() {
+		output, err := r.Render(renderer.Badge{Subject: "build", Status: "passing", Color: "green", Style: style})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", style, err)
+		}
+		svg := string(output)
+		if !strings.Contains(svg, `role="img" aria-label="build: passing"`) {
+			t.Fatalf("%s: expected role and aria-label, got %s", style, svg)
+		}
+		if !strings.Contains(svg, "<title>build: passing</title>") {
+			t.Fatalf("%s: expected title, got %s", style, svg)
+		}
+	}
+
+	output, err := r.Render(renderer.Badge{Subject: "build", Status: "passing", Color: "green", Title: `Build "main" <ok>`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svg := string(output)
+	if !strings.Contains(svg, `aria-label="Build &#34;main&#34; &lt;ok&gt;"`) {
+		t.Fatalf("expected escaped aria-label override, got %s", svg)
+	}
+	if !strings.Contains(svg, "<title>Build &#34;main&#34; &lt;ok&gt;</title>") {
+		t.Fatalf("expected escaped title override, got %s", svg)
+	}
+}
+
+func TestRendererRenderIDPrefix(t *testing.T) {
+	r := newRenderer(t)
+	output, err := r.Render(renderer.Badge{Subject: "build", Status: "passing", Color: "green", IDPrefix: "docs_1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(output), `id="smooth-docs_1-`) || !strings.Contains(string(output), `mask="url(#round-docs_1-`) {
+		t.Fatalf("expected prefixed ids, got %s", output)
+	}
+
+	idOf := func(b renderer.Badge) string {
+		output, renderErr := r.Render(b)
+		if renderErr != nil {
+			t.Fatalf("unexpected error: %v", renderErr)
+		}
+		_, rest, _ := strings.Cut(string(output), `id="smooth-`)
+		id, _, _ := strings.Cut(rest, `"`)
+		return id
+	}
+	base := renderer.Badge{Subject: "build", Status: "passing", Color: "green"}
+	wider := base
+	wider.Logo = renderer.Logo(renderer.LogoNames()[0])
+	if idOf(base) == idOf(wider) {
+		t.Fatalf("expected badges with different output to have different ids")
+	}
+	if idOf(base) != idOf(base) {
+		t.Fatalf("expected stable ids")
+	}
+
+	_, err = r.Render(renderer.Badge{Subject: "build", Status: "passing", Color: "green", IDPrefix: "a b"})
+	if err == nil {
+		t.Fatalf("expected error for invalid id prefix")
+	}
+}
+
+func TestRendererRenderLinks(t *testing.T) {
+	r := newRenderer(t)
+	for _, style := range renderer.Styles() {
+		output, err := r.Render(renderer.Badge{
+			Subject: "docs",
+			Status:  "latest",
+			Color:   "blue",
+			Style:   style,
+			Links:   [2]string{"https://example.com/docs?a=1&b=2", "mailto:team@example.com"},
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", style, err)
+		}
+		svg := string(output)
+		if !strings.Contains(svg, `<a target="_blank" xlink:href="https://example.com/docs?a=1&amp;b=2"><rect x="0"`) {
+			t.Fatalf("%s: expected subject link, got %s", style, svg)
+		}
+		if !strings.Contains(svg, `xlink:href="mailto:team@example.com"`) {
+			t.Fatalf("%s: expected status link, got %s", style, svg)
+		}
+	}
+
+	output, err := r.Render(renderer.Badge{Subject: "docs", Status: "latest", Color: "blue", Links: [2]string{"", "https://example.com"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Count(string(output), "<a ") != 1 {
+		t.Fatalf("expected only the status to be linked, got %s", output)
+	}
+
+	for _, link := range []string{"javascript:alert(1)", "data:text/html,hi", "/relative", "https://", "ftp://example.com"} {
+		_, err = r.Render(renderer.Badge{Subject: "docs", Status: "latest", Color: "blue", Links: [2]string{link}})
+		if err == nil {
+			t.Fatalf("expected error for link %q", link)
+		}
+	}
+}
+
+func TestRendererRenderScale(t *testing.T) {
+	r := newRenderer(t)
+	base := renderer.Badge{Subject: "build", Status: "passing", Color: "green"}
+	output, err := r.Render(base)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	width := badgeWidth(t, string(output))
+	if !strings.Contains(string(output), fmt.Sprintf(`height="20" viewBox="0 0 %g 20"`, width)) {
+		t.Fatalf("expected unscaled viewBox, got %s", output)
+	}
+
+	for _, style := range renderer.Styles() {
+		unscaled := base
+		unscaled.Style = style
+		output, err = r.Render(unscaled)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", style, err)
+		}
+		width = badgeWidth(t, string(output))
+
+		large := unscaled
+		large.Scale = 2
+		output, err = r.Render(large)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", style, err)
+		}
+		if got := badgeWidth(t, string(output)); got != 2*width {
+			t.Fatalf("%s: expected scaled width %g, got %g", style, 2*width, got)
+		}
+		if !strings.Contains(string(output), fmt.Sprintf(`viewBox="0 0 %g `, width)) {
+			t.Fatalf("%s: expected unscaled viewBox, got %s", style, output)
+		}
+	}
+
+	for _, scale := range []float64{-1, renderer.MaxScale + 1, math.NaN()} {
+		invalid := base
+		invalid.Scale = scale
+		if _, err = r.Render(invalid); err == nil {
+			t.Fatalf("expected error for scale %v", scale)
+		}
+	}
+}
+
+func TestRendererRenderScaleMeasuresAtTargetSize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "goregular.ttf")
+	if err := os.WriteFile(path, goregular.TTF, 0o600); err != nil {
+		t.Fatalf("write temp font: %v", err)
+	}
+	r, err := renderer.NewRenderer(path)
+	if err != nil {
+		t.Fatalf("new renderer: %v", err)
+	}
+	f, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatalf("parse font: %v", err)
+	}
+	const scale = 3
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: 11 * scale, DPI: 72})
+	if err != nil {
+		t.Fatalf("new face: %v", err)
+	}
+	textDx := math.Ceil(float64(font.MeasureString(face, "passing"))/64/scale) + 13
+
+	output, err := r.Render(renderer.Badge{Subject: "build", Status: "passing", Color: "green", Scale: scale})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(output), fmt.Sprintf(`width="%g" height="20" fill="#97ca00"`, textDx)) {
+		t.Fatalf("expected status measured at %dx size to be %g wide, got %s", scale, textDx, output)
+	}
+}
@@ -0,0 +1,342 @@
+package renderer
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"strings"
+	"text/template/parse"
+
+	"golang.org/x/text/unicode/bidi"
+)
+
+// MaxSegments is the largest number of Badge.Segments.
+const MaxSegments = 8
+
+// Segment is one part of a badge with Badge.Segments.
+type Segment struct {
+	Text string `json:"text"`
+	// Color fills the segment. Empty uses Badge.LabelColor for the first
+	// segment and Badge.Color for the others.
+	Color Color `json:"color,omitempty"`
+	// Link is an http, https or mailto URL the segment points to, or empty.
+	Link string `json:"link,omitempty"`
+	// Logo is drawn at the leading edge of the segment, tinted with
+	// Badge.LogoColor and sized with Badge.LogoWidth.
+	Logo Logo `json:"logo,omitempty"`
+}
+
+// segmentSeparator separates the fields of a segment in ParseSegment.
+const segmentSeparator = "|"
+
+// ParseSegment parses a segment written as text|color|link|logo, where the
+// fields after the text are optional, e.g. "build|green" or
+// "docs||https://example.com/docs|book". It does not validate the fields.
+func ParseSegment(s string) Segment {
+	fields := strings.SplitN(s, segmentSeparator, 4)
+	fields = append(fields, make([]string, 4-len(fields))...)
+	return Segment{Text: fields[0], Color: Color(fields[1]), Link: fields[2], Logo: Logo(fields[3])}
+}
+
+// segmentBounds is the position of one segment of Badge.Segments.
+type segmentBounds struct {
+	// Start is the x offset where the segment begins; Dx is its width,
+	// including the logo.
+	Start float64
+	Dx    float64
+	// X is the x of the text anchor.
+	X float64
+	// TextDx compresses the text to a textLength when non-zero.
+	TextDx float64
+	// LogoX is the x of the logo; LogoDx is its width, zero without a logo.
+	LogoX  float64
+	LogoDx float64
+	// NotchX is the x where the arrow of a bubble leaves its edge and
+	// NotchTipX the x of its tip, both zero without an arrow.
+	NotchX    float64
+	NotchTipX float64
+}
+
+// templateSegment is the data of one segment in .Segments.
+type templateSegment struct {
+	Text  string
+	Color string
+	// TextColor and ShadowColor contrast with Color.
+	TextColor   string
+	ShadowColor string
+	// ColorOpacity and TextOpacity are the alpha of a translucent Color and
+	// TextColor, empty for opaque ones.
+	ColorOpacity string
+	TextOpacity  string
+	Link         string
+	Logo         template.URL
+	RTL          bool
+	Bold         bool
+	// Label is set on the first of several segments, drawn as a subject.
+	Label bool
+	// Path holds the text outlines when TextPaths is set.
+	Path string
+	// Class names the segment in the .CSS rules.
+	Class  string
+	Bounds segmentBounds
+}
+
+// isLabel reports whether segment i of n is drawn as a subject. A single
+// segment is drawn as a status, as shields.io draws message-only badges.
+func isLabel(i, n int) bool {
+	return i == 0 && n > 1
+}
+
+// SegmentsUseColor reports whether any of segments is filled with
+// Badge.Color: one without a color of its own that is not drawn as a subject.
+func SegmentsUseColor(segments []Segment) bool {
+	for i, s := range segments {
+		if s.Color == "" && !isLabel(i, len(segments)) {
+			return true
+		}
+	}
+	return false
+}
+
+// segmentClass is the class of segment i of n: the first and last take the
+// subject and status classes, so the rules of the dark palette and the
+// animations apply as to a two-segment badge.
+func segmentClass(i, n int) string {
+	switch {
+	case isLabel(i, n):
+		return "subject"
+	case i == n-1:
+		return "status"
+	default:
+		return fmt.Sprintf("segment-%d", i)
+	}
+}
+
+// layoutSegments positions segments of the padded text widths dxs, given in
+// reading order, each with a logo of width logoDxs[i] at its leading edge.
+// Mirrored badges place them right to left. The first segment drawn takes
+// the left text shift of the style and the others the right one.
+func layoutSegments(m styleMetrics, dxs, logoDxs []float64, mirrored bool) []segmentBounds {
+	segments := make([]segmentBounds, len(dxs))
+	start := 0.0
+	for v := range dxs {
+		i := v
+		if mirrored {
+			i = len(dxs) - 1 - v
+		}
+		shift := logoShift(logoDxs[i])
+		s := segmentBounds{Start: start, Dx: dxs[i] + shift, LogoDx: logoDxs[i]}
+		anchor := m.rightShift
+		if v == 0 {
+			anchor = m.leftShift
+		}
+		if mirrored {
+			s.X = s.Start + s.Dx/2.0 - shift/2.0 + anchor
+			s.LogoX = s.Start + s.Dx - m.logoInset - s.LogoDx
+		} else {
+			s.X = s.Start + s.Dx/2.0 + shift/2.0 + anchor
+			s.LogoX = s.Start + m.logoInset
+		}
+		if m.notch && i > 0 {
+			// The arrow points back at the previous segment in reading order.
+			if mirrored {
+				s.NotchX, s.NotchTipX = s.Start+s.Dx-0.5, s.Start+s.Dx+socialNotch-0.5
+			} else {
+				s.NotchX, s.NotchTipX = s.Start+0.5, s.Start-socialNotch+0.5
+			}
+		}
+		segments[i] = s
+		start += s.Dx + m.gap
+	}
+	return segments
+}
+
+// validateSegments checks the segments of b and the fields they replace.
+func validateSegments(b Badge) error {
+	if len(b.Segments) == 0 {
+		return nil
+	}
+	if len(b.Segments) > MaxSegments {
+		return fmt.Errorf("too many segments: %d, at most %d", len(b.Segments), MaxSegments)
+	}
+	if b.Subject != "" || b.Status != "" {
+		return errors.New("segments replace subject and status")
+	}
+	if b.Logo != "" || b.Links != [2]string{} {
+		return errors.New("segments carry their own logos and links")
+	}
+	if b.Kind == KindProgress {
+		return errors.New("progress badges do not support segments")
+	}
+	for i, s := range b.Segments {
+		if s.Text == "" && s.Logo == "" {
+			return fmt.Errorf("segment %d has neither text nor logo", i)
+		}
+		if !s.Color.IsValid() {
+			return fmt.Errorf("invalid segment color: %q", s.Color)
+		}
+		if !ValidLink(s.Link) {
+			return fmt.Errorf("invalid link: %q", s.Link)
+		}
+	}
+	return nil
+}
+
+// segmentFills returns the fills of the segments of b as drawn by a template
+// with the metrics m. Styles with a fixed status fill keep it for every
+// segment but the subject.
+func segmentFills(b Badge, m styleMetrics) []Color {
+	labelColor, color := segmentColors(b, m)
+	n := len(b.Segments)
+	fills := make([]Color, n)
+	for i, s := range b.Segments {
+		switch {
+		case !isLabel(i, n) && m.fixedColor:
+			fills[i] = color
+		case s.Color != "":
+			fills[i] = s.Color
+		case isLabel(i, n):
+			fills[i] = labelColor
+		default:
+			fills[i] = color
+		}
+	}
+	return fills
+}
+
+// prepareSegments lays out the segments of b and returns them with the
+// bounds of the badge. The first and last segments also set the subject and
+// status fields of the bounds, which the spinner and progress helpers use.
+func (r *Renderer) prepareSegments(b Badge, m styleMetrics) ([]templateSegment, bounds, error) {
+	n := len(b.Segments)
+	segs := make([]segment, n)
+	logos := make([]template.URL, n)
+	logoDxs := make([]float64, n)
+	dirs := make([]bidi.Direction, n)
+	budget := float64(b.MaxWidth) - b.Animation.shift() - m.gap*float64(n-1)
+	for i, s := range b.Segments {
+		var err error
+		logos[i], logoDxs[i], err = resolveLogo(Badge{Logo: s.Logo, LogoColor: b.LogoColor, LogoWidth: b.LogoWidth})
+		if err != nil {
+			return nil, bounds{}, err
+		}
+		budget -= logoShift(logoDxs[i])
+		text := s.Text
+		if m.uppercase {
+			text = strings.ToUpper(text)
+		}
+		bold := m.boldStatus
+		if isLabel(i, n) {
+			bold = m.boldSubject
+		}
+		segs[i] = segment{text: text, bold: bold, dx: r.measureText(text, m, bold)}
+		dirs[i] = baseDirection(text)
+	}
+	if b.MaxWidth > 0 {
+		ptrs := make([]*segment, n)
+		for i := range segs {
+			ptrs[i] = &segs[i]
+		}
+		if minDx, ok := r.fitSegments(b.Overflow, budget, m, ptrs...); !ok {
+			return nil, bounds{}, maxWidthError(b.MaxWidth, budget, minDx)
+		}
+	}
+	segs[n-1].dx += b.Animation.shift()
+	dxs := make([]float64, n)
+	for i, s := range segs {
+		dxs[i] = s.dx
+	}
+	mirrored := isRTLBadge(dirs...)
+	layout := layoutSegments(m, dxs, logoDxs, mirrored)
+	for i := range layout {
+		layout[i].TextDx = segs[i].textDx
+	}
+	first, last := layout[0], layout[n-1]
+	bb := bounds{
+		SubjectDx:     first.Dx,
+		SubjectX:      first.X,
+		LogoDx:        first.LogoDx,
+		LogoX:         first.LogoX,
+		Gap:           m.gap,
+		StatusDx:      last.Dx,
+		StatusX:       last.X,
+		SubjectTextDx: first.TextDx,
+		StatusTextDx:  last.TextDx,
+		Mirrored:      mirrored,
+		segments:      layout,
+	}
+	if b.Animation == AnimationSpinner {
+		placeSpinner(&bb, m)
+		if !mirrored {
+			// The spinner follows the logo at the leading edge.
+			bb.SpinnerX += logoShift(last.LogoDx)
+		}
+		layout[n-1].X = bb.StatusX
+	}
+	fills := segmentFills(b, m)
+	data := make([]templateSegment, n)
+	for i, s := range b.Segments {
+		text, shadow := textColors(fills[i], b.TextColor, m)
+		data[i] = templateSegment{
+			Text:        segs[i].text,
+			Color:       fills[i].String(),
+			TextColor:   text,
+			ShadowColor: shadow,
+			Link:        s.Link,
+			Logo:        logos[i],
+			RTL:         dirs[i] == bidi.RightToLeft,
+			Bold:        segs[i].bold,
+			Label:       isLabel(i, n),
+			Class:       segmentClass(i, n),
+			Bounds:      layout[i],
+		}
+	}
+	return data, bb, nil
+}
+
+// segmentsTitle is the accessible name of a badge with segments.
+func segmentsTitle(b Badge) string {
+	texts := make([]string, 0, len(b.Segments))
+	for _, s := range b.Segments {
+		if s.Text != "" {
+			texts = append(texts, s.Text)
+		}
+	}
+	return strings.Join(texts, ": ")
+}
+
+// usesSegments reports whether a style template ranges over .Segments, and
+// so can draw badges with Badge.Segments.
+func usesSegments(tmpl *template.Template) bool {
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil && rangesOverSegments(t.Tree.Root) {
+			return true
+		}
+	}
+	return false
+}
+
+func rangesOverSegments(n parse.Node) bool {
+	switch node := n.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return false
+		}
+		for _, child := range node.Nodes {
+			if rangesOverSegments(child) {
+				return true
+			}
+		}
+	case *parse.RangeNode:
+		if strings.Contains(node.Pipe.String(), ".Segments") {
+			return true
+		}
+		return rangesOverSegments(node.List) || rangesOverSegments(node.ElseList)
+	case *parse.IfNode:
+		return rangesOverSegments(node.List) || rangesOverSegments(node.ElseList)
+	case *parse.WithNode:
+		return rangesOverSegments(node.List) || rangesOverSegments(node.ElseList)
+	default:
+	}
+	return false
+}
@@ -0,0 +1,253 @@
+package renderer_test
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rhajizada/signum/pkg/renderer"
+)
+
+func TestRenderSegments(t *testing.T) {
+	r := newRenderer(t)
+	segments := []renderer.Segment{
+		{Text: "build", Logo: "bolt"},
+		{Text: "linux", Color: renderer.ColorBlue, Link: "https://example.com/linux"},
+		{Text: "passing"},
+	}
+	for _, style := range renderer.Styles() {
+		b := renderer.Badge{Segments: segments, Color: renderer.ColorGreen, Style: style}
+		svg, err := r.Render(b)
+		if err != nil {
+			t.Fatalf("%s: render: %v", style, err)
+		}
+		output := strings.ToLower(string(svg))
+		for _, want := range []string{
+			`aria-label="build: linux: passing"`, ">build<", ">linux<", ">passing<",
+			`xlink:href="https://example.com/linux"`, `xlink:href="data:image/svg&#43;xml;base64,`,
+		} {
+			if !strings.Contains(output, want) {
+				t.Fatalf("%s: expected output to contain %q: %s", style, want, output)
+			}
+		}
+		if style != renderer.StyleSocial {
+			for _, fill := range []string{`fill="#555"`, `fill="#007ec6"`, `fill="#97ca00"`} {
+				if !strings.Contains(output, fill) {
+					t.Fatalf("%s: expected the segment fill %s: %s", style, fill, output)
+				}
+			}
+		}
+
+		two, err := r.Render(renderer.Badge{Segments: segments[1:], Color: renderer.ColorGreen, Style: style})
+		if err != nil {
+			t.Fatalf("%s: render: %v", style, err)
+		}
+		classic, err := r.Render(renderer.Badge{Subject: "linux", Status: "passing", Color: renderer.ColorGreen, Style: style})
+		if err != nil {
+			t.Fatalf("%s: render: %v", style, err)
+		}
+		if badgeWidth(t, string(two)) != badgeWidth(t, string(classic)) {
+			t.Fatalf("%s: expected two segments as wide as subject and status:\n%s\n%s", style, two, classic)
+		}
+		if badgeWidth(t, string(svg)) <= badgeWidth(t, string(two)) {
+			t.Fatalf("%s: expected a third segment to widen the badge", style)
+		}
+
+		if _, err = r.RenderPNG(b, 2); err != nil {
+			t.Fatalf("%s: render png: %v", style, err)
+		}
+	}
+}
+
+func TestRenderSegmentsLayout(t *testing.T) {
+	r := newRenderer(t)
+	single, err := r.Render(renderer.Badge{Segments: []renderer.Segment{{Text: "stable"}}, Color: renderer.ColorGreen})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(string(single), `fill="#97ca00"`) || !strings.Contains(string(single), `aria-label="stable"`) {
+		t.Fatalf("expected a single segment drawn as a status: %s", single)
+	}
+
+	rtl, err := r.Render(renderer.Badge{Segments: []renderer.Segment{{Text: "שלום"}, {Text: "עולם"}, {Text: "123"}}})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(string(rtl), `direction="rtl"`) {
+		t.Fatalf("expected rtl segments: %s", rtl)
+	}
+	if x := segmentTextX(t, string(rtl), "שלום"); x <= segmentTextX(t, string(rtl), "123") {
+		t.Fatalf("expected the first rtl segment on the right: %s", rtl)
+	}
+
+	long := renderer.Badge{
+		Segments: []renderer.Segment{{Text: "ci"}, {Text: "a very long branch name"}, {Text: "a much longer status message"}},
+		MaxWidth: 160,
+	}
+	capped, err := r.Render(long)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if width := badgeWidth(t, string(capped)); width > 160 || !strings.Contains(string(capped), ">ci<") {
+		t.Fatalf("expected the long segments to be truncated to fit 160px: %s", capped)
+	}
+
+	dark, err := r.Render(renderer.Badge{
+		Segments:  []renderer.Segment{{Text: "deploy"}, {Text: "eu", Color: renderer.ColorBlue}, {Text: "running"}},
+		Animation: renderer.AnimationSpinner,
+		Dark:      renderer.Palette{Color: "#222"},
+	})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	for _, want := range []string{`class="segment-1"`, `.status{fill:#222}`, `.segment-1{fill:#007ec6}`, `class="spinner"`} {
+		if !strings.Contains(string(dark), want) {
+			t.Fatalf("expected output to contain %q: %s", want, dark)
+		}
+	}
+}
+
+func TestRenderSegmentsMaxWidth(t *testing.T) {
+	r, err := renderer.NewVerdanaRenderer()
+	if err != nil {
+		t.Fatalf("new renderer: %v", err)
+	}
+	segments := []renderer.Segment{
+		{Text: "ci", Logo: "bolt"},
+		{Text: "a very long branch name"},
+		{Text: "a much longer status message"},
+	}
+	for _, overflow := range renderer.Overflows() {
+		for _, style := range renderer.Styles() {
+			for maxWidth := 20; maxWidth <= 260; maxWidth += 12 {
+				b := renderer.Badge{Segments: segments, Style: style, MaxWidth: maxWidth, Overflow: overflow}
+				svg, renderErr := r.Render(b)
+				if renderErr != nil {
+					if !strings.Contains(renderErr.Error(), "below the minimum") {
+						t.Fatalf("%s/%s/%d: unexpected error: %v", overflow, style, maxWidth, renderErr)
+					}
+					continue
+				}
+				if width := badgeWidth(t, string(svg)); width > float64(maxWidth) {
+					t.Fatalf("%s/%s: expected width <= %d, got %v", overflow, style, maxWidth, width)
+				}
+				data, pngErr := r.RenderPNG(b, 1)
+				if pngErr != nil {
+					t.Fatalf("%s/%s/%d: render png: %v", overflow, style, maxWidth, pngErr)
+				}
+				if width := decodePNG(t, data).Bounds().Dx(); width > maxWidth {
+					t.Fatalf("%s/%s: expected png width <= %d, got %d", overflow, style, maxWidth, width)
+				}
+			}
+		}
+	}
+	if _, err = r.Render(renderer.Badge{Segments: segments, MaxWidth: 30}); err == nil {
+		t.Fatalf("expected error for a max width below the ellipses of every segment")
+	}
+}
+
+// segmentTextX returns the x of the first text element drawing text.
+func segmentTextX(tb testing.TB, svg, text string) float64 {
+	tb.Helper()
+	end := strings.Index(svg, ">"+text+"</text>")
+	if end < 0 {
+		tb.Fatalf("missing text %q: %s", text, svg)
+	}
+	start := strings.LastIndex(svg[:end], `<text x="`) + len(`<text x="`)
+	x, err := strconv.ParseFloat(svg[start:start+strings.IndexByte(svg[start:], '"')], 64)
+	if err != nil {
+		tb.Fatalf("parse x: %v", err)
+	}
+	return x
+}
+
+func TestRenderSegmentsPNG(t *testing.T) {
+	r := newRenderer(t)
+	data, err := r.RenderPNG(renderer.Badge{
+		Segments: []renderer.Segment{{Text: "a", Color: "#ff0000"}, {Text: "b", Color: "#00ff00"}, {Text: "c", Color: "#0000ff"}},
+		Style:    renderer.StyleForTheBadge,
+	}, 1)
+	if err != nil {
+		t.Fatalf("render png: %v", err)
+	}
+	img := decodePNG(t, data)
+	width := img.Bounds().Dx()
+	for x, want := range map[int]color.NRGBA{
+		1:         {R: 0xff, A: 0xff},
+		width / 2: {G: 0xff, A: 0xff},
+		width - 2: {B: 0xff, A: 0xff},
+	} {
+		if got := color.NRGBAModel.Convert(img.At(x, 1)); got != want {
+			t.Fatalf("expected %v at x %d, got %v", want, x, got)
+		}
+	}
+}
+
+func TestRenderSegmentsInvalid(t *testing.T) {
+	r := newRenderer(t)
+	segments := []renderer.Segment{{Text: "a"}, {Text: "b"}}
+	for _, b := range []renderer.Badge{
+		{Segments: segments, Subject: "a"},
+		{Segments: segments, Logo: "bolt"},
+		{Segments: segments, Links: [2]string{"https://example.com"}},
+		{Segments: segments, Kind: renderer.KindProgress},
+		{Segments: make([]renderer.Segment, renderer.MaxSegments+1)},
+		{Segments: []renderer.Segment{{Text: "a"}, {}}},
+		{Segments: []renderer.Segment{{Text: "a", Color: "nope"}}},
+		{Segments: []renderer.Segment{{Text: "a", Link: "javascript:alert(1)"}}},
+		{Segments: []renderer.Segment{{Text: "a", Logo: "nope"}}},
+	} {
+		if _, err := r.Render(b); err == nil {
+			t.Fatalf("expected error for %+v", b)
+		}
+		if _, err := r.RenderPNG(b, 1); err == nil {
+			t.Fatalf("expected png error for %+v", b)
+		}
+	}
+}
+
+func TestRenderSegmentsCustomStyle(t *testing.T) {
+	r := newRenderer(t)
+	const tmpl = `<svg width="{{.Width}}">{{range .Segments}}<text x="{{.Bounds.X}}" class="{{.Class}}">{{.Text}}` +
+		`{{if $.FontWeight}}!{{end}}</text>{{end}}</svg>`
+	if err := r.RegisterStyle("segmented", tmpl); err != nil {
+		t.Fatalf("register style: %v", err)
+	}
+	b := renderer.Badge{Segments: []renderer.Segment{{Text: "a"}, {Text: "b"}, {Text: "c"}}, Style: "segmented"}
+	svg, err := r.Render(b)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(string(svg), `class="segment-1">b</text>`) {
+		t.Fatalf("expected the segments in the custom template: %s", svg)
+	}
+	if err = r.RegisterStyle("plain", `<svg>{{.Subject}}</svg>`); err != nil {
+		t.Fatalf("register style: %v", err)
+	}
+	if !r.HasSegments("segmented") || !r.HasSegments(renderer.StyleFlat) || r.HasSegments("plain") || r.HasSegments("unknown") {
+		t.Fatalf("unexpected segment support")
+	}
+	b.Style = "plain"
+	if _, err = r.Render(b); err == nil {
+		t.Fatalf("expected error for a style without segments")
+	}
+	if err = r.RegisterStyle("typo", `<svg>{{range .Segments}}{{.Txt}}{{end}}</svg>`); err == nil {
+		t.Fatalf("expected error for an unknown segment field")
+	}
+}
+
+func TestParseSegment(t *testing.T) {
+	cases := map[string]renderer.Segment{
+		"build":                      {Text: "build"},
+		"build|green":                {Text: "build", Color: renderer.ColorGreen},
+		"docs||https://example.com|": {Text: "docs", Link: "https://example.com"},
+		"|#333||bolt":                {Color: "#333", Logo: "bolt"},
+		"a|b|c|d|e":                  {Text: "a", Color: "b", Link: "c", Logo: "d|e"},
+	}
+	for s, want := range cases {
+		if got := renderer.ParseSegment(s); got != want {
+			t.Fatalf("%q: expected %+v, got %+v", s, want, got)
+		}
+	}
+}
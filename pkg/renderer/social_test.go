@@ -0,0 +1,90 @@
+package renderer_test
+
+import (
+	"image/color"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/rhajizada/signum/pkg/renderer"
+)
+
+// TestRenderSocialGolden checks the social badge against testdata and its
+// count bubble against the shields.io social style, whose message bubble is
+// drawn by badge-maker as
+//
+//	<rect x="X" y="0.5" width="W" height="19" rx="2" fill="#fafafa"/>
+//	<rect x="X-0.5" y="7.5" width="0.5" height="5" stroke="#fafafa"/>
+//	<path d="MX 6.5 l-3 3v1 l3 3" fill="#fafafa"/>
+//
+// with the notch rect hiding the bubble border where the arrow joins it.
+func TestRenderSocialGolden(t *testing.T) {
+	r := newRenderer(t)
+	svg, err := r.Render(renderer.Badge{Subject: "Follow", Status: "123", Style: renderer.StyleSocial})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	want, err := os.ReadFile(filepath.Join("testdata", "social.svg"))
+	if err != nil {
+		t.Fatalf("read golden: %v", err)
+	}
+	if string(svg) != string(want) {
+		t.Fatalf("social output differs from testdata/social.svg\n got: %s\nwant: %s", svg, want)
+	}
+
+	bubble := regexp.MustCompile(`<rect x="([\d.]+)" y="\.5" width="[\d.]+" height="19" rx="2" fill="#fafafa"/>` +
+		`<rect x="([\d.]+)" y="7.5" width="0.5" height="5" stroke="#fafafa"/>` +
+		`<path d="M([\d.]+) 6.5L([\d.]+) 9.5v1L([\d.]+) 13.5" fill="#fafafa"/>`)
+	match := bubble.FindStringSubmatch(string(svg))
+	if match == nil {
+		t.Fatalf("expected a count bubble with its notch: %s", svg)
+	}
+	var v [5]float64
+	for i := range v {
+		v[i], _ = strconv.ParseFloat(match[i+1], 64)
+	}
+	x := v[0]
+	if v[1] != x-0.5 || v[2] != x || v[3] != x-3 || v[4] != x {
+		t.Fatalf("expected the shields.io notch at x %v, got %v", x, v)
+	}
+
+	rtl, err := r.Render(renderer.Badge{Subject: "שלום", Status: "עולם", Style: renderer.StyleSocial})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !regexp.MustCompile(`<path d="M([\d.]+) 6.5L[\d.]+ 9.5v1L[\d.]+ 13.5"`).MatchString(string(rtl)) {
+		t.Fatalf("expected a notch on the mirrored bubble: %s", rtl)
+	}
+}
+
+func TestRenderSocialNotchPNG(t *testing.T) {
+	r := newRenderer(t)
+	b := renderer.Badge{Subject: "Follow", Status: "123", Style: renderer.StyleSocial}
+	for _, b := range []renderer.Badge{b, {Segments: []renderer.Segment{{Text: "Follow"}, {Text: "123"}}, Style: renderer.StyleSocial}} {
+		data, err := r.RenderPNG(b, 1)
+		if err != nil {
+			t.Fatalf("render png: %v", err)
+		}
+		img := decodePNG(t, data)
+		svg, err := r.Render(b)
+		if err != nil {
+			t.Fatalf("render: %v", err)
+		}
+		match := regexp.MustCompile(`<path d="M([\d.]+) 6.5`).FindStringSubmatch(string(svg))
+		if match == nil {
+			t.Fatalf("expected a notch: %s", svg)
+		}
+		x, _ := strconv.ParseFloat(match[1], 64)
+		// Inside the arrow, left of the bubble, and on the opened border.
+		for _, px := range []int{int(x) - 2, int(x)} {
+			if c := color.NRGBAModel.Convert(img.At(px, 10)).(color.NRGBA); c.A == 0 || c.R < 0xf0 {
+				t.Fatalf("%+v: expected the bubble fill at %d,10, got %v", b, px, c)
+			}
+		}
+		if c := color.NRGBAModel.Convert(img.At(int(x)-5, 10)).(color.NRGBA); c.A != 0 {
+			t.Fatalf("%+v: expected the gap beyond the arrow tip to be clear, got %v", b, c)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	_ "embed"
 	"fmt"
 	"html/template"
+	"strings"
 )
 
 //go:embed templates/flat.svg.tmpl
@@ -15,39 +16,224 @@ var flatSquareTemplate string
 //go:embed templates/plastic.svg.tmpl
 var plasticTemplate string
 
+//go:embed templates/for-the-badge.svg.tmpl
+var forTheBadgeTemplate string
+
+//go:embed templates/social.svg.tmpl
+var socialTemplate string
+
+//go:embed templates/progress-flat.svg.tmpl
+var progressFlatTemplate string
+
+//go:embed templates/progress-flat-square.svg.tmpl
+var progressFlatSquareTemplate string
+
+//go:embed templates/progress-plastic.svg.tmpl
+var progressPlasticTemplate string
+
+//go:embed templates/segments-flat.svg.tmpl
+var segmentsFlatTemplate string
+
+//go:embed templates/segments-flat-square.svg.tmpl
+var segmentsFlatSquareTemplate string
+
+//go:embed templates/segments-plastic.svg.tmpl
+var segmentsPlasticTemplate string
+
+//go:embed templates/segments-for-the-badge.svg.tmpl
+var segmentsForTheBadgeTemplate string
+
+//go:embed templates/segments-social.svg.tmpl
+var segmentsSocialTemplate string
+
 type Style string
 
 const (
-	StyleFlat       Style = "flat"
-	StyleFlatSquare Style = "flat-square"
-	StylePlastic    Style = "plastic"
+	StyleFlat        Style = "flat"
+	StyleFlatSquare  Style = "flat-square"
+	StylePlastic     Style = "plastic"
+	StyleForTheBadge Style = "for-the-badge"
+	StyleSocial      Style = "social"
 )
 
+// Styles returns every supported style in display order.
+func Styles() []Style {
+	return []Style{StyleFlat, StyleFlatSquare, StylePlastic, StyleForTheBadge, StyleSocial}
+}
+
+// IsValid reports whether s is a built-in style. Use Renderer.HasStyle to
+// include registered styles.
 func (s Style) IsValid() bool {
 	switch s {
-	case StyleFlat, StyleFlatSquare, StylePlastic:
+	case StyleFlat, StyleFlatSquare, StylePlastic, StyleForTheBadge, StyleSocial:
 		return true
 	default:
 		return false
 	}
 }
 
-func parseTemplates() (map[Style]*template.Template, error) {
-	templates := map[Style]string{
-		StyleFlat:       flatTemplate,
-		StyleFlatSquare: flatSquareTemplate,
-		StylePlastic:    plasticTemplate,
+// styleMetrics describes how text is measured and placed for a style.
+type styleMetrics struct {
+	// fontScale is applied to widths measured at the base font size.
+	fontScale float64
+	// letterSpacing is added after every rune.
+	letterSpacing float64
+	// padding is the horizontal room around the text of a segment.
+	padding float64
+	// gap separates the subject and status segments.
+	gap float64
+	// notch is set when segments after the first are bubbles with an arrow
+	// pointing at the previous one, as in StyleSocial.
+	notch bool
+	// leftShift and rightShift nudge the text anchors of the left and right segments.
+	leftShift  float64
+	rightShift float64
+	// logoInset is the distance between the logo and the outer badge edge.
+	logoInset   float64
+	uppercase   bool
+	boldSubject bool
+	boldStatus  bool
+	// fontFamily is the CSS font-family list used when the font is unknown.
+	fontFamily string
+	// labelColor and color are the default segment fills of the template.
+	labelColor Color
+	color      Color
+	// fixedColor is set when the status fill does not follow Badge.Color.
+	fixedColor bool
+	// darkTextShadow is drawn under dark text.
+	darkTextShadow string
+	// height is the unscaled badge height.
+	height float64
+	// scale is the Badge.Scale text is measured at.
+	scale float64
+}
+
+const (
+	badgeHeight              = 20
+	forTheBadgeFontScale     = 10.0 / fontsize
+	forTheBadgeLetterSpacing = 1.25
+	forTheBadgePadding       = 18
+	forTheBadgeHeight        = 28
+	socialPadding            = 12
+	socialGap                = 6
+	// socialNotch is how far the arrow of a social bubble reaches out of it.
+	socialNotch = 3
+
+	verdanaFontFamily   = "DejaVu Sans,Verdana,Geneva,sans-serif"
+	helveticaFontFamily = "Helvetica Neue,Helvetica,Arial,sans-serif"
+
+	defaultLabelColor = Color("#555")
+	defaultColor      = Color("#4c1")
+	defaultTextShadow = "#ccc"
+	socialLabelColor  = Color("#fcfcfc")
+	socialColor       = Color("#fafafa")
+	socialTextShadow  = "#fff"
+)
+
+func (s Style) metrics() styleMetrics {
+	switch s {
+	case StyleForTheBadge:
+		return styleMetrics{
+			fontScale:      forTheBadgeFontScale,
+			letterSpacing:  forTheBadgeLetterSpacing,
+			padding:        forTheBadgePadding,
+			leftShift:      forTheBadgeLetterSpacing / 2,
+			rightShift:     forTheBadgeLetterSpacing / 2,
+			logoInset:      forTheBadgePadding / 2,
+			uppercase:      true,
+			boldStatus:     true,
+			fontFamily:     verdanaFontFamily,
+			labelColor:     defaultLabelColor,
+			color:          defaultColor,
+			darkTextShadow: defaultTextShadow,
+			height:         forTheBadgeHeight,
+		}
+	case StyleSocial:
+		return styleMetrics{
+			fontScale:      1,
+			padding:        socialPadding,
+			gap:            socialGap,
+			notch:          true,
+			logoInset:      logoInset,
+			boldSubject:    true,
+			boldStatus:     true,
+			fontFamily:     helveticaFontFamily,
+			labelColor:     socialLabelColor,
+			color:          socialColor,
+			fixedColor:     true,
+			darkTextShadow: socialTextShadow,
+			height:         badgeHeight,
+		}
+	case StyleFlat, StyleFlatSquare, StylePlastic:
+		fallthrough
+	default:
+		return styleMetrics{
+			fontScale:      1,
+			padding:        extraDx,
+			leftShift:      1,
+			rightShift:     -1,
+			logoInset:      logoInset,
+			fontFamily:     verdanaFontFamily,
+			labelColor:     defaultLabelColor,
+			color:          defaultColor,
+			darkTextShadow: defaultTextShadow,
+			height:         badgeHeight,
+		}
 	}
-	parsed := make(map[Style]*template.Template, len(templates))
+}
+
+func parseTemplates() (map[Style]*styleTemplate, error) {
+	return parseStyleTemplates(map[Style]string{
+		StyleFlat:        flatTemplate,
+		StyleFlatSquare:  flatSquareTemplate,
+		StylePlastic:     plasticTemplate,
+		StyleForTheBadge: forTheBadgeTemplate,
+		StyleSocial:      socialTemplate,
+	})
+}
+
+// parseProgressTemplates parses the KindProgress templates of ProgressStyles.
+func parseProgressTemplates() (map[Style]*styleTemplate, error) {
+	return parseStyleTemplates(map[Style]string{
+		StyleFlat:       progressFlatTemplate,
+		StyleFlatSquare: progressFlatSquareTemplate,
+		StylePlastic:    progressPlasticTemplate,
+	})
+}
+
+// parseSegmentTemplates parses the templates of badges with Badge.Segments.
+func parseSegmentTemplates() (map[Style]*styleTemplate, error) {
+	return parseStyleTemplates(map[Style]string{
+		StyleFlat:        segmentsFlatTemplate,
+		StyleFlatSquare:  segmentsFlatSquareTemplate,
+		StylePlastic:     segmentsPlasticTemplate,
+		StyleForTheBadge: segmentsForTheBadgeTemplate,
+		StyleSocial:      segmentsSocialTemplate,
+	})
+}
+
+func parseStyleTemplates(templates map[Style]string) (map[Style]*styleTemplate, error) {
+	parsed := make(map[Style]*styleTemplate, len(templates))
 	for style, tmplText := range templates {
-		if tmplText == "" {
-			return nil, fmt.Errorf("empty template for style: %q", style)
-		}
-		tmpl, err := template.New(string(style)).Parse(stripXMLWhitespace(tmplText))
+		tmpl, err := parseTemplate(style, tmplText)
 		if err != nil {
 			return nil, err
 		}
-		parsed[style] = tmpl
+		parsed[style] = newStyleTemplate(tmpl)
 	}
 	return parsed, nil
 }
+
+func parseTemplate(style Style, tmplText string) (*template.Template, error) {
+	if strings.TrimSpace(tmplText) == "" {
+		return nil, fmt.Errorf("empty template for style: %q", style)
+	}
+	return template.New(string(style)).Funcs(templateFuncs()).Parse(stripXMLWhitespace(tmplText))
+}
+
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"add": func(a, b float64) float64 { return a + b },
+		"sub": func(a, b float64) float64 { return a - b },
+	}
+}
@@ -0,0 +1,310 @@
+package renderer
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/vector"
+)
+
+// svgIcon is the subset of an SVG logo that RenderPNG can draw: filled paths
+// in a view box. Transforms, strokes and other shapes are ignored.
+type svgIcon struct {
+	viewBox [4]float64
+	paths   []svgPath
+}
+
+type svgPath struct {
+	d    string
+	fill color.NRGBA
+}
+
+// parseSVGIcon decodes a base64 SVG data URI. Paths inherit the fill of the
+// svg element, as embedded logos are tinted there, and default to black.
+func parseSVGIcon(uri string) (svgIcon, bool) {
+	encoded, ok := strings.CutPrefix(uri, logoDataURIPrefix)
+	if !ok {
+		return svgIcon{}, false
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return svgIcon{}, false
+	}
+	icon := svgIcon{viewBox: [4]float64{0, 0, logoDefaultWidth, logoHeight}}
+	fill := color.NRGBA{A: maxByte}
+	dec := xml.NewDecoder(strings.NewReader(string(data)))
+	for {
+		tok, tokErr := dec.Token()
+		if tokErr != nil {
+			break
+		}
+		start, isStart := tok.(xml.StartElement)
+		if !isStart {
+			continue
+		}
+		attrs := map[string]string{}
+		for _, attr := range start.Attr {
+			attrs[attr.Name.Local] = attr.Value
+		}
+		switch start.Name.Local {
+		case "svg":
+			if box, boxOK := parseViewBox(attrs); boxOK {
+				icon.viewBox = box
+			}
+			if c, fillOK := Color(attrs["fill"]).NRGBA(); fillOK {
+				fill = c
+			}
+		case "path":
+			pathFill := fill
+			if value, set := attrs["fill"]; set {
+				if value == "none" {
+					continue
+				}
+				if c, fillOK := Color(value).NRGBA(); fillOK {
+					pathFill = c
+				}
+			}
+			icon.paths = append(icon.paths, svgPath{d: attrs["d"], fill: pathFill})
+		default:
+		}
+	}
+	return icon, len(icon.paths) > 0
+}
+
+// parseViewBox reads the viewBox attribute, or width and height without one.
+func parseViewBox(attrs map[string]string) ([4]float64, bool) {
+	var box [4]float64
+	if fields := strings.FieldsFunc(attrs["viewBox"], isPathSeparator); len(fields) == len(box) {
+		for i, field := range fields {
+			v, ok := parseNumber(field)
+			if !ok {
+				return box, false
+			}
+			box[i] = v
+		}
+		return box, true
+	}
+	width, ok := parseNumber(strings.TrimSuffix(attrs["width"], "px"))
+	if !ok {
+		return box, false
+	}
+	height, ok := parseNumber(strings.TrimSuffix(attrs["height"], "px"))
+	if !ok {
+		return box, false
+	}
+	return [4]float64{0, 0, width, height}, true
+}
+
+func isPathSeparator(r rune) bool {
+	return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
+
+// pathScanner tokenizes SVG path data.
+type pathScanner struct {
+	s string
+	i int
+}
+
+func (p *pathScanner) skip() {
+	for p.i < len(p.s) && isPathSeparator(rune(p.s[p.i])) {
+		p.i++
+	}
+}
+
+// command returns the next command letter, if the next token is one.
+func (p *pathScanner) command() (byte, bool) {
+	p.skip()
+	if p.i >= len(p.s) {
+		return 0, false
+	}
+	c := p.s[p.i]
+	if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+		return 0, false
+	}
+	p.i++
+	return c, true
+}
+
+// number reads the next number. Numbers may run together as in "1.5.5-2".
+func (p *pathScanner) number() (float64, bool) {
+	p.skip()
+	start := p.i
+	if p.i < len(p.s) && (p.s[p.i] == '-' || p.s[p.i] == '+') {
+		p.i++
+	}
+	digits := func() {
+		for p.i < len(p.s) && p.s[p.i] >= '0' && p.s[p.i] <= '9' {
+			p.i++
+		}
+	}
+	digits()
+	if p.i < len(p.s) && p.s[p.i] == '.' {
+		p.i++
+		digits()
+	}
+	if p.i < len(p.s) && (p.s[p.i] == 'e' || p.s[p.i] == 'E') {
+		p.i++
+		if p.i < len(p.s) && (p.s[p.i] == '-' || p.s[p.i] == '+') {
+			p.i++
+		}
+		digits()
+	}
+	v, err := strconv.ParseFloat(p.s[start:p.i], 64)
+	if err != nil {
+		p.i = start
+		return 0, false
+	}
+	return v, true
+}
+
+// numbers reads n numbers, or reports false without consuming a partial set.
+func (p *pathScanner) numbers(n int) ([]float64, bool) {
+	start := p.i
+	values := make([]float64, n)
+	for i := range values {
+		v, ok := p.number()
+		if !ok {
+			p.i = start
+			return nil, false
+		}
+		values[i] = v
+	}
+	return values, true
+}
+
+// pathArgs is the number of arguments each supported command takes.
+func pathArgs(cmd byte) (int, bool) {
+	switch cmd | 0x20 {
+	case 'm', 'l', 't':
+		return 2, true
+	case 'h', 'v':
+		return 1, true
+	case 'c':
+		return 6, true
+	case 's', 'q':
+		return 4, true
+	case 'z':
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+// pathState tracks the pen while a path is added to a rasterizer.
+type pathState struct {
+	z              *vector.Rasterizer
+	pt             func(x, y float64) (float32, float32)
+	x, y           float64
+	startX, startY float64
+	// ctrlX and ctrlY are the last control point, reflected by S and T.
+	ctrlX, ctrlY float64
+	last         byte
+}
+
+// addSVGPath adds the path data d to z, mapping coordinates with pt. It
+// supports M, L, H, V, C, S, Q, T and Z in absolute and relative forms, and
+// reports false for arcs and malformed data.
+func addSVGPath(z *vector.Rasterizer, d string, pt func(x, y float64) (float32, float32)) bool {
+	scanner := &pathScanner{s: d}
+	state := &pathState{z: z, pt: pt}
+	cmd, ok := scanner.command()
+	if !ok || cmd|0x20 != 'm' {
+		return false
+	}
+	for {
+		n, supported := pathArgs(cmd)
+		if !supported {
+			return false
+		}
+		args, argsOK := scanner.numbers(n)
+		if !argsOK {
+			return false
+		}
+		state.apply(cmd, args)
+		if next, isCommand := scanner.command(); isCommand {
+			cmd = next
+			continue
+		}
+		scanner.skip()
+		if scanner.i >= len(scanner.s) {
+			return true
+		}
+		if n == 0 {
+			return false
+		}
+		// Repeated arguments continue the command; after a move they are lines.
+		switch cmd {
+		case 'M':
+			cmd = 'L'
+		case 'm':
+			cmd = 'l'
+		default:
+		}
+	}
+}
+
+//nolint:funlen // one case per path command
+func (s *pathState) apply(cmd byte, a []float64) {
+	relative := cmd >= 'a'
+	ox, oy := 0.0, 0.0
+	if relative {
+		ox, oy = s.x, s.y
+	}
+	// Control point reflected from the previous curve, or the current point.
+	reflect := func(curves string) (float64, float64) {
+		if strings.IndexByte(curves, s.last|0x20) >= 0 {
+			return 2*s.x - s.ctrlX, 2*s.y - s.ctrlY
+		}
+		return s.x, s.y
+	}
+	switch cmd | 0x20 {
+	case 'm':
+		s.x, s.y = ox+a[0], oy+a[1]
+		s.startX, s.startY = s.x, s.y
+		s.z.MoveTo(s.pt(s.x, s.y))
+	case 'l':
+		s.lineTo(ox+a[0], oy+a[1])
+	case 'h':
+		s.lineTo(ox+a[0], s.y)
+	case 'v':
+		s.lineTo(s.x, oy+a[0])
+	case 'c':
+		s.cubeTo(ox+a[0], oy+a[1], ox+a[2], oy+a[3], ox+a[4], oy+a[5])
+	case 's':
+		x1, y1 := reflect("cs")
+		s.cubeTo(x1, y1, ox+a[0], oy+a[1], ox+a[2], oy+a[3])
+	case 'q':
+		s.quadTo(ox+a[0], oy+a[1], ox+a[2], oy+a[3])
+	case 't':
+		x1, y1 := reflect("qt")
+		s.quadTo(x1, y1, ox+a[0], oy+a[1])
+	case 'z':
+		s.z.ClosePath()
+		s.x, s.y = s.startX, s.startY
+	default:
+	}
+	s.last = cmd
+}
+
+func (s *pathState) lineTo(x, y float64) {
+	s.x, s.y = x, y
+	s.z.LineTo(s.pt(x, y))
+}
+
+func (s *pathState) cubeTo(x1, y1, x2, y2, x, y float64) {
+	ax, ay := s.pt(x1, y1)
+	bx, by := s.pt(x2, y2)
+	cx, cy := s.pt(x, y)
+	s.z.CubeTo(ax, ay, bx, by, cx, cy)
+	s.ctrlX, s.ctrlY, s.x, s.y = x2, y2, x, y
+}
+
+func (s *pathState) quadTo(x1, y1, x, y float64) {
+	ax, ay := s.pt(x1, y1)
+	bx, by := s.pt(x, y)
+	s.z.QuadTo(ax, ay, bx, by)
+	s.ctrlX, s.ctrlY, s.x, s.y = x1, y1, x, y
+}
@@ -0,0 +1,42 @@
+package renderer
+
+import (
+	"testing"
+
+	"golang.org/x/image/vector"
+)
+
+func TestPathScannerNumbers(t *testing.T) {
+	scanner := &pathScanner{s: "1.5.5-2e1,3 -.25"}
+	want := []float64{1.5, 0.5, -20, 3, -0.25}
+	for _, w := range want {
+		got, ok := scanner.number()
+		if !ok || got != w {
+			t.Fatalf("expected %v, got %v (%v)", w, got, ok)
+		}
+	}
+	if _, ok := scanner.number(); ok {
+		t.Fatalf("expected end of input")
+	}
+}
+
+func TestAddSVGPath(t *testing.T) {
+	identity := func(x, y float64) (float32, float32) { return float32(x), float32(y) }
+	cases := []struct {
+		d  string
+		ok bool
+	}{
+		{"M7 2v11h3v9l7-12h-4l4-8z", true},
+		{"M12 2C6.48 2 2 6.48 2 12s4.48 10 10 10zm1 15h-2v-6h2v6z", true},
+		{"M0 0 10 0 10 10Q5 15 0 10T0 0Z", true},
+		{"M0 0A5 5 0 0 1 10 10", false},
+		{"L0 0", false},
+		{"M0 0L1", false},
+	}
+	for _, tc := range cases {
+		z := vector.NewRasterizer(24, 24)
+		if got := addSVGPath(z, tc.d, identity); got != tc.ok {
+			t.Fatalf("%q: expected %v, got %v", tc.d, tc.ok, got)
+		}
+	}
+}
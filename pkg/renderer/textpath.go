@@ -0,0 +1,198 @@
+package renderer
+
+import (
+	"math"
+	"strconv"
+
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// pointsPerInch converts font sizes in points to pixels at a given dpi.
+const pointsPerInch = 72
+
+// pathPrecision is the number of path units per pixel; coordinates are
+// rounded to hundredths of a pixel.
+const pathPrecision = 100
+
+// glyphOutline is the outline of one glyph as SVG path data. Commands are
+// relative, so the outline is placed anywhere by its first moveto alone.
+type glyphOutline struct {
+	// x and y start the first contour, in path units from the glyph origin.
+	x, y int
+	// d continues the path from there, e.g. "q1 -2 3 4l5 0z".
+	d string
+}
+
+// newGlyphOutline converts the contours of a loaded glyph, whose y axis
+// points down.
+func newGlyphOutline(segments sfnt.Segments) glyphOutline {
+	var o glyphOutline
+	var d []byte
+	started := false
+	// sx, sy start the current contour and px, py are the current point.
+	var sx, sy, px, py int
+	for i, seg := range segments {
+		// coords holds the points of seg relative to the current point.
+		var coords [6]int
+		for j, p := range seg.Args {
+			coords[2*j], coords[2*j+1] = pathUnits(p.X)-px, pathUnits(p.Y)-py
+		}
+		switch seg.Op {
+		case sfnt.SegmentOpMoveTo:
+			if !started {
+				o.x, o.y, started = coords[0], coords[1], true
+			} else {
+				// After z, the current point is the start of the closed contour.
+				d = append(d, 'z')
+				d = appendPathCommand(d, 'm', coords[0]+px-sx, coords[1]+py-sy)
+			}
+			px, py = px+coords[0], py+coords[1]
+			sx, sy = px, py
+		case sfnt.SegmentOpLineTo:
+			// z draws the line back to the start.
+			last := i == len(segments)-1 || segments[i+1].Op == sfnt.SegmentOpMoveTo
+			if !last || px+coords[0] != sx || py+coords[1] != sy {
+				d = appendPathCommand(d, 'l', coords[:2]...)
+			}
+			px, py = px+coords[0], py+coords[1]
+		case sfnt.SegmentOpQuadTo:
+			d = appendPathCommand(d, 'q', coords[:4]...)
+			px, py = px+coords[2], py+coords[3]
+		case sfnt.SegmentOpCubeTo:
+			d = appendPathCommand(d, 'c', coords[:6]...)
+			px, py = px+coords[4], py+coords[5]
+		}
+	}
+	if started {
+		d = append(d, 'z')
+	}
+	o.d = string(d)
+	return o
+}
+
+func pathUnits(v fixed.Int26_6) int {
+	return int(math.Round(fixedFloat(v) * pathPrecision))
+}
+
+// appendTo appends the outline with its origin at x on the baseline.
+func (o glyphOutline) appendTo(dst []byte, x float64) []byte {
+	if o.d == "" {
+		return dst
+	}
+	return append(appendPathCommand(dst, 'M', int(math.Round(x*pathPrecision))+o.x, o.y), o.d...)
+}
+
+// appendPathCommand appends an SVG path command with coordinates in path units.
+func appendPathCommand(dst []byte, command byte, coords ...int) []byte {
+	dst = append(dst, command)
+	for i, v := range coords {
+		if i > 0 && v >= 0 {
+			dst = append(dst, ' ')
+		}
+		dst = strconv.AppendFloat(dst, float64(v)/pathPrecision, 'f', -1, 64)
+	}
+	return dst
+}
+
+// outline returns the cached outline of r in the first face with its glyph.
+func (c *glyphChain) outline(r rune) glyphOutline {
+	if o, ok := c.outlines.Load(r); ok {
+		return o.(glyphOutline)
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	var o glyphOutline
+	if face := c.faces[faceFor(c.faces, r)]; face.outline != nil {
+		o = face.outline(r)
+	}
+	c.store(&c.outlines, r, o)
+	return o
+}
+
+// textPath returns the outlines of s as SVG path data, laid out as RenderPNG
+// draws text and centered on the origin, with the baseline at y = 0. Text is
+// compressed to textDx when it is non-zero, and bold text is emboldened by
+// repeating every glyph slightly offset, size being the font size.
+func (c *glyphChain) textPath(s string, bold bool, spacing, textDx, size float64) string {
+	type placed struct {
+		r rune
+		x float64
+	}
+	var glyphs []placed
+	x := 0.0
+	prev, prevFace := rune(-1), -1
+	for _, run := range bidiRuns(s) {
+		for _, r := range run {
+			g := c.glyph(r)
+			if g.face == prevFace {
+				x += fixedFloat(c.kern(g.face, prev, r))
+			}
+			glyphs = append(glyphs, placed{r: r, x: x})
+			step := fixedFloat(g.advance)
+			if bold {
+				step *= boldWidthFactor
+			}
+			x += step + spacing
+			prev, prevFace = r, g.face
+		}
+	}
+	width, ratio := x, 1.0
+	if textDx > 0 && x > 0 {
+		width, ratio = textDx, textDx/x
+	}
+	var d []byte
+	for _, g := range glyphs {
+		o := c.outline(g.r)
+		left := g.x*ratio - width/2
+		d = o.appendTo(d, left)
+		if bold {
+			d = o.appendTo(d, left+size*boldStrokeSize)
+		}
+	}
+	return string(d)
+}
+
+// outlineChain returns the font chain at size pixels for text paths.
+// Renderers without font outlines draw with Go Regular, as in PNG output.
+func (r *Renderer) outlineChain(size float64) (*glyphChain, error) {
+	text, ok := r.text.(*fontMeasurer)
+	if !ok || text.base.faces[0].outline == nil {
+		var err error
+		if text, err = r.raster(); err != nil {
+			return nil, err
+		}
+	}
+	return sizedChain(text, size), nil
+}
+
+// textPaths sets the SubjectPath and StatusPath of d, or the paths of its
+// segments, drawn as the style with the metrics m draws text.
+func (r *Renderer) textPaths(d *badgeTemplateData, m styleMetrics) error {
+	size := fontsize * m.fontScale
+	chain, err := r.outlineChain(size)
+	if err != nil {
+		return err
+	}
+	d.TextPaths = true
+	for i := range d.Segments {
+		s := &d.Segments[i]
+		s.Path = chain.textPath(s.Text, s.Bold, m.letterSpacing, s.Bounds.TextDx, size)
+	}
+	if len(d.Segments) > 0 {
+		return nil
+	}
+	d.SubjectPath = chain.textPath(d.Subject, m.boldSubject, m.letterSpacing, d.Bounds.SubjectTextDx, size)
+	d.StatusPath = chain.textPath(d.Status, m.boldStatus, m.letterSpacing, d.Bounds.StatusTextDx, size)
+	return nil
+}
+
+// SetTextPaths makes Render draw text as glyph outlines from the renderer
+// fonts instead of <text> elements, so badges look the same whether or not
+// the viewer has the fonts installed, and drops the cached SVGs. Renderers
+// without font files draw Go Regular outlines. Custom styles keep <text>
+// unless their templates use .TextPaths.
+func (r *Renderer) SetTextPaths(enabled bool) {
+	r.paths.Store(enabled)
+	r.cache.purge()
+}
@@ -0,0 +1,76 @@
+package renderer
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+func TestNewGlyphOutline(t *testing.T) {
+	pt := func(x, y float64) fixed.Point26_6 {
+		return fixed.Point26_6{X: fixed.Int26_6(x * 64), Y: fixed.Int26_6(y * 64)}
+	}
+	seg := func(op sfnt.SegmentOp, points ...fixed.Point26_6) sfnt.Segment {
+		s := sfnt.Segment{Op: op}
+		copy(s.Args[:], points)
+		return s
+	}
+	o := newGlyphOutline(sfnt.Segments{
+		// A square, closed by a line back to its start.
+		seg(sfnt.SegmentOpMoveTo, pt(4, 0)),
+		seg(sfnt.SegmentOpLineTo, pt(4, -4)),
+		seg(sfnt.SegmentOpLineTo, pt(0, -4)),
+		seg(sfnt.SegmentOpQuadTo, pt(0, 0), pt(4, 0)),
+		seg(sfnt.SegmentOpLineTo, pt(4, 0)),
+		// A CFF contour with a cubic curve.
+		seg(sfnt.SegmentOpMoveTo, pt(7, -3)),
+		seg(sfnt.SegmentOpCubeTo, pt(7, -4), pt(9, -4), pt(9, -3)),
+		seg(sfnt.SegmentOpQuadTo, pt(8, -1), pt(7, -3)),
+	})
+	if o.x != 400 || o.y != 0 {
+		t.Fatalf("expected the outline to start at its first moveto, got %d,%d", o.x, o.y)
+	}
+	const want = "l0-4l-4 0q0 4 4 4zm3-3c0-1 2-1 2 0q-1 2-2 0z"
+	if o.d != want {
+		t.Fatalf("expected %q, got %q", want, o.d)
+	}
+	z := vector.NewRasterizer(24, 24)
+	d := string(o.appendTo(nil, 1.5))
+	if d[:7] != "M5.5 0l" {
+		t.Fatalf("expected the outline placed at x 1.5, got %q", d)
+	}
+	if !addSVGPath(z, d, func(x, y float64) (float32, float32) { return float32(x), float32(y + 10) }) {
+		t.Fatalf("expected valid path data: %q", d)
+	}
+}
+
+func TestDefaultRendererMeasuresOutlineAdvances(t *testing.T) {
+	r, err := NewDefaultRenderer()
+	if err != nil {
+		t.Fatalf("new default renderer: %v", err)
+	}
+	const size = 11
+	chain, err := r.outlineChain(size)
+	if err != nil {
+		t.Fatalf("outline chain: %v", err)
+	}
+	for _, s := range []string{"passing", "build", "Wave AVAT", "coverage 97%"} {
+		// Outlines are placed by glyph advance and kerning, as in textPath.
+		advance := 0.0
+		prev, prevFace := rune(-1), -1
+		for _, c := range s {
+			g := chain.glyph(c)
+			if g.face == prevFace {
+				advance += fixedFloat(chain.kern(g.face, prev, c))
+			}
+			advance += fixedFloat(g.advance)
+			prev, prevFace = c, g.face
+		}
+		if got := r.MeasureText(s, size); math.Abs(got-advance) > 1e-9 {
+			t.Fatalf("%q: expected the measured width %v to match the outline advance %v", s, got, advance)
+		}
+	}
+}
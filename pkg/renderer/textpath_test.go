@@ -0,0 +1,91 @@
+package renderer_test
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/rhajizada/signum/pkg/renderer"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+var textPath = regexp.MustCompile(`<path transform="translate\([0-9.]+ [0-9]+\)" d="M[-0-9. Mmlqz]+"`)
+
+func TestRenderTextPaths(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "goregular.ttf")
+	if err := os.WriteFile(path, goregular.TTF, 0o600); err != nil {
+		t.Fatalf("write temp font: %v", err)
+	}
+	ttf, err := renderer.NewRenderer(path)
+	if err != nil {
+		t.Fatalf("new renderer: %v", err)
+	}
+	verdana, err := renderer.NewVerdanaRenderer()
+	if err != nil {
+		t.Fatalf("new renderer: %v", err)
+	}
+	for _, r := range []*renderer.Renderer{ttf, verdana, newRenderer(t)} {
+		r.SetTextPaths(true)
+		for _, style := range renderer.Styles() {
+			b := renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen, Style: style}
+			svg, renderErr := r.Render(b)
+			if renderErr != nil {
+				t.Fatalf("render %s: %v", style, renderErr)
+			}
+			if strings.Contains(string(svg), "<text") {
+				t.Fatalf("%s: expected no text elements: %s", style, svg)
+			}
+			if got := len(textPath.FindAll(svg, -1)); got < 2 {
+				t.Fatalf("%s: expected text paths, got %d: %s", style, got, svg)
+			}
+		}
+	}
+}
+
+func TestRenderTextPathsToggle(t *testing.T) {
+	r := newRenderer(t)
+	b := renderer.Badge{Subject: "build", Status: "passing", Color: renderer.ColorGreen, TextColor: "#333"}
+	text, err := r.Render(b)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	r.SetTextPaths(true)
+	paths, err := r.Render(b)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if string(paths) == string(text) || !strings.Contains(string(paths), `d="M`) {
+		t.Fatalf("expected cached text to be replaced by paths: %s", paths)
+	}
+	if !strings.Contains(string(paths), `fill="#333" class=`) && !strings.Contains(string(paths), `fill="#333"/>`) {
+		t.Fatalf("expected paths filled with the text color: %s", paths)
+	}
+	r.SetTextPaths(false)
+	again, err := r.Render(b)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if string(again) != string(text) {
+		t.Fatalf("expected text elements again: %s", again)
+	}
+}
+
+func TestRenderTextPathsCompressed(t *testing.T) {
+	r := newRenderer(t)
+	r.SetTextPaths(true)
+	b := renderer.Badge{Subject: "coverage", Status: "a very long status message", MaxWidth: 90, Overflow: renderer.OverflowShrink}
+	svg, err := r.Render(b)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	b.MaxWidth = 0
+	wide, err := r.Render(b)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if len(textPath.FindAll(svg, -1)) < 2 || string(svg) == string(wide) {
+		t.Fatalf("expected compressed text paths: %s", svg)
+	}
+}
@@ -0,0 +1,258 @@
+package renderer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultColorScale colors percentages such as coverage or pass rates.
+const DefaultColorScale = "<50 red, <80 yellow, else brightgreen"
+
+// ColorScale picks a badge color for a value from thresholds, as in
+// "<50 red, <80 yellow, else brightgreen". Steps are tried in order and the
+// first whose comparison holds wins. Reversed scales, where higher values are
+// worse such as latency, compare with > or >=: ">500 red, >200 yellow, else green".
+type ColorScale struct {
+	Steps []ColorStep
+	// Else colors values that match no step.
+	Else Color
+}
+
+// ColorStep colors the values for which Value Op Threshold holds.
+type ColorStep struct {
+	// Op is one of <, <=, > and >=.
+	Op        string
+	Threshold float64
+	Color     Color
+}
+
+// ParseColorScale parses comma-separated steps of a comparison, a threshold
+// and a color, followed by "else" and a color. Thresholds ascend with < and <=
+// and descend with > and >=; the two may not be mixed.
+func ParseColorScale(s string) (ColorScale, error) {
+	var scale ColorScale
+	parts := splitTopLevel(s, ',')
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if fields := strings.Fields(part); len(fields) > 1 && fields[0] == "else" && i == len(parts)-1 {
+			scale.Else = Color(strings.Join(fields[1:], " "))
+			if scale.Else == "" || !scale.Else.IsValid() {
+				return ColorScale{}, fmt.Errorf("invalid color scale color: %q", scale.Else)
+			}
+			return scale, scale.validate()
+		}
+		step, err := parseColorStep(part)
+		if err != nil {
+			return ColorScale{}, err
+		}
+		scale.Steps = append(scale.Steps, step)
+	}
+	return ColorScale{}, fmt.Errorf("color scale %q must end with an else color", s)
+}
+
+func parseColorStep(s string) (ColorStep, error) {
+	op := ""
+	for _, candidate := range []string{"<=", ">=", "<", ">"} {
+		if strings.HasPrefix(s, candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		return ColorStep{}, fmt.Errorf("invalid color scale step: %q", s)
+	}
+	fields := strings.Fields(s[len(op):])
+	if len(fields) < 2 {
+		return ColorStep{}, fmt.Errorf("invalid color scale step: %q", s)
+	}
+	threshold, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil || math.IsNaN(threshold) || math.IsInf(threshold, 0) {
+		return ColorStep{}, fmt.Errorf("invalid color scale threshold: %q", fields[0])
+	}
+	color := Color(strings.Join(fields[1:], " "))
+	if !color.IsValid() {
+		return ColorStep{}, fmt.Errorf("invalid color scale color: %q", color)
+	}
+	return ColorStep{Op: op, Threshold: threshold, Color: color}, nil
+}
+
+// validate checks that every step can match: thresholds run in the direction
+// of the comparisons, and a repeated threshold only moves from < to <=.
+func (s ColorScale) validate() error {
+	reversed := s.Reversed()
+	for i, step := range s.Steps {
+		if isReversedOp(step.Op) != reversed {
+			return errors.New("color scale mixes < and > comparisons")
+		}
+		if i == 0 {
+			continue
+		}
+		prev := s.Steps[i-1]
+		ordered := step.Threshold > prev.Threshold
+		if reversed {
+			ordered = step.Threshold < prev.Threshold
+		}
+		if !ordered && (step.Threshold != prev.Threshold || len(prev.Op) != 1 || len(step.Op) != 2) {
+			return fmt.Errorf("color scale step %q can never match", step.String())
+		}
+	}
+	return nil
+}
+
+// Reversed reports whether higher values take earlier steps.
+func (s ColorScale) Reversed() bool {
+	return len(s.Steps) > 0 && isReversedOp(s.Steps[0].Op)
+}
+
+func isReversedOp(op string) bool {
+	return strings.HasPrefix(op, ">")
+}
+
+// Color returns the color of the first step matching v, or Else.
+func (s ColorScale) Color(v float64) Color {
+	for _, step := range s.Steps {
+		if step.matches(v) {
+			return step.Color
+		}
+	}
+	return s.Else
+}
+
+func (s ColorStep) matches(v float64) bool {
+	switch s.Op {
+	case "<":
+		return v < s.Threshold
+	case "<=":
+		return v <= s.Threshold
+	case ">":
+		return v > s.Threshold
+	case ">=":
+		return v >= s.Threshold
+	default:
+		return false
+	}
+}
+
+func (s ColorStep) String() string {
+	return s.Op + strconv.FormatFloat(s.Threshold, 'g', -1, 64) + " " + string(s.Color)
+}
+
+// String returns the scale in the syntax read by ParseColorScale.
+func (s ColorScale) String() string {
+	parts := make([]string, 0, len(s.Steps)+1)
+	for _, step := range s.Steps {
+		parts = append(parts, step.String())
+	}
+	return strings.Join(append(parts, "else "+string(s.Else)), ", ")
+}
+
+// splitTopLevel splits s at sep outside parentheses, so rgb() colors stay whole.
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := range len(s) {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth = max(depth-1, 0)
+		case sep:
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		default:
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// FormatValue formats v as badge text. format is a printf-style pattern with
+// exactly one %f, %e, %g or %d verb, which may have a + flag and, except for
+// %d, a precision such as %.1f; %d rounds to the nearest integer and %% is a
+// literal percent sign. An empty format prints the shortest decimal form.
+func FormatValue(v float64, format string) (string, error) {
+	if format == "" {
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	}
+	pattern, round, err := valuePattern(format)
+	if err != nil {
+		return "", err
+	}
+	if round {
+		// Adding zero turns a rounded -0 into 0.
+		v = math.Round(v) + 0
+	}
+	return fmt.Sprintf(pattern, v), nil
+}
+
+// ValidValueFormat reports whether format is accepted by FormatValue.
+func ValidValueFormat(format string) bool {
+	if format == "" {
+		return true
+	}
+	_, _, err := valuePattern(format)
+	return err == nil
+}
+
+// maxValuePrecision bounds the precision digits of a value format.
+const maxValuePrecision = 2
+
+// valuePattern checks format and returns it as a fmt pattern for a single
+// float64, with %d rewritten as %.0f to be applied after rounding.
+func valuePattern(format string) (string, bool, error) {
+	invalid := fmt.Errorf("invalid value format: %q", format)
+	var b strings.Builder
+	verbs, round := 0, false
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			b.WriteByte(format[i])
+			continue
+		}
+		j := i + 1
+		if j < len(format) && format[j] == '%' {
+			b.WriteString("%%")
+			i = j
+			continue
+		}
+		if j < len(format) && format[j] == '+' {
+			j++
+		}
+		precision := false
+		if j < len(format) && format[j] == '.' {
+			j++
+			start := j
+			for j < len(format) && format[j] >= '0' && format[j] <= '9' {
+				j++
+			}
+			if j == start || j-start > maxValuePrecision {
+				return "", false, invalid
+			}
+			precision = true
+		}
+		if j >= len(format) {
+			return "", false, invalid
+		}
+		switch format[j] {
+		case 'f', 'e', 'g':
+			b.WriteString(format[i : j+1])
+		case 'd':
+			if precision {
+				return "", false, invalid
+			}
+			b.WriteString(format[i:j] + ".0f")
+			round = true
+		default:
+			return "", false, invalid
+		}
+		verbs++
+		i = j
+	}
+	if verbs != 1 {
+		return "", false, invalid
+	}
+	return b.String(), round, nil
+}
@@ -0,0 +1,120 @@
+package renderer_test
+
+import (
+	"testing"
+
+	"github.com/rhajizada/signum/pkg/renderer"
+)
+
+func TestParseColorScale(t *testing.T) {
+	scale, err := renderer.ParseColorScale(renderer.DefaultColorScale)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	cases := map[float64]renderer.Color{
+		0:    renderer.ColorRed,
+		49.9: renderer.ColorRed,
+		50:   renderer.ColorYellow,
+		79:   renderer.ColorYellow,
+		80:   renderer.ColorBrightgreen,
+		100:  renderer.ColorBrightgreen,
+	}
+	for value, want := range cases {
+		if got := scale.Color(value); got != want {
+			t.Fatalf("%v: expected %s, got %s", value, want, got)
+		}
+	}
+	if scale.Reversed() {
+		t.Fatalf("expected an ascending scale")
+	}
+	if scale.String() != renderer.DefaultColorScale {
+		t.Fatalf("expected %q, got %q", renderer.DefaultColorScale, scale.String())
+	}
+}
+
+func TestParseColorScaleReversed(t *testing.T) {
+	scale, err := renderer.ParseColorScale(" >= 500 red,>200 rgb(255, 200, 0) , else  green")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !scale.Reversed() {
+		t.Fatalf("expected a reversed scale")
+	}
+	cases := map[float64]renderer.Color{
+		900: renderer.ColorRed,
+		500: renderer.ColorRed,
+		300: "rgb(255, 200, 0)",
+		200: renderer.ColorGreen,
+	}
+	for value, want := range cases {
+		if got := scale.Color(value); got != want {
+			t.Fatalf("%v: expected %s, got %s", value, want, got)
+		}
+	}
+	if got := scale.String(); got != ">=500 red, >200 rgb(255, 200, 0), else green" {
+		t.Fatalf("unexpected canonical form %q", got)
+	}
+}
+
+func TestParseColorScaleInvalid(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"<50 red",
+		"else green, <50 red",
+		"<50 red, >80 yellow, else green",
+		"<80 red, <50 yellow, else green",
+		"<50 red, <50 yellow, else green",
+		"<=50 red, <50 yellow, else green",
+		"=50 red, else green",
+		"<abc red, else green",
+		"<NaN red, else green",
+		"<50 notacolor, else green",
+		"<50, else green",
+		"<50 red, else notacolor",
+		"<50 red, else",
+	} {
+		if _, err := renderer.ParseColorScale(input); err == nil {
+			t.Fatalf("%q: expected error", input)
+		}
+	}
+	if _, err := renderer.ParseColorScale("<50 red, <=50 orange, else green"); err != nil {
+		t.Fatalf("expected < then <= at one threshold to be valid: %v", err)
+	}
+	if _, err := renderer.ParseColorScale("else blue"); err != nil {
+		t.Fatalf("expected a constant scale to be valid: %v", err)
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	cases := []struct {
+		value  float64
+		format string
+		want   string
+	}{
+		{87.3, "", "87.3"},
+		{87.25, "%.1f", "87.2"},
+		{87.5, "%d", "88"},
+		{-0.4, "%d", "0"},
+		{3, "%+d", "+3"},
+		{1234.5, "%g", "1234.5"},
+		{0.5, "%.0f%%", "0%"},
+		{12, "v%d.x", "v12.x"},
+	}
+	for _, tc := range cases {
+		got, err := renderer.FormatValue(tc.value, tc.format)
+		if err != nil {
+			t.Fatalf("%v %q: %v", tc.value, tc.format, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%v %q: expected %q, got %q", tc.value, tc.format, tc.want, got)
+		}
+	}
+	for _, format := range []string{"%", "%s", "%x", "%.1d", "%f %f", "plain", "%.123f", "%5f", "%."} {
+		if renderer.ValidValueFormat(format) {
+			t.Fatalf("%q: expected invalid format", format)
+		}
+		if _, err := renderer.FormatValue(1, format); err == nil {
+			t.Fatalf("%q: expected error", format)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package renderer
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Advance widths of Verdana at 11px, stored as [lower, upper, width] rune ranges
+// in the same layout shields' badge-maker uses.
+//
+//go:embed widths/*.json
+var widthsFS embed.FS
+
+// fallbackRune is measured in place of runes missing from a width table.
+const fallbackRune = 'm'
+
+// measurer returns the advance width of s in pixels at font size size.
+type measurer interface {
+	measure(s string, bold bool, size float64) float64
+}
+
+type widthRange struct {
+	lower rune
+	upper rune
+	width float64
+}
+
+// widthTable is a sorted list of non-overlapping rune ranges.
+type widthTable struct {
+	ranges   []widthRange
+	fallback float64
+}
+
+func loadWidthTable(name string) (*widthTable, error) {
+	data, err := widthsFS.ReadFile("widths/" + name)
+	if err != nil {
+		return nil, err
+	}
+	var raw [][3]float64
+	if err = json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse width table %s: %w", name, err)
+	}
+	table := &widthTable{ranges: make([]widthRange, 0, len(raw))}
+	for _, entry := range raw {
+		table.ranges = append(table.ranges, widthRange{
+			lower: rune(entry[0]),
+			upper: rune(entry[1]),
+			width: entry[2],
+		})
+	}
+	sort.Slice(table.ranges, func(i, j int) bool {
+		return table.ranges[i].lower < table.ranges[j].lower
+	})
+	fallback, ok := table.lookup(fallbackRune)
+	if !ok {
+		return nil, fmt.Errorf("width table %s has no entry for %q", name, fallbackRune)
+	}
+	table.fallback = fallback
+	return table, nil
+}
+
+func (t *widthTable) lookup(r rune) (float64, bool) {
+	i := sort.Search(len(t.ranges), func(i int) bool {
+		return t.ranges[i].upper >= r
+	})
+	if i < len(t.ranges) && t.ranges[i].lower <= r {
+		return t.ranges[i].width, true
+	}
+	return 0, false
+}
+
+func (t *widthTable) measure(s string) float64 {
+	width := 0.0
+	for _, r := range s {
+		w, ok := t.lookup(r)
+		if !ok {
+			w = t.fallback
+		}
+		width += w
+	}
+	return width
+}
+
+// verdanaMeasurer measures text with the embedded Verdana width tables.
+type verdanaMeasurer struct {
+	normal *widthTable
+	bold   *widthTable
+}
+
+func newVerdanaMeasurer() (*verdanaMeasurer, error) {
+	normal, err := loadWidthTable("verdana-11px-normal.json")
+	if err != nil {
+		return nil, err
+	}
+	bold, err := loadWidthTable("verdana-11px-bold.json")
+	if err != nil {
+		return nil, err
+	}
+	return &verdanaMeasurer{normal: normal, bold: bold}, nil
+}
+
+func (m *verdanaMeasurer) measure(s string, bold bool, size float64) float64 {
+	table := m.normal
+	if bold {
+		table = m.bold
+	}
+	return table.measure(s) * size / fontsize
+}